@@ -0,0 +1,40 @@
+package apikey
+
+import (
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// ApiKeyListReq 获取API密钥列表请求
+type ApiKeyListReq struct {
+	g.Meta `path:"/api-keys" method:"get" summary:"获取API密钥列表" tags:"API密钥"`
+}
+
+// ApiKeyListRes 获取API密钥列表响应
+type ApiKeyListRes struct {
+	List []*sysout.ApiKeyModel `json:"list" description:"API密钥列表"`
+}
+
+// ApiKeyCreateReq 创建API密钥请求
+type ApiKeyCreateReq struct {
+	g.Meta `path:"/api-keys/create" method:"post" summary:"创建API密钥" tags:"API密钥"`
+	sysin.ApiKeyCreateInp
+}
+
+// ApiKeyCreateRes 创建API密钥响应
+type ApiKeyCreateRes struct {
+	*sysout.ApiKeyCreateModel
+}
+
+// ApiKeyRevokeReq 撤销API密钥请求
+type ApiKeyRevokeReq struct {
+	g.Meta `path:"/api-keys/revoke" method:"post" summary:"撤销API密钥" tags:"API密钥"`
+	sysin.ApiKeyRevokeInp
+}
+
+// ApiKeyRevokeRes 撤销API密钥响应
+type ApiKeyRevokeRes struct {
+	Success bool   `json:"success" description:"是否成功"`
+	Message string `json:"message" description:"提示信息"`
+}
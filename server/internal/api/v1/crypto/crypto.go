@@ -0,0 +1,17 @@
+package crypto
+
+import (
+	"client-app/internal/model/output/sysout"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// PublicKeyReq 获取传输加密公钥请求
+type PublicKeyReq struct {
+	g.Meta `path:"/crypto/public-key" method:"get" summary:"获取传输加密公钥" tags:"公开接口"`
+}
+
+// PublicKeyRes 获取传输加密公钥响应
+type PublicKeyRes struct {
+	*sysout.PublicKeyModel
+}
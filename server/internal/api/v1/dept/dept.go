@@ -0,0 +1,87 @@
+package dept
+
+import (
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// DeptTreeReq 部门树请求
+type DeptTreeReq struct {
+	g.Meta `path:"/dept/tree" method:"get" perm:"dept:tree" summary:"获取部门树" tags:"部门管理"`
+	sysin.DeptTreeInp
+}
+
+// DeptTreeRes 部门树响应
+type DeptTreeRes struct {
+	List []*sysout.DeptTreeModel `json:"list" description:"部门树"`
+}
+
+// DeptDetailReq 部门详情请求
+type DeptDetailReq struct {
+	g.Meta `path:"/dept/detail" method:"get" perm:"dept:detail" summary:"获取部门详情" tags:"部门管理"`
+	sysin.DeptDetailInp
+}
+
+// DeptDetailRes 部门详情响应
+type DeptDetailRes struct {
+	*sysout.DeptDetailModel
+}
+
+// CreateDeptReq 创建部门请求
+type CreateDeptReq struct {
+	g.Meta `path:"/dept/create" method:"post" perm:"dept:create" summary:"创建部门" tags:"部门管理"`
+	sysin.CreateDeptInp
+}
+
+// CreateDeptRes 创建部门响应
+type CreateDeptRes struct {
+	*sysout.DeptModel
+}
+
+// UpdateDeptReq 更新部门请求
+type UpdateDeptReq struct {
+	g.Meta `path:"/dept/update" method:"put" perm:"dept:update" summary:"更新部门" tags:"部门管理"`
+	sysin.UpdateDeptInp
+}
+
+// UpdateDeptRes 更新部门响应
+type UpdateDeptRes struct {
+	*sysout.DeptModel
+}
+
+// MoveDeptReq 移动部门请求
+type MoveDeptReq struct {
+	g.Meta `path:"/dept/move" method:"put" perm:"dept:move" summary:"移动部门" tags:"部门管理"`
+	sysin.MoveDeptInp
+}
+
+// MoveDeptRes 移动部门响应
+type MoveDeptRes struct{}
+
+// UpdateDeptStatusReq 修改部门状态请求
+type UpdateDeptStatusReq struct {
+	g.Meta `path:"/dept/status" method:"put" perm:"dept:status" summary:"修改部门状态" tags:"部门管理"`
+	sysin.UpdateDeptStatusInp
+}
+
+// UpdateDeptStatusRes 修改部门状态响应
+type UpdateDeptStatusRes struct{}
+
+// DeleteDeptReq 删除部门请求
+type DeleteDeptReq struct {
+	g.Meta `path:"/dept/delete" method:"delete" perm:"dept:delete" summary:"删除部门" tags:"部门管理"`
+	sysin.DeleteDeptInp
+}
+
+// DeleteDeptRes 删除部门响应
+type DeleteDeptRes struct{}
+
+// MoveDeptUsersReq 调整用户部门请求
+type MoveDeptUsersReq struct {
+	g.Meta `path:"/dept/users/move" method:"put" perm:"dept:users:move" summary:"调整用户所属部门" tags:"部门管理"`
+	sysin.MoveDeptUsersInp
+}
+
+// MoveDeptUsersRes 调整用户部门响应
+type MoveDeptUsersRes struct{}
@@ -9,7 +9,7 @@ import (
 
 // MenuListReq 菜单列表查询请求
 type MenuListReq struct {
-	g.Meta `path:"/menu/list" method:"GET" summary:"获取菜单列表" tags:"菜单管理"`
+	g.Meta `path:"/menu/list" method:"GET" perm:"menu:list" summary:"获取菜单列表" tags:"菜单管理"`
 	sysin.MenuListInp
 }
 
@@ -20,7 +20,7 @@ type MenuListRes struct {
 
 // MenuTreeReq 菜单树查询请求
 type MenuTreeReq struct {
-	g.Meta `path:"/menu/tree" method:"GET" summary:"获取菜单树" tags:"菜单管理"`
+	g.Meta `path:"/menu/tree" method:"GET" perm:"menu:tree" summary:"获取菜单树" tags:"菜单管理"`
 	sysin.MenuTreeInp
 }
 
@@ -31,7 +31,7 @@ type MenuTreeRes struct {
 
 // MenuDetailReq 菜单详情查询请求
 type MenuDetailReq struct {
-	g.Meta `path:"/menu/{id}" method:"GET" summary:"获取菜单详情" tags:"菜单管理"`
+	g.Meta `path:"/menu/{id}" method:"GET" perm:"menu:detail" summary:"获取菜单详情" tags:"菜单管理"`
 	sysin.MenuDetailInp
 }
 
@@ -42,7 +42,7 @@ type MenuDetailRes struct {
 
 // CreateMenuReq 创建菜单请求
 type CreateMenuReq struct {
-	g.Meta `path:"/menu" method:"POST" summary:"创建菜单" tags:"菜单管理"`
+	g.Meta `path:"/menu" method:"POST" perm:"menu:create" summary:"创建菜单" tags:"菜单管理"`
 	sysin.CreateMenuInp
 }
 
@@ -53,7 +53,7 @@ type CreateMenuRes struct {
 
 // UpdateMenuReq 更新菜单请求
 type UpdateMenuReq struct {
-	g.Meta `path:"/menu/{id}" method:"PUT" summary:"更新菜单" tags:"菜单管理"`
+	g.Meta `path:"/menu/{id}" method:"PUT" perm:"menu:update" summary:"更新菜单" tags:"菜单管理"`
 	sysin.UpdateMenuInp
 }
 
@@ -64,7 +64,7 @@ type UpdateMenuRes struct {
 
 // DeleteMenuReq 删除菜单请求
 type DeleteMenuReq struct {
-	g.Meta `path:"/menu/{id}" method:"DELETE" summary:"删除菜单" tags:"菜单管理"`
+	g.Meta `path:"/menu/{id}" method:"DELETE" perm:"menu:delete" summary:"删除菜单" tags:"菜单管理"`
 	sysin.DeleteMenuInp
 }
 
@@ -76,7 +76,7 @@ type DeleteMenuRes struct {
 
 // UpdateMenuStatusReq 更新菜单状态请求
 type UpdateMenuStatusReq struct {
-	g.Meta `path:"/menu/{id}/status" method:"PUT" summary:"更新菜单状态" tags:"菜单管理"`
+	g.Meta `path:"/menu/{id}/status" method:"PUT" perm:"menu:status" summary:"更新菜单状态" tags:"菜单管理"`
 	sysin.UpdateMenuStatusInp
 }
 
@@ -88,7 +88,7 @@ type UpdateMenuStatusRes struct {
 
 // BatchDeleteMenuReq 批量删除菜单请求
 type BatchDeleteMenuReq struct {
-	g.Meta `path:"/menu/batch/delete" method:"DELETE" summary:"批量删除菜单" tags:"菜单管理"`
+	g.Meta `path:"/menu/batch/delete" method:"DELETE" perm:"menu:delete" summary:"批量删除菜单" tags:"菜单管理"`
 	sysin.BatchDeleteMenuInp
 }
 
@@ -100,7 +100,7 @@ type BatchDeleteMenuRes struct {
 
 // MenuOptionsReq 菜单选项请求
 type MenuOptionsReq struct {
-	g.Meta `path:"/menu/options" method:"GET" summary:"获取菜单选项" tags:"菜单管理"`
+	g.Meta `path:"/menu/options" method:"GET" perm:"menu:options" summary:"获取菜单选项" tags:"菜单管理"`
 	sysin.MenuOptionInp
 }
 
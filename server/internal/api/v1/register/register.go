@@ -0,0 +1,30 @@
+package register
+
+import (
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// UserRegisterReq 用户注册请求
+type UserRegisterReq struct {
+	g.Meta `path:"/register" method:"post" summary:"用户注册" tags:"用户注册"`
+	sysin.UserRegisterInp
+}
+
+// UserRegisterRes 用户注册响应
+type UserRegisterRes struct {
+	*sysout.UserRegisterModel
+}
+
+// VerifyEmailReq 邮箱验证请求
+type VerifyEmailReq struct {
+	g.Meta `path:"/register/verify-email" method:"post" summary:"验证邮箱" tags:"用户注册"`
+	sysin.VerifyEmailInp
+}
+
+// VerifyEmailRes 邮箱验证响应
+type VerifyEmailRes struct {
+	Success bool   `json:"success" description:"是否成功"`
+	Message string `json:"message" description:"提示信息"`
+}
@@ -9,7 +9,7 @@ import (
 
 // RoleMenuReq 获取角色菜单权限请求
 type RoleMenuReq struct {
-	g.Meta `path:"/role/{id}/menus" method:"GET" summary:"获取角色菜单权限" tags:"角色权限"`
+	g.Meta `path:"/role/{id}/menus" method:"GET" perm:"role:menus" summary:"获取角色菜单权限" tags:"角色权限"`
 	sysin.RoleMenuInp
 }
 
@@ -20,7 +20,7 @@ type RoleMenuRes struct {
 
 // UpdateRoleMenuReq 更新角色菜单权限请求
 type UpdateRoleMenuReq struct {
-	g.Meta `path:"/role/{id}/menus" method:"PUT" summary:"更新角色菜单权限" tags:"角色权限"`
+	g.Meta `path:"/role/{id}/menus" method:"PUT" perm:"role:menus:update" summary:"更新角色菜单权限" tags:"角色权限"`
 	sysin.UpdateRoleMenuInp
 }
 
@@ -32,7 +32,7 @@ type UpdateRoleMenuRes struct {
 
 // RolePermissionReq 获取角色权限详情请求
 type RolePermissionReq struct {
-	g.Meta `path:"/role/{id}/permissions" method:"GET" summary:"获取角色权限详情" tags:"角色权限"`
+	g.Meta `path:"/role/{id}/permissions" method:"GET" perm:"role:permissions" summary:"获取角色权限详情" tags:"角色权限"`
 	sysin.RolePermissionInp
 }
 
@@ -40,3 +40,26 @@ type RolePermissionReq struct {
 type RolePermissionRes struct {
 	*sysout.RolePermissionModel
 }
+
+// RoleDataScopeReq 获取角色数据权限请求
+type RoleDataScopeReq struct {
+	g.Meta `path:"/role/{id}/data-scope" method:"GET" perm:"role:data-scope" summary:"获取角色数据权限" tags:"角色权限"`
+	sysin.RoleDataScopeInp
+}
+
+// RoleDataScopeRes 获取角色数据权限响应
+type RoleDataScopeRes struct {
+	*sysout.RoleDataScopeModel
+}
+
+// UpdateRoleDataScopeReq 更新角色数据权限请求
+type UpdateRoleDataScopeReq struct {
+	g.Meta `path:"/role/{id}/data-scope" method:"PUT" perm:"role:data-scope:update" summary:"更新角色数据权限" tags:"角色权限"`
+	sysin.UpdateRoleDataScopeInp
+}
+
+// UpdateRoleDataScopeRes 更新角色数据权限响应
+type UpdateRoleDataScopeRes struct {
+	Success bool   `json:"success" description:"是否成功"`
+	Message string `json:"message" description:"提示信息"`
+}
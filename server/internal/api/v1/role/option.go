@@ -9,7 +9,7 @@ import (
 
 // RoleOptionReq 获取角色选项请求
 type RoleOptionReq struct {
-	g.Meta `path:"/role/options" method:"GET" summary:"获取角色选项" tags:"角色选项"`
+	g.Meta `path:"/role/options" method:"GET" perm:"role:options" summary:"获取角色选项" tags:"角色选项"`
 	sysin.RoleOptionInp
 }
 
@@ -20,7 +20,7 @@ type RoleOptionRes struct {
 
 // RoleStatsReq 获取角色统计请求
 type RoleStatsReq struct {
-	g.Meta `path:"/role/stats" method:"GET" summary:"获取角色统计" tags:"角色统计"`
+	g.Meta `path:"/role/stats" method:"GET" perm:"role:stats" summary:"获取角色统计" tags:"角色统计"`
 }
 
 // RoleStatsRes 获取角色统计响应
@@ -30,7 +30,7 @@ type RoleStatsRes struct {
 
 // DataScopeOptionReq 获取数据权限范围选项请求
 type DataScopeOptionReq struct {
-	g.Meta `path:"/role/data-scope-options" method:"GET" summary:"获取数据权限范围选项" tags:"角色选项"`
+	g.Meta `path:"/role/data-scope-options" method:"GET" perm:"role:options" summary:"获取数据权限范围选项" tags:"角色选项"`
 }
 
 // DataScopeOptionRes 获取数据权限范围选项响应
@@ -9,7 +9,7 @@ import (
 
 // RoleListReq 角色列表请求
 type RoleListReq struct {
-	g.Meta `path:"/role/list" method:"GET" summary:"获取角色列表" tags:"角色管理"`
+	g.Meta `path:"/role/list" method:"GET" perm:"role:list" summary:"获取角色列表" tags:"角色管理"`
 	sysin.RoleListInp
 }
 
@@ -20,7 +20,7 @@ type RoleListRes struct {
 
 // RoleDetailReq 角色详情请求
 type RoleDetailReq struct {
-	g.Meta `path:"/role/{id}" method:"GET" summary:"获取角色详情" tags:"角色管理"`
+	g.Meta `path:"/role/{id}" method:"GET" perm:"role:detail" summary:"获取角色详情" tags:"角色管理"`
 	sysin.RoleDetailInp
 }
 
@@ -31,7 +31,7 @@ type RoleDetailRes struct {
 
 // CreateRoleReq 创建角色请求
 type CreateRoleReq struct {
-	g.Meta `path:"/role" method:"POST" summary:"创建角色" tags:"角色管理"`
+	g.Meta `path:"/role" method:"POST" perm:"role:create" summary:"创建角色" tags:"角色管理"`
 	sysin.CreateRoleInp
 }
 
@@ -42,7 +42,7 @@ type CreateRoleRes struct {
 
 // UpdateRoleReq 更新角色请求
 type UpdateRoleReq struct {
-	g.Meta `path:"/role/{id}" method:"PUT" summary:"更新角色" tags:"角色管理"`
+	g.Meta `path:"/role/{id}" method:"PUT" perm:"role:update" summary:"更新角色" tags:"角色管理"`
 	sysin.UpdateRoleInp
 }
 
@@ -53,7 +53,7 @@ type UpdateRoleRes struct {
 
 // DeleteRoleReq 删除角色请求
 type DeleteRoleReq struct {
-	g.Meta `path:"/role/{id}" method:"DELETE" summary:"删除角色" tags:"角色管理"`
+	g.Meta `path:"/role/{id}" method:"DELETE" perm:"role:delete" summary:"删除角色" tags:"角色管理"`
 	sysin.DeleteRoleInp
 }
 
@@ -65,7 +65,7 @@ type DeleteRoleRes struct {
 
 // BatchDeleteRoleReq 批量删除角色请求
 type BatchDeleteRoleReq struct {
-	g.Meta `path:"/role/batch" method:"DELETE" summary:"批量删除角色" tags:"角色管理"`
+	g.Meta `path:"/role/batch" method:"DELETE" perm:"role:delete" summary:"批量删除角色" tags:"角色管理"`
 	sysin.BatchDeleteRoleInp
 }
 
@@ -77,7 +77,7 @@ type BatchDeleteRoleRes struct {
 
 // UpdateRoleStatusReq 更新角色状态请求
 type UpdateRoleStatusReq struct {
-	g.Meta `path:"/role/{id}/status" method:"PUT" summary:"更新角色状态" tags:"角色管理"`
+	g.Meta `path:"/role/{id}/status" method:"PUT" perm:"role:status" summary:"更新角色状态" tags:"角色管理"`
 	sysin.UpdateRoleStatusInp
 }
 
@@ -89,7 +89,7 @@ type UpdateRoleStatusRes struct {
 
 // CopyRoleReq 复制角色请求
 type CopyRoleReq struct {
-	g.Meta `path:"/role/{id}/copy" method:"POST" summary:"复制角色" tags:"角色管理"`
+	g.Meta `path:"/role/{id}/copy" method:"POST" perm:"role:copy" summary:"复制角色" tags:"角色管理"`
 	sysin.CopyRoleInp
 }
 
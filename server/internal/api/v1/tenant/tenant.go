@@ -8,7 +8,7 @@ import (
 
 // 租户列表请求
 type TenantListReq struct {
-	g.Meta `path:"/tenant/list" method:"get" summary:"获取租户列表" tags:"租户管理"`
+	g.Meta `path:"/tenant/list" method:"get" perm:"tenant:list" summary:"获取租户列表" tags:"租户管理"`
 	sysin.TenantListInp
 }
 
@@ -18,7 +18,7 @@ type TenantListRes struct {
 
 // 创建租户请求
 type CreateTenantReq struct {
-	g.Meta `path:"/tenant/create" method:"post" summary:"创建租户" tags:"租户管理"`
+	g.Meta `path:"/tenant/create" method:"post" perm:"tenant:create" summary:"创建租户" tags:"租户管理"`
 	sysin.CreateTenantInp
 }
 
@@ -28,7 +28,7 @@ type CreateTenantRes struct {
 
 // 更新租户请求
 type UpdateTenantReq struct {
-	g.Meta `path:"/tenant/update" method:"put" summary:"更新租户" tags:"租户管理"`
+	g.Meta `path:"/tenant/update" method:"put" perm:"tenant:update" summary:"更新租户" tags:"租户管理"`
 	sysin.UpdateTenantInp
 }
 
@@ -38,7 +38,7 @@ type UpdateTenantRes struct {
 
 // 删除租户请求
 type DeleteTenantReq struct {
-	g.Meta `path:"/tenant/delete" method:"delete" summary:"删除租户" tags:"租户管理"`
+	g.Meta `path:"/tenant/delete" method:"delete" perm:"tenant:delete" summary:"删除租户" tags:"租户管理"`
 	sysin.DeleteTenantInp
 }
 
@@ -46,7 +46,7 @@ type DeleteTenantRes struct{}
 
 // 获取租户详情请求
 type TenantDetailReq struct {
-	g.Meta `path:"/tenant/detail" method:"get" summary:"获取租户详情" tags:"租户管理"`
+	g.Meta `path:"/tenant/detail" method:"get" perm:"tenant:detail" summary:"获取租户详情" tags:"租户管理"`
 	sysin.TenantDetailInp
 }
 
@@ -56,7 +56,7 @@ type TenantDetailRes struct {
 
 // 更新租户状态请求
 type TenantStatusReq struct {
-	g.Meta `path:"/tenant/status" method:"put" summary:"更新租户状态" tags:"租户管理"`
+	g.Meta `path:"/tenant/status" method:"put" perm:"tenant:status" summary:"更新租户状态" tags:"租户管理"`
 	sysin.TenantStatusInp
 }
 
@@ -64,7 +64,7 @@ type TenantStatusRes struct{}
 
 // 获取租户统计请求
 type TenantStatsReq struct {
-	g.Meta `path:"/tenant/stats" method:"get" summary:"获取租户统计" tags:"租户管理"`
+	g.Meta `path:"/tenant/stats" method:"get" perm:"tenant:stats" summary:"获取租户统计" tags:"租户管理"`
 	sysin.TenantStatsInp
 }
 
@@ -74,7 +74,7 @@ type TenantStatsRes struct {
 
 // 更新租户配置请求
 type TenantConfigReq struct {
-	g.Meta `path:"/tenant/config" method:"put" summary:"更新租户配置" tags:"租户管理"`
+	g.Meta `path:"/tenant/config" method:"put" perm:"tenant:config" summary:"更新租户配置" tags:"租户管理"`
 	sysin.TenantConfigInp
 }
 
@@ -82,9 +82,29 @@ type TenantConfigRes struct{}
 
 // 获取租户选项请求
 type TenantOptionsReq struct {
-	g.Meta `path:"/tenant/options" method:"get" summary:"获取租户选项" tags:"租户管理"`
+	g.Meta `path:"/tenant/options" method:"get" perm:"tenant:options" summary:"获取租户选项" tags:"租户管理"`
 }
 
 type TenantOptionsRes struct {
 	*sysout.TenantOptionsModel
 }
+
+// 创建注册邀请码请求
+type InviteCodeCreateReq struct {
+	g.Meta `path:"/tenant/invite-code/create" method:"post" perm:"tenant:invite-code:create" summary:"创建注册邀请码" tags:"租户管理"`
+	sysin.InviteCodeCreateInp
+}
+
+type InviteCodeCreateRes struct {
+	*sysout.InviteCodeModel
+}
+
+// 注册邀请码列表请求
+type InviteCodeListReq struct {
+	g.Meta `path:"/tenant/invite-code/list" method:"get" perm:"tenant:invite-code:list" summary:"获取注册邀请码列表" tags:"租户管理"`
+	sysin.InviteCodeListInp
+}
+
+type InviteCodeListRes struct {
+	*sysout.InviteCodeListModel
+}
@@ -0,0 +1,107 @@
+package user
+
+import (
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// UserListReq 用户列表请求
+type UserListReq struct {
+	g.Meta `path:"/user/list" method:"get" perm:"user:list" summary:"获取用户列表" tags:"用户管理"`
+	sysin.UserListInp
+}
+
+// UserListRes 用户列表响应
+type UserListRes struct {
+	*sysout.UserListModel
+}
+
+// UserDetailReq 用户详情请求
+type UserDetailReq struct {
+	g.Meta `path:"/user/detail" method:"get" perm:"user:detail" summary:"获取用户详情" tags:"用户管理"`
+	sysin.UserDetailInp
+}
+
+// UserDetailRes 用户详情响应
+type UserDetailRes struct {
+	*sysout.UserDetailModel
+}
+
+// CreateUserReq 创建用户请求
+type CreateUserReq struct {
+	g.Meta `path:"/user/create" method:"post" perm:"user:create" summary:"创建用户" tags:"用户管理"`
+	sysin.CreateUserInp
+}
+
+// CreateUserRes 创建用户响应
+type CreateUserRes struct {
+	*sysout.UserModel
+}
+
+// UpdateUserReq 更新用户请求
+type UpdateUserReq struct {
+	g.Meta `path:"/user/update" method:"put" perm:"user:update" summary:"更新用户" tags:"用户管理"`
+	sysin.UpdateUserInp
+}
+
+// UpdateUserRes 更新用户响应
+type UpdateUserRes struct {
+	*sysout.UserModel
+}
+
+// UpdateUserStatusReq 修改用户状态请求
+type UpdateUserStatusReq struct {
+	g.Meta `path:"/user/status" method:"put" perm:"user:status" summary:"修改用户状态" tags:"用户管理"`
+	sysin.UpdateUserStatusInp
+}
+
+// UpdateUserStatusRes 修改用户状态响应
+type UpdateUserStatusRes struct{}
+
+// ResetUserPasswordReq 重置用户密码请求
+type ResetUserPasswordReq struct {
+	g.Meta `path:"/user/reset-password" method:"post" perm:"user:reset-password" summary:"重置用户密码" tags:"用户管理"`
+	sysin.ResetPasswordInp
+}
+
+// ResetUserPasswordRes 重置用户密码响应
+type ResetUserPasswordRes struct{}
+
+// DeleteUserReq 删除用户请求
+type DeleteUserReq struct {
+	g.Meta `path:"/user/delete" method:"delete" perm:"user:delete" summary:"删除用户" tags:"用户管理"`
+	sysin.DeleteUserInp
+}
+
+// DeleteUserRes 删除用户响应
+type DeleteUserRes struct{}
+
+// AssignUserRolesReq 分配用户角色请求
+type AssignUserRolesReq struct {
+	g.Meta `path:"/user/roles" method:"put" perm:"user:roles" summary:"分配用户角色" tags:"用户管理"`
+	sysin.AssignUserRoleInp
+}
+
+// AssignUserRolesRes 分配用户角色响应
+type AssignUserRolesRes struct{}
+
+// ImportUserReq 批量导入用户请求
+type ImportUserReq struct {
+	g.Meta `path:"/user/import" method:"post" perm:"user:import" mime:"multipart/form-data" summary:"批量导入用户" tags:"用户管理"`
+	sysin.ImportUserInp
+}
+
+// ImportUserRes 批量导入用户响应
+type ImportUserRes struct {
+	*sysout.UserImportModel
+}
+
+// ExportUserReq 导出用户请求
+type ExportUserReq struct {
+	g.Meta `path:"/user/export" method:"get" perm:"user:export" summary:"导出用户" tags:"用户管理"`
+	sysin.ExportUserInp
+}
+
+// ExportUserRes 导出用户响应，直接输出文件
+type ExportUserRes struct{}
@@ -55,9 +55,9 @@ type UserRefreshTokenRes struct {
 // UserChangePasswordReq 修改密码请求
 type UserChangePasswordReq struct {
 	g.Meta          `path:"/change-password" method:"post" summary:"修改密码" tags:"用户认证"`
-	OldPassword     string `json:"oldPassword" v:"required|length:6,32" description:"原密码"`
-	NewPassword     string `json:"newPassword" v:"required|length:6,32" description:"新密码"`
-	ConfirmPassword string `json:"confirmPassword" v:"required|same:NewPassword" description:"确认新密码"`
+	OldPassword     string `json:"oldPassword" v:"required"             description:"原密码"`
+	NewPassword     string `json:"newPassword" v:"required"             description:"新密码，按租户密码策略校验"`
+	ConfirmPassword string `json:"confirmPassword" v:"required" description:"确认新密码"`
 }
 
 // UserChangePasswordRes 修改密码响应
@@ -69,10 +69,277 @@ type UserChangePasswordRes struct {
 // GetCaptchaReq 获取验证码请求
 type GetCaptchaReq struct {
 	g.Meta `path:"/captcha" method:"get" summary:"获取验证码" tags:"用户认证"`
+	sysin.CaptchaInp
 }
 
 // GetCaptchaRes 获取验证码响应
 type GetCaptchaRes struct {
-	CaptchaId    string `json:"captchaId"    description:"验证码ID"`
-	CaptchaImage string `json:"captchaImage" description:"验证码图片（base64）"`
+	*sysout.CaptchaModel
+}
+
+// UserLoginTwoFactorReq 双因子认证登录请求
+type UserLoginTwoFactorReq struct {
+	g.Meta `path:"/login/2fa" method:"post" summary:"双因子认证登录" tags:"用户认证"`
+	sysin.UserLoginTwoFactorInp
+}
+
+// UserLoginTwoFactorRes 双因子认证登录响应
+type UserLoginTwoFactorRes struct {
+	*sysout.LoginTokenModel
+}
+
+// TwoFactorEnrollReq 获取双因子认证密钥请求
+type TwoFactorEnrollReq struct {
+	g.Meta `path:"/2fa/enroll" method:"post" summary:"获取双因子认证密钥" tags:"用户认证"`
+}
+
+// TwoFactorEnrollRes 获取双因子认证密钥响应
+type TwoFactorEnrollRes struct {
+	*sysout.TwoFactorEnrollModel
+}
+
+// TwoFactorConfirmReq 确认启用双因子认证请求
+type TwoFactorConfirmReq struct {
+	g.Meta `path:"/2fa/confirm" method:"post" summary:"确认启用双因子认证" tags:"用户认证"`
+	sysin.TwoFactorConfirmInp
+}
+
+// TwoFactorConfirmRes 确认启用双因子认证响应
+type TwoFactorConfirmRes struct {
+	*sysout.TwoFactorRecoveryCodesModel
+}
+
+// TwoFactorDisableReq 关闭双因子认证请求
+type TwoFactorDisableReq struct {
+	g.Meta `path:"/2fa/disable" method:"post" summary:"关闭双因子认证" tags:"用户认证"`
+	sysin.TwoFactorDisableInp
+}
+
+// TwoFactorDisableRes 关闭双因子认证响应
+type TwoFactorDisableRes struct {
+	Success bool   `json:"success" description:"是否成功"`
+	Message string `json:"message" description:"提示信息"`
+}
+
+// TwoFactorRecoveryCodesReq 重新生成恢复码请求
+type TwoFactorRecoveryCodesReq struct {
+	g.Meta `path:"/2fa/recovery-codes" method:"post" summary:"重新生成恢复码" tags:"用户认证"`
+	sysin.TwoFactorRecoveryCodesInp
+}
+
+// TwoFactorRecoveryCodesRes 重新生成恢复码响应
+type TwoFactorRecoveryCodesRes struct {
+	*sysout.TwoFactorRecoveryCodesModel
+}
+
+// ForgotPasswordReq 找回密码请求
+type ForgotPasswordReq struct {
+	g.Meta `path:"/forgot-password" method:"post" summary:"找回密码" tags:"用户认证"`
+	sysin.ForgotPasswordInp
+}
+
+// ForgotPasswordRes 找回密码响应
+type ForgotPasswordRes struct {
+	Success bool   `json:"success" description:"是否成功"`
+	Message string `json:"message" description:"提示信息"`
+}
+
+// ResetPasswordReq 重置密码请求
+type ResetPasswordReq struct {
+	g.Meta `path:"/reset-password" method:"post" summary:"重置密码" tags:"用户认证"`
+	sysin.ResetPasswordByTokenInp
+}
+
+// ResetPasswordRes 重置密码响应
+type ResetPasswordRes struct {
+	Success bool   `json:"success" description:"是否成功"`
+	Message string `json:"message" description:"提示信息"`
+}
+
+// OidcAuthorizeReq 发起单点登录请求
+type OidcAuthorizeReq struct {
+	g.Meta `path:"/oidc/authorize" method:"get" summary:"发起单点登录" tags:"用户认证"`
+	sysin.OidcAuthorizeInp
+}
+
+// OidcAuthorizeRes 发起单点登录响应
+type OidcAuthorizeRes struct {
+	*sysout.OidcAuthorizeModel
+}
+
+// OidcCallbackReq 单点登录回调请求
+type OidcCallbackReq struct {
+	g.Meta `path:"/oidc/callback" method:"post" summary:"单点登录回调" tags:"用户认证"`
+	sysin.OidcCallbackInp
+}
+
+// OidcCallbackRes 单点登录回调响应
+type OidcCallbackRes struct {
+	*sysout.LoginTokenModel
+}
+
+// RevokeUserSessionsReq 强制用户下线请求
+type RevokeUserSessionsReq struct {
+	g.Meta `path:"/user/revoke-sessions" method:"post" perm:"user:revoke-sessions" summary:"强制用户下线" tags:"用户认证"`
+	UserId int64 `json:"userId" v:"required|min:1" description:"用户ID"`
+}
+
+// RevokeUserSessionsRes 强制用户下线响应
+type RevokeUserSessionsRes struct {
+	Success bool   `json:"success" description:"是否成功"`
+	Message string `json:"message" description:"提示信息"`
+}
+
+// ImpersonateReq 模拟登录请求
+type ImpersonateReq struct {
+	g.Meta `path:"/user/impersonate" method:"post" perm:"user:impersonate" summary:"模拟登录" tags:"用户认证"`
+	sysin.ImpersonateInp
+}
+
+// ImpersonateRes 模拟登录响应
+type ImpersonateRes struct {
+	*sysout.ImpersonateModel
+}
+
+// StopImpersonateReq 结束模拟登录请求
+type StopImpersonateReq struct {
+	g.Meta `path:"/user/impersonate/stop" method:"post" summary:"结束模拟登录" tags:"用户认证"`
+}
+
+// StopImpersonateRes 结束模拟登录响应
+type StopImpersonateRes struct {
+	Success bool   `json:"success" description:"是否成功"`
+	Message string `json:"message" description:"提示信息"`
+}
+
+// SessionListReq 我的登录会话请求
+type SessionListReq struct {
+	g.Meta `path:"/sessions" method:"get" summary:"我的登录会话" tags:"用户认证"`
+}
+
+// SessionListRes 我的登录会话响应
+type SessionListRes struct {
+	List []*sysout.SessionModel `json:"list" description:"会话列表"`
+}
+
+// SessionRevokeReq 撤销我的登录会话请求
+type SessionRevokeReq struct {
+	g.Meta `path:"/sessions/revoke" method:"post" summary:"撤销我的登录会话" tags:"用户认证"`
+	sysin.SessionRevokeInp
+}
+
+// SessionRevokeRes 撤销我的登录会话响应
+type SessionRevokeRes struct {
+	Success bool   `json:"success" description:"是否成功"`
+	Message string `json:"message" description:"提示信息"`
+}
+
+// UserSessionListReq 用户登录会话请求
+type UserSessionListReq struct {
+	g.Meta `path:"/user/sessions" method:"get" perm:"user:sessions" summary:"用户登录会话" tags:"用户认证"`
+	sysin.UserSessionListInp
+}
+
+// UserSessionListRes 用户登录会话响应
+type UserSessionListRes struct {
+	List []*sysout.SessionModel `json:"list" description:"会话列表"`
+}
+
+// UserSessionRevokeReq 撤销用户登录会话请求
+type UserSessionRevokeReq struct {
+	g.Meta `path:"/user/sessions/revoke" method:"post" perm:"user:sessions:revoke" summary:"撤销用户登录会话" tags:"用户认证"`
+	sysin.UserSessionRevokeInp
+}
+
+// UserSessionRevokeRes 撤销用户登录会话响应
+type UserSessionRevokeRes struct {
+	Success bool   `json:"success" description:"是否成功"`
+	Message string `json:"message" description:"提示信息"`
+}
+
+// PasskeyRegisterBeginReq 获取通行密钥注册选项请求
+type PasskeyRegisterBeginReq struct {
+	g.Meta `path:"/passkeys/register/begin" method:"post" summary:"获取通行密钥注册选项" tags:"用户认证"`
+}
+
+// PasskeyRegisterBeginRes 获取通行密钥注册选项响应
+type PasskeyRegisterBeginRes struct {
+	*sysout.PasskeyCreationModel
+}
+
+// PasskeyRegisterFinishReq 完成通行密钥注册请求
+type PasskeyRegisterFinishReq struct {
+	g.Meta `path:"/passkeys/register/finish" method:"post" summary:"完成通行密钥注册" tags:"用户认证"`
+	sysin.PasskeyRegisterFinishInp
+}
+
+// PasskeyRegisterFinishRes 完成通行密钥注册响应
+type PasskeyRegisterFinishRes struct {
+	*sysout.PasskeyModel
+}
+
+// PasskeyListReq 我的通行密钥请求
+type PasskeyListReq struct {
+	g.Meta `path:"/passkeys" method:"get" summary:"我的通行密钥" tags:"用户认证"`
+}
+
+// PasskeyListRes 我的通行密钥响应
+type PasskeyListRes struct {
+	List []*sysout.PasskeyModel `json:"list" description:"通行密钥列表"`
+}
+
+// PasskeyDeleteReq 删除通行密钥请求
+type PasskeyDeleteReq struct {
+	g.Meta `path:"/passkeys/delete" method:"post" summary:"删除通行密钥" tags:"用户认证"`
+	sysin.PasskeyDeleteInp
+}
+
+// PasskeyDeleteRes 删除通行密钥响应
+type PasskeyDeleteRes struct {
+	Success bool   `json:"success" description:"是否成功"`
+	Message string `json:"message" description:"提示信息"`
+}
+
+// PasskeyLoginBeginReq 获取通行密钥登录选项请求
+type PasskeyLoginBeginReq struct {
+	g.Meta `path:"/passkey/login/begin" method:"post" summary:"获取通行密钥登录选项" tags:"用户认证"`
+	sysin.PasskeyLoginBeginInp
+}
+
+// PasskeyLoginBeginRes 获取通行密钥登录选项响应
+type PasskeyLoginBeginRes struct {
+	*sysout.PasskeyRequestModel
+}
+
+// PasskeyLoginFinishReq 通行密钥登录请求
+type PasskeyLoginFinishReq struct {
+	g.Meta `path:"/passkey/login/finish" method:"post" summary:"通行密钥登录" tags:"用户认证"`
+	sysin.PasskeyLoginFinishInp
+}
+
+// PasskeyLoginFinishRes 通行密钥登录响应
+type PasskeyLoginFinishRes struct {
+	*sysout.LoginTokenModel
+}
+
+// PasskeyTwoFactorBeginReq 获取通行密钥二次验证选项请求
+type PasskeyTwoFactorBeginReq struct {
+	g.Meta `path:"/login/2fa/passkey/begin" method:"post" summary:"获取通行密钥二次验证选项" tags:"用户认证"`
+	sysin.PasskeyTwoFactorBeginInp
+}
+
+// PasskeyTwoFactorBeginRes 获取通行密钥二次验证选项响应
+type PasskeyTwoFactorBeginRes struct {
+	*sysout.PasskeyRequestModel
+}
+
+// PasskeyTwoFactorFinishReq 通行密钥二次验证登录请求
+type PasskeyTwoFactorFinishReq struct {
+	g.Meta `path:"/login/2fa/passkey/finish" method:"post" summary:"通行密钥二次验证登录" tags:"用户认证"`
+	sysin.PasskeyTwoFactorFinishInp
+}
+
+// PasskeyTwoFactorFinishRes 通行密钥二次验证登录响应
+type PasskeyTwoFactorFinishRes struct {
+	*sysout.LoginTokenModel
 }
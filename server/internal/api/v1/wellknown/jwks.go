@@ -0,0 +1,13 @@
+package wellknown
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// JwksReq 获取JWT公钥集合请求
+type JwksReq struct {
+	g.Meta `path:"/.well-known/jwks.json" method:"get" summary:"获取JWT公钥集合" tags:"公开接口"`
+}
+
+// JwksRes 获取JWT公钥集合响应，直接按 RFC 7517 格式输出
+type JwksRes struct{}
@@ -8,6 +8,7 @@ package cmd
 import (
 	"client-app/internal/router"
 	"client-app/internal/service"
+	"client-app/utility/simple"
 	"context"
 
 	"github.com/gogf/gf/v2/frame/g"
@@ -41,6 +42,11 @@ var (
 				service.Middleware().ResponseHandler, // HTTP响应预处理，在业务处理完成后，对响应结果进行格式化和错误过滤，将处理后的数据发送给请求方
 			}...)
 
+			// JWT签名密钥未配置或无效时拒绝启动
+			if _, err = simple.JWTKeyring(ctx); err != nil {
+				return
+			}
+
 			s.Group("/", func(group *ghttp.RouterGroup) {
 				// 注册Api路由
 				router.Api(ctx, group)
@@ -59,8 +65,14 @@ var (
 				g.Log().Debug(ctx, "http successfully closed ..")
 				serverWg.Done()
 			}()
-			// Just run the server.
-			s.Run()
+			// 路由在服务启动时才完成注册，启动后检查接口权限标识
+			if err = s.Start(); err != nil {
+				return
+			}
+			service.Middleware().CheckRoutePermissions(ctx, s)
+
+			// 阻塞等待服务关闭
+			g.Wait()
 			return
 		},
 	}
@@ -19,13 +19,17 @@ const (
 // 鉴权相关错误码
 const (
 	// 认证错误
-	ErrUnauthorized = "UNAUTHORIZED"   // 未授权访问
-	ErrTokenMissing = "TOKEN_MISSING"  // Token缺失
-	ErrTokenInvalid = "TOKEN_INVALID"  // Token无效
-	ErrTokenExpired = "TOKEN_EXPIRED"  // Token已过期
-	ErrUserNotFound = "USER_NOT_FOUND" // 用户不存在
-	ErrUserDisabled = "USER_DISABLED"  // 用户已禁用
-	ErrUserLocked   = "USER_LOCKED"    // 用户已锁定
+	ErrUnauthorized   = "UNAUTHORIZED"      // 未授权访问
+	ErrTokenMissing   = "TOKEN_MISSING"     // Token缺失
+	ErrTokenInvalid   = "TOKEN_INVALID"     // Token无效
+	ErrTokenExpired   = "TOKEN_EXPIRED"     // Token已过期
+	ErrTokenRevoked   = "TOKEN_REVOKED"     // Token已撤销
+	ErrUserNotFound   = "USER_NOT_FOUND"    // 用户不存在
+	ErrUserDisabled   = "USER_DISABLED"     // 用户已禁用
+	ErrUserLocked     = "USER_LOCKED"       // 用户已锁定
+	ErrTenantDisabled = "TENANT_DISABLED"   // 租户不可用
+	ErrApiKeyInvalid  = "API_KEY_INVALID"   // API密钥无效
+	ErrApiKeyIpDenied = "API_KEY_IP_DENIED" // API密钥不允许从当前IP访问
 
 	// 权限错误
 	ErrForbidden        = "FORBIDDEN"         // 禁止访问
@@ -34,15 +38,44 @@ const (
 	ErrDataScopeLimit   = "DATA_SCOPE_LIMIT"  // 数据权限限制
 )
 
+// 登录保护响应码，前端根据响应码显示验证码或锁定提示
+const (
+	CodeCaptchaRequired = 1001 // 需要验证码
+	CodeLoginLocked     = 1002 // 登录失败次数过多，已被临时锁定
+	CodePasswordExpired = 1003 // 密码已过期，需要修改密码后才能继续访问
+)
+
+// 请求签名验证响应码，调用方可根据响应码区分失败原因
+const (
+	CodeSignMissing   = 1101 // 缺少签名参数
+	CodeSignMerchant  = 1102 // 商户不存在或已停用
+	CodeSignTimestamp = 1103 // 时间戳格式错误或超出允许的时间偏差
+	CodeSignNonce     = 1104 // nonce格式错误
+	CodeSignReplay    = 1105 // nonce已被使用，疑似重放请求
+	CodeSignInvalid   = 1106 // 签名不匹配
+)
+
+// 请求签名请求头
+const (
+	HeaderMerchantId = "X-Merchant-Id" // 商户ID
+	HeaderTimestamp  = "X-Timestamp"   // Unix时间戳（秒）
+	HeaderNonce      = "X-Nonce"       // 随机串
+	HeaderSignature  = "X-Signature"   // HMAC-SHA256签名
+)
+
 // 鉴权错误信息映射
 var AuthErrorMessages = map[string]string{
 	ErrUnauthorized:     "未授权访问，请先登录",
 	ErrTokenMissing:     "访问令牌不能为空",
 	ErrTokenInvalid:     "访问令牌无效",
 	ErrTokenExpired:     "访问令牌已过期，请重新登录",
+	ErrTokenRevoked:     "访问令牌已失效，请重新登录",
 	ErrUserNotFound:     "用户不存在或已被删除",
 	ErrUserDisabled:     "用户已被禁用，无法访问系统",
 	ErrUserLocked:       "用户已被锁定，请联系管理员",
+	ErrTenantDisabled:   "租户已被禁用、锁定或已过期，无法访问系统",
+	ErrApiKeyInvalid:    "API密钥无效、已过期或已撤销",
+	ErrApiKeyIpDenied:   "API密钥不允许从当前IP访问",
 	ErrForbidden:        "禁止访问该资源",
 	ErrPermissionDenied: "权限不足，无法执行该操作",
 	ErrRoleMissing:      "用户角色缺失，请联系管理员分配角色",
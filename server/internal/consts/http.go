@@ -24,3 +24,9 @@ const (
 	HTTPHandlerTime1000To10000 = "BETWEEN 1000 AND 10000"
 	HTTPHandlerTime10000UP     = "> 10000"
 )
+
+// API密钥认证
+const (
+	HTTPHeaderApiKey = "X-Api-Key" // API密钥请求头，也可以通过 Authorization: Bearer 传递
+	ApiKeyPrefix     = "ak_"       // API密钥前缀，用于区分API密钥和JWT
+)
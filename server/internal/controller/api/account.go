@@ -0,0 +1,209 @@
+package api
+
+import (
+	"client-app/internal/api/v1/user"
+	"client-app/internal/service"
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+var (
+	Account = &cAccount{}
+)
+
+// cAccount 当前登录用户的账号自助管理
+type cAccount struct{}
+
+// TwoFactorEnroll 获取双因子认证密钥
+func (c *cAccount) TwoFactorEnroll(ctx context.Context, req *user.TwoFactorEnrollReq) (res *user.TwoFactorEnrollRes, err error) {
+	currentUser := service.Middleware().GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, gerror.New("用户未登录")
+	}
+
+	out, err := service.User().EnrollTwoFactor(ctx, currentUser.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.TwoFactorEnrollRes{TwoFactorEnrollModel: out}
+	return res, nil
+}
+
+// TwoFactorConfirm 确认启用双因子认证
+func (c *cAccount) TwoFactorConfirm(ctx context.Context, req *user.TwoFactorConfirmReq) (res *user.TwoFactorConfirmRes, err error) {
+	currentUser := service.Middleware().GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, gerror.New("用户未登录")
+	}
+
+	out, err := service.User().ConfirmTwoFactor(ctx, currentUser.Id, &req.TwoFactorConfirmInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.TwoFactorConfirmRes{TwoFactorRecoveryCodesModel: out}
+	return res, nil
+}
+
+// TwoFactorDisable 关闭双因子认证
+func (c *cAccount) TwoFactorDisable(ctx context.Context, req *user.TwoFactorDisableReq) (res *user.TwoFactorDisableRes, err error) {
+	currentUser := service.Middleware().GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, gerror.New("用户未登录")
+	}
+
+	if err = service.User().DisableTwoFactor(ctx, currentUser.Id, &req.TwoFactorDisableInp); err != nil {
+		return nil, err
+	}
+
+	res = &user.TwoFactorDisableRes{
+		Success: true,
+		Message: "双因子认证已关闭",
+	}
+	return res, nil
+}
+
+// TwoFactorRecoveryCodes 重新生成恢复码
+func (c *cAccount) TwoFactorRecoveryCodes(ctx context.Context, req *user.TwoFactorRecoveryCodesReq) (res *user.TwoFactorRecoveryCodesRes, err error) {
+	currentUser := service.Middleware().GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, gerror.New("用户未登录")
+	}
+
+	out, err := service.User().RegenerateRecoveryCodes(ctx, currentUser.Id, &req.TwoFactorRecoveryCodesInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.TwoFactorRecoveryCodesRes{TwoFactorRecoveryCodesModel: out}
+	return res, nil
+}
+
+// SessionList 我的登录会话
+func (c *cAccount) SessionList(ctx context.Context, req *user.SessionListReq) (res *user.SessionListRes, err error) {
+	list, err := service.User().ListMySessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.SessionListRes{List: list}
+	return res, nil
+}
+
+// SessionRevoke 撤销我的登录会话
+func (c *cAccount) SessionRevoke(ctx context.Context, req *user.SessionRevokeReq) (res *user.SessionRevokeRes, err error) {
+	if err = service.User().RevokeMySession(ctx, &req.SessionRevokeInp); err != nil {
+		return nil, err
+	}
+
+	res = &user.SessionRevokeRes{
+		Success: true,
+		Message: "会话已撤销",
+	}
+	return res, nil
+}
+
+// StopImpersonate 结束模拟登录
+func (c *cAccount) StopImpersonate(ctx context.Context, req *user.StopImpersonateReq) (res *user.StopImpersonateRes, err error) {
+	if err = service.User().StopImpersonate(ctx); err != nil {
+		return nil, err
+	}
+
+	res = &user.StopImpersonateRes{
+		Success: true,
+		Message: "已结束模拟登录",
+	}
+	return res, nil
+}
+
+// Profile 获取用户信息
+func (c *cAccount) Profile(ctx context.Context, req *user.UserProfileReq) (res *user.UserProfileRes, err error) {
+	// 获取当前用户信息
+	currentUser := service.Middleware().GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, gerror.New("用户未登录")
+	}
+
+	// 调用服务层获取用户详细信息
+	out, err := service.User().GetProfile(ctx, currentUser.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	res = new(user.UserProfileRes)
+	if g.IsEmpty(out) {
+		return res, nil
+	}
+	res.UserModel = out
+	return res, nil
+}
+
+// ChangePassword 修改密码
+func (c *cAccount) ChangePassword(ctx context.Context, req *user.UserChangePasswordReq) (res *user.UserChangePasswordRes, err error) {
+	// 获取当前用户信息
+	currentUser := service.Middleware().GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, gerror.New("用户未登录")
+	}
+
+	// 调用服务层修改密码
+	err = service.User().ChangePassword(ctx, currentUser.Id, req.OldPassword, req.NewPassword, req.ConfirmPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.UserChangePasswordRes{
+		Success: true,
+		Message: "密码修改成功",
+	}
+	return res, nil
+}
+
+// PasskeyRegisterBegin 获取通行密钥注册选项
+func (c *cAccount) PasskeyRegisterBegin(ctx context.Context, req *user.PasskeyRegisterBeginReq) (res *user.PasskeyRegisterBeginRes, err error) {
+	out, err := service.User().PasskeyRegisterBegin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.PasskeyRegisterBeginRes{PasskeyCreationModel: out}
+	return res, nil
+}
+
+// PasskeyRegisterFinish 完成通行密钥注册
+func (c *cAccount) PasskeyRegisterFinish(ctx context.Context, req *user.PasskeyRegisterFinishReq) (res *user.PasskeyRegisterFinishRes, err error) {
+	out, err := service.User().PasskeyRegisterFinish(ctx, &req.PasskeyRegisterFinishInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.PasskeyRegisterFinishRes{PasskeyModel: out}
+	return res, nil
+}
+
+// PasskeyList 我的通行密钥
+func (c *cAccount) PasskeyList(ctx context.Context, req *user.PasskeyListReq) (res *user.PasskeyListRes, err error) {
+	list, err := service.User().ListPasskeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.PasskeyListRes{List: list}
+	return res, nil
+}
+
+// PasskeyDelete 删除通行密钥
+func (c *cAccount) PasskeyDelete(ctx context.Context, req *user.PasskeyDeleteReq) (res *user.PasskeyDeleteRes, err error) {
+	if err = service.User().DeletePasskey(ctx, &req.PasskeyDeleteInp); err != nil {
+		return nil, err
+	}
+
+	res = &user.PasskeyDeleteRes{
+		Success: true,
+		Message: "通行密钥已删除",
+	}
+	return res, nil
+}
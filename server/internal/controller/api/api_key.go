@@ -0,0 +1,49 @@
+package api
+
+import (
+	"client-app/internal/api/v1/apikey"
+	"client-app/internal/service"
+	"context"
+)
+
+var (
+	ApiKey = &cApiKey{}
+)
+
+// cApiKey 个人API密钥管理
+type cApiKey struct{}
+
+// List 获取API密钥列表
+func (c *cApiKey) List(ctx context.Context, req *apikey.ApiKeyListReq) (res *apikey.ApiKeyListRes, err error) {
+	list, err := service.ApiKey().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &apikey.ApiKeyListRes{List: list}
+	return res, nil
+}
+
+// Create 创建API密钥
+func (c *cApiKey) Create(ctx context.Context, req *apikey.ApiKeyCreateReq) (res *apikey.ApiKeyCreateRes, err error) {
+	out, err := service.ApiKey().Create(ctx, &req.ApiKeyCreateInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &apikey.ApiKeyCreateRes{ApiKeyCreateModel: out}
+	return res, nil
+}
+
+// Revoke 撤销API密钥
+func (c *cApiKey) Revoke(ctx context.Context, req *apikey.ApiKeyRevokeReq) (res *apikey.ApiKeyRevokeRes, err error) {
+	if err = service.ApiKey().Revoke(ctx, &req.ApiKeyRevokeInp); err != nil {
+		return nil, err
+	}
+
+	res = &apikey.ApiKeyRevokeRes{
+		Success: true,
+		Message: "API密钥已撤销",
+	}
+	return res, nil
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"client-app/internal/api/v1/crypto"
+	"client-app/internal/service"
+	"context"
+)
+
+var (
+	Crypto = &cCrypto{}
+)
+
+// cCrypto 传输加密
+type cCrypto struct{}
+
+// PublicKey 获取传输加密公钥，客户端用于加密密码
+func (c *cCrypto) PublicKey(ctx context.Context, req *crypto.PublicKeyReq) (res *crypto.PublicKeyRes, err error) {
+	out, err := service.Crypto().PublicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &crypto.PublicKeyRes{PublicKeyModel: out}
+	return res, nil
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"client-app/internal/api/v1/dept"
+	"client-app/internal/service"
+	"context"
+)
+
+var (
+	Dept = &cDept{}
+)
+
+type cDept struct{}
+
+// GetDeptTree 获取部门树
+func (c *cDept) GetDeptTree(ctx context.Context, req *dept.DeptTreeReq) (res *dept.DeptTreeRes, err error) {
+	out, err := service.Dept().GetDeptTree(ctx, &req.DeptTreeInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &dept.DeptTreeRes{List: out}
+	return res, nil
+}
+
+// GetDeptDetail 获取部门详情
+func (c *cDept) GetDeptDetail(ctx context.Context, req *dept.DeptDetailReq) (res *dept.DeptDetailRes, err error) {
+	out, err := service.Dept().GetDeptDetail(ctx, &req.DeptDetailInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &dept.DeptDetailRes{DeptDetailModel: out}
+	return res, nil
+}
+
+// CreateDept 创建部门
+func (c *cDept) CreateDept(ctx context.Context, req *dept.CreateDeptReq) (res *dept.CreateDeptRes, err error) {
+	out, err := service.Dept().CreateDept(ctx, &req.CreateDeptInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &dept.CreateDeptRes{DeptModel: out}
+	return res, nil
+}
+
+// UpdateDept 更新部门
+func (c *cDept) UpdateDept(ctx context.Context, req *dept.UpdateDeptReq) (res *dept.UpdateDeptRes, err error) {
+	out, err := service.Dept().UpdateDept(ctx, &req.UpdateDeptInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &dept.UpdateDeptRes{DeptModel: out}
+	return res, nil
+}
+
+// MoveDept 移动部门
+func (c *cDept) MoveDept(ctx context.Context, req *dept.MoveDeptReq) (res *dept.MoveDeptRes, err error) {
+	err = service.Dept().MoveDept(ctx, &req.MoveDeptInp)
+	return
+}
+
+// UpdateDeptStatus 修改部门状态
+func (c *cDept) UpdateDeptStatus(ctx context.Context, req *dept.UpdateDeptStatusReq) (res *dept.UpdateDeptStatusRes, err error) {
+	err = service.Dept().UpdateDeptStatus(ctx, &req.UpdateDeptStatusInp)
+	return
+}
+
+// DeleteDept 删除部门
+func (c *cDept) DeleteDept(ctx context.Context, req *dept.DeleteDeptReq) (res *dept.DeleteDeptRes, err error) {
+	err = service.Dept().DeleteDept(ctx, &req.DeleteDeptInp)
+	return
+}
+
+// MoveDeptUsers 调整用户所属部门
+func (c *cDept) MoveDeptUsers(ctx context.Context, req *dept.MoveDeptUsersReq) (res *dept.MoveDeptUsersRes, err error) {
+	err = service.Dept().MoveDeptUsers(ctx, &req.MoveDeptUsersInp)
+	return
+}
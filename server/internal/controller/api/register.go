@@ -0,0 +1,38 @@
+package api
+
+import (
+	"client-app/internal/api/v1/register"
+	"client-app/internal/service"
+	"context"
+)
+
+var (
+	Register = &cRegister{}
+)
+
+// cRegister 用户自助注册，需要挂载 TenantFilter 中间件确定目标租户
+type cRegister struct{}
+
+// Register 用户注册
+func (c *cRegister) Register(ctx context.Context, req *register.UserRegisterReq) (res *register.UserRegisterRes, err error) {
+	out, err := service.User().Register(ctx, &req.UserRegisterInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &register.UserRegisterRes{UserRegisterModel: out}
+	return res, nil
+}
+
+// VerifyEmail 验证邮箱
+func (c *cRegister) VerifyEmail(ctx context.Context, req *register.VerifyEmailReq) (res *register.VerifyEmailRes, err error) {
+	if err = service.User().VerifyEmail(ctx, &req.VerifyEmailInp); err != nil {
+		return nil, err
+	}
+
+	res = &register.VerifyEmailRes{
+		Success: true,
+		Message: "邮箱验证成功",
+	}
+	return res, nil
+}
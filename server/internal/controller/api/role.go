@@ -167,6 +167,34 @@ func (c *cRole) UpdateRoleMenus(ctx context.Context, req *role.UpdateRoleMenuReq
 	}, nil
 }
 
+// GetRoleDataScope 获取角色数据权限
+func (c *cRole) GetRoleDataScope(ctx context.Context, req *role.RoleDataScopeReq) (res *role.RoleDataScopeRes, err error) {
+	out, err := service.Role().GetRoleDataScope(ctx, &req.RoleDataScopeInp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &role.RoleDataScopeRes{
+		RoleDataScopeModel: out,
+	}, nil
+}
+
+// UpdateRoleDataScope 更新角色数据权限
+func (c *cRole) UpdateRoleDataScope(ctx context.Context, req *role.UpdateRoleDataScopeReq) (res *role.UpdateRoleDataScopeRes, err error) {
+	err = service.Role().UpdateRoleDataScope(ctx, &req.UpdateRoleDataScopeInp)
+	if err != nil {
+		return &role.UpdateRoleDataScopeRes{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &role.UpdateRoleDataScopeRes{
+		Success: true,
+		Message: "数据权限更新成功",
+	}, nil
+}
+
 // GetRolePermissions 获取角色权限详情
 func (c *cRole) GetRolePermissions(ctx context.Context, req *role.RolePermissionReq) (res *role.RolePermissionRes, err error) {
 	out, err := service.Role().GetRolePermissions(ctx, &req.RolePermissionInp)
@@ -124,3 +124,29 @@ func (c *Tenant) GetTenantOptions(ctx context.Context, req *tenant.TenantOptions
 	}
 	return res, nil
 }
+
+// CreateInviteCode 创建注册邀请码
+func (c *Tenant) CreateInviteCode(ctx context.Context, req *tenant.InviteCodeCreateReq) (res *tenant.InviteCodeCreateRes, err error) {
+	out, err := service.Tenant().CreateInviteCode(ctx, &req.InviteCodeCreateInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &tenant.InviteCodeCreateRes{
+		InviteCodeModel: out,
+	}
+	return res, nil
+}
+
+// GetInviteCodeList 获取注册邀请码列表
+func (c *Tenant) GetInviteCodeList(ctx context.Context, req *tenant.InviteCodeListReq) (res *tenant.InviteCodeListRes, err error) {
+	out, err := service.Tenant().GetInviteCodeList(ctx, &req.InviteCodeListInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &tenant.InviteCodeListRes{
+		InviteCodeListModel: out,
+	}
+	return res, nil
+}
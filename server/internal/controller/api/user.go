@@ -4,7 +4,6 @@ import (
 	"client-app/internal/api/v1/user"
 	"client-app/internal/service"
 	"context"
-	"github.com/gogf/gf/v2/errors/gerror"
 
 	"github.com/gogf/gf/v2/frame/g"
 )
@@ -46,77 +45,135 @@ func (c *cUser) Logout(ctx context.Context, req *user.UserLogoutReq) (res *user.
 	return res, nil
 }
 
-// Profile 获取用户信息
-func (c *cUser) Profile(ctx context.Context, req *user.UserProfileReq) (res *user.UserProfileRes, err error) {
-	// 获取当前用户信息
-	currentUser := service.Middleware().GetCurrentUser(ctx)
-	if currentUser == nil {
-		return nil, gerror.New("用户未登录")
+// RefreshToken 刷新访问令牌
+func (c *cUser) RefreshToken(ctx context.Context, req *user.UserRefreshTokenReq) (res *user.UserRefreshTokenRes, err error) {
+	// 调用服务层刷新令牌
+	out, err := service.User().RefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.UserRefreshTokenRes{
+		AccessToken:  out.AccessToken,
+		RefreshToken: out.RefreshToken,
+		ExpiresIn:    out.ExpiresIn,
 	}
+	return res, nil
+}
 
-	// 调用服务层获取用户详细信息
-	out, err := service.User().GetProfile(ctx, currentUser.Id)
+// GetCaptcha 获取验证码
+func (c *cUser) GetCaptcha(ctx context.Context, req *user.GetCaptchaReq) (res *user.GetCaptchaRes, err error) {
+
+	// 调用服务层生成验证码
+	out, err := service.User().GenerateCaptcha(ctx, &req.CaptchaInp)
 	if err != nil {
 		return nil, err
 	}
 
-	res = new(user.UserProfileRes)
-	if g.IsEmpty(out) {
-		return res, nil
-	}
-	res.UserModel = out
+	res = &user.GetCaptchaRes{CaptchaModel: out}
 	return res, nil
 }
 
-// RefreshToken 刷新访问令牌
-func (c *cUser) RefreshToken(ctx context.Context, req *user.UserRefreshTokenReq) (res *user.UserRefreshTokenRes, err error) {
-	// 调用服务层刷新令牌
-	out, err := service.User().RefreshToken(ctx, req.RefreshToken)
+// LoginTwoFactor 双因子认证登录
+func (c *cUser) LoginTwoFactor(ctx context.Context, req *user.UserLoginTwoFactorReq) (res *user.UserLoginTwoFactorRes, err error) {
+	out, err := service.User().LoginTwoFactor(ctx, &req.UserLoginTwoFactorInp)
 	if err != nil {
 		return nil, err
 	}
 
-	res = &user.UserRefreshTokenRes{
-		AccessToken:  out.AccessToken,
-		RefreshToken: out.RefreshToken,
-		ExpiresIn:    out.ExpiresIn,
+	res = &user.UserLoginTwoFactorRes{LoginTokenModel: out}
+	return res, nil
+}
+
+// ForgotPassword 找回密码
+func (c *cUser) ForgotPassword(ctx context.Context, req *user.ForgotPasswordReq) (res *user.ForgotPasswordRes, err error) {
+	if err = service.User().ForgotPassword(ctx, &req.ForgotPasswordInp); err != nil {
+		return nil, err
+	}
+
+	// 无论账号是否存在都返回相同提示，避免泄露账号信息
+	res = &user.ForgotPasswordRes{
+		Success: true,
+		Message: "如果账号存在且已绑定邮箱，重置链接已发送",
 	}
 	return res, nil
 }
 
-// ChangePassword 修改密码
-func (c *cUser) ChangePassword(ctx context.Context, req *user.UserChangePasswordReq) (res *user.UserChangePasswordRes, err error) {
-	// 获取当前用户信息
-	currentUser := service.Middleware().GetCurrentUser(ctx)
-	if currentUser == nil {
-		return nil, gerror.New("用户未登录")
+// OidcAuthorize 发起单点登录
+func (c *cUser) OidcAuthorize(ctx context.Context, req *user.OidcAuthorizeReq) (res *user.OidcAuthorizeRes, err error) {
+	out, err := service.User().OidcAuthorize(ctx, &req.OidcAuthorizeInp)
+	if err != nil {
+		return nil, err
 	}
 
-	// 调用服务层修改密码
-	err = service.User().ChangePassword(ctx, currentUser.Id, req.OldPassword, req.NewPassword)
+	res = &user.OidcAuthorizeRes{OidcAuthorizeModel: out}
+	return res, nil
+}
+
+// OidcCallback 单点登录回调
+func (c *cUser) OidcCallback(ctx context.Context, req *user.OidcCallbackReq) (res *user.OidcCallbackRes, err error) {
+	out, err := service.User().OidcCallback(ctx, &req.OidcCallbackInp)
 	if err != nil {
 		return nil, err
 	}
 
-	res = &user.UserChangePasswordRes{
+	res = &user.OidcCallbackRes{LoginTokenModel: out}
+	return res, nil
+}
+
+// ResetPassword 重置密码
+func (c *cUser) ResetPassword(ctx context.Context, req *user.ResetPasswordReq) (res *user.ResetPasswordRes, err error) {
+	if err = service.User().ResetPasswordByToken(ctx, &req.ResetPasswordByTokenInp); err != nil {
+		return nil, err
+	}
+
+	res = &user.ResetPasswordRes{
 		Success: true,
-		Message: "密码修改成功",
+		Message: "密码重置成功，请重新登录",
 	}
 	return res, nil
 }
 
-// GetCaptcha 获取验证码
-func (c *cUser) GetCaptcha(ctx context.Context, req *user.GetCaptchaReq) (res *user.GetCaptchaRes, err error) {
+// PasskeyLoginBegin 获取通行密钥登录选项
+func (c *cUser) PasskeyLoginBegin(ctx context.Context, req *user.PasskeyLoginBeginReq) (res *user.PasskeyLoginBeginRes, err error) {
+	out, err := service.User().PasskeyLoginBegin(ctx, &req.PasskeyLoginBeginInp)
+	if err != nil {
+		return nil, err
+	}
 
-	// 调用服务层生成验证码
-	captchaId, captchaImage, err := service.User().GenerateCaptcha(ctx)
+	res = &user.PasskeyLoginBeginRes{PasskeyRequestModel: out}
+	return res, nil
+}
+
+// PasskeyLoginFinish 通行密钥登录
+func (c *cUser) PasskeyLoginFinish(ctx context.Context, req *user.PasskeyLoginFinishReq) (res *user.PasskeyLoginFinishRes, err error) {
+	out, err := service.User().PasskeyLoginFinish(ctx, &req.PasskeyLoginFinishInp)
 	if err != nil {
 		return nil, err
 	}
 
-	res = &user.GetCaptchaRes{
-		CaptchaId:    captchaId,
-		CaptchaImage: captchaImage,
+	res = &user.PasskeyLoginFinishRes{LoginTokenModel: out}
+	return res, nil
+}
+
+// PasskeyTwoFactorBegin 获取通行密钥二次验证选项
+func (c *cUser) PasskeyTwoFactorBegin(ctx context.Context, req *user.PasskeyTwoFactorBeginReq) (res *user.PasskeyTwoFactorBeginRes, err error) {
+	out, err := service.User().PasskeyTwoFactorBegin(ctx, &req.PasskeyTwoFactorBeginInp)
+	if err != nil {
+		return nil, err
 	}
+
+	res = &user.PasskeyTwoFactorBeginRes{PasskeyRequestModel: out}
+	return res, nil
+}
+
+// PasskeyTwoFactorFinish 通行密钥二次验证登录
+func (c *cUser) PasskeyTwoFactorFinish(ctx context.Context, req *user.PasskeyTwoFactorFinishReq) (res *user.PasskeyTwoFactorFinishRes, err error) {
+	out, err := service.User().PasskeyTwoFactorFinish(ctx, &req.PasskeyTwoFactorFinishInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.PasskeyTwoFactorFinishRes{LoginTokenModel: out}
 	return res, nil
 }
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"client-app/internal/api/v1/user"
+	"client-app/internal/service"
+	"context"
+	"net/url"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+var (
+	UserManage = &cUserManage{}
+)
+
+// cUserManage 用户管理，按接口权限标识鉴权
+type cUserManage struct{}
+
+// GetUserList 获取用户列表
+func (c *cUserManage) GetUserList(ctx context.Context, req *user.UserListReq) (res *user.UserListRes, err error) {
+	out, err := service.User().GetUserList(ctx, &req.UserListInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.UserListRes{UserListModel: out}
+	return res, nil
+}
+
+// GetUserDetail 获取用户详情
+func (c *cUserManage) GetUserDetail(ctx context.Context, req *user.UserDetailReq) (res *user.UserDetailRes, err error) {
+	out, err := service.User().GetUserDetail(ctx, &req.UserDetailInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.UserDetailRes{UserDetailModel: out}
+	return res, nil
+}
+
+// CreateUser 创建用户
+func (c *cUserManage) CreateUser(ctx context.Context, req *user.CreateUserReq) (res *user.CreateUserRes, err error) {
+	out, err := service.User().CreateUser(ctx, &req.CreateUserInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.CreateUserRes{UserModel: out}
+	return res, nil
+}
+
+// UpdateUser 更新用户
+func (c *cUserManage) UpdateUser(ctx context.Context, req *user.UpdateUserReq) (res *user.UpdateUserRes, err error) {
+	out, err := service.User().UpdateUser(ctx, &req.UpdateUserInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.UpdateUserRes{UserModel: out}
+	return res, nil
+}
+
+// UpdateUserStatus 修改用户状态
+func (c *cUserManage) UpdateUserStatus(ctx context.Context, req *user.UpdateUserStatusReq) (res *user.UpdateUserStatusRes, err error) {
+	err = service.User().UpdateUserStatus(ctx, &req.UpdateUserStatusInp)
+	return
+}
+
+// ResetUserPassword 重置用户密码
+func (c *cUserManage) ResetUserPassword(ctx context.Context, req *user.ResetUserPasswordReq) (res *user.ResetUserPasswordRes, err error) {
+	err = service.User().ResetUserPassword(ctx, &req.ResetPasswordInp)
+	return
+}
+
+// DeleteUser 删除用户
+func (c *cUserManage) DeleteUser(ctx context.Context, req *user.DeleteUserReq) (res *user.DeleteUserRes, err error) {
+	err = service.User().DeleteUser(ctx, &req.DeleteUserInp)
+	return
+}
+
+// AssignUserRoles 分配用户角色
+func (c *cUserManage) AssignUserRoles(ctx context.Context, req *user.AssignUserRolesReq) (res *user.AssignUserRolesRes, err error) {
+	err = service.User().AssignUserRoles(ctx, &req.AssignUserRoleInp)
+	return
+}
+
+// ImportUsers 批量导入用户
+func (c *cUserManage) ImportUsers(ctx context.Context, req *user.ImportUserReq) (res *user.ImportUserRes, err error) {
+	out, err := service.User().ImportUsers(ctx, &req.ImportUserInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.ImportUserRes{UserImportModel: out}
+	return res, nil
+}
+
+// ExportUsers 导出用户
+func (c *cUserManage) ExportUsers(ctx context.Context, req *user.ExportUserReq) (res *user.ExportUserRes, err error) {
+	out, err := service.User().ExportUsers(ctx, &req.ExportUserInp)
+	if err != nil {
+		return nil, err
+	}
+
+	r := g.RequestFromCtx(ctx)
+	r.Response.Header().Set("Content-Type", out.ContentType)
+	r.Response.Header().Set("Content-Disposition", "attachment; filename*=UTF-8''"+url.PathEscape(out.Filename))
+	r.Response.Write(out.Content)
+	return
+}
+
+// RevokeUserSessions 强制用户下线
+func (c *cUserManage) RevokeUserSessions(ctx context.Context, req *user.RevokeUserSessionsReq) (res *user.RevokeUserSessionsRes, err error) {
+	if err = service.User().RevokeUserSessions(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	res = &user.RevokeUserSessionsRes{
+		Success: true,
+		Message: "已撤销该用户的全部登录会话",
+	}
+	return res, nil
+}
+
+// UserSessionList 用户登录会话
+func (c *cUserManage) UserSessionList(ctx context.Context, req *user.UserSessionListReq) (res *user.UserSessionListRes, err error) {
+	list, err := service.User().ListUserSessions(ctx, &req.UserSessionListInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.UserSessionListRes{List: list}
+	return res, nil
+}
+
+// UserSessionRevoke 撤销用户登录会话
+func (c *cUserManage) UserSessionRevoke(ctx context.Context, req *user.UserSessionRevokeReq) (res *user.UserSessionRevokeRes, err error) {
+	if err = service.User().RevokeUserSession(ctx, &req.UserSessionRevokeInp); err != nil {
+		return nil, err
+	}
+
+	res = &user.UserSessionRevokeRes{
+		Success: true,
+		Message: "会话已撤销",
+	}
+	return res, nil
+}
+
+// Impersonate 模拟登录
+func (c *cUserManage) Impersonate(ctx context.Context, req *user.ImpersonateReq) (res *user.ImpersonateRes, err error) {
+	out, err := service.User().Impersonate(ctx, &req.ImpersonateInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &user.ImpersonateRes{ImpersonateModel: out}
+	return res, nil
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"client-app/internal/api/v1/wellknown"
+	"client-app/internal/library/response"
+	"client-app/utility/simple"
+	"context"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+var (
+	WellKnown = &cWellKnown{}
+)
+
+// cWellKnown 公开的标准发现接口
+type cWellKnown struct{}
+
+// Jwks 获取JWT公钥集合，供网关和其他服务验证令牌
+func (c *cWellKnown) Jwks(ctx context.Context, req *wellknown.JwksReq) (res *wellknown.JwksRes, err error) {
+	set, err := simple.JWKS(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r := g.RequestFromCtx(ctx)
+	r.Response.Header().Set("Cache-Control", "public, max-age=300")
+	response.CustomJson(r, set)
+	return
+}
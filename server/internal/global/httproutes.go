@@ -21,8 +21,12 @@ type HTTPRouter struct {
 	Tags        string `json:"tags"         dc:"接口所属的标签，用于接口分类"`
 	Summary     string `json:"summary"      dc:"接口/参数概要描述"`
 	Description string `json:"description"  dc:"接口/参数详细描述"`
+	Permission  string `json:"permission"   dc:"接口权限标识，来自请求结构体g.Meta的perm标签"`
 }
 
+// PermissionTag 请求结构体g.Meta中声明接口权限标识的标签，如 perm:"role:delete"
+const PermissionTag = "perm"
+
 var (
 	httpRoutes         map[string]*HTTPRouter
 	routeMutex         sync.Mutex
@@ -34,9 +38,12 @@ var (
 	}
 )
 
-// GetRequestRoute 获取当前请求路由属性
+// GetRequestRoute 获取当前请求路由属性，优先按匹配到的路由模板查找，带参数的路由如 /role/{id} 同样适用
 func GetRequestRoute(r *ghttp.Request) *HTTPRouter {
 	key := GenFilterRequestKey(r)
+	if r.Router != nil {
+		key = GenFilterRouteKey(r.Router)
+	}
 	routes := LoadHTTPRoutes(r)
 	router, ok := routes[key]
 	if !ok {
@@ -60,7 +67,13 @@ func GenRouteKey(method, path string) string {
 	return strings.ToUpper(method) + " " + path
 }
 
+// LoadHTTPRoutes 加载请求所属服务的路由属性
 func LoadHTTPRoutes(r *ghttp.Request) map[string]*HTTPRouter {
+	return LoadServerRoutes(r.Server)
+}
+
+// LoadServerRoutes 加载服务已注册的路由属性，路由在服务启动时注册，需在启动后调用
+func LoadServerRoutes(s *ghttp.Server) map[string]*HTTPRouter {
 	if httpRoutes == nil {
 		routeMutex.Lock()
 		defer routeMutex.Unlock()
@@ -69,8 +82,8 @@ func LoadHTTPRoutes(r *ghttp.Request) map[string]*HTTPRouter {
 			return httpRoutes
 		}
 
-		httpRoutes = make(map[string]*HTTPRouter, len(r.Server.GetRoutes()))
-		for _, v := range r.Server.GetRoutes() {
+		httpRoutes = make(map[string]*HTTPRouter, len(s.GetRoutes()))
+		for _, v := range s.GetRoutes() {
 			key := GenFilterRouteKey(v.Handler.Router)
 			if _, ok := httpRoutes[key]; !ok {
 				router := new(HTTPRouter)
@@ -112,6 +125,7 @@ func setRouterMeta(router *HTTPRouter) *HTTPRouter {
 	router.Tags = inputMetaMap["tags"]
 	router.Summary = inputMetaMap[gtag.Summary]
 	router.Description = inputMetaMap[gtag.Description]
+	router.Permission = inputMetaMap[PermissionTag]
 	return router
 }
 
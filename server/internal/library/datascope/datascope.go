@@ -0,0 +1,154 @@
+// Package datascope
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package datascope
+
+import (
+	"client-app/internal/consts"
+	"client-app/internal/model"
+	"client-app/internal/model/entity"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// DefaultOwnerField 默认的数据所有者字段
+const DefaultOwnerField = "created_by"
+
+// Options 数据权限过滤选项
+type Options struct {
+	// OwnerField 数据所有者字段，默认 created_by；仅本人数据时按该字段过滤
+	OwnerField string
+	// DeptField 数据所属部门字段，为空时按所有者所在部门过滤
+	DeptField string
+	// TenantField 租户字段，设置后非系统管理员只能访问本租户数据
+	TenantField string
+}
+
+// Scope 用户的数据权限范围，持有多个角色时取并集，角色继承上级角色的数据权限
+type Scope struct {
+	All      bool    // 可访问全部数据
+	UserId   int64   // 本人ID，始终可访问本人数据
+	TenantId int64   // 所属租户
+	DeptIds  []int64 // 可访问的部门
+}
+
+// Resolve 根据用户已启用的角色及其启用的上级角色计算数据权限范围
+// 继承链上的数据权限取并集，即不小于其中范围最大的；没有角色时只能访问本人数据
+func Resolve(ctx context.Context, identity *model.Identity) (*Scope, error) {
+	if identity == nil || identity.Id <= 0 {
+		return nil, gerror.New(consts.GetAuthErrorMessage(consts.ErrDataScopeLimit))
+	}
+
+	var roles []*entity.Role
+	err := g.DB().Model("sys_user_roles ur").
+		InnerJoin("sys_roles r", "r.id = ur.role_id").
+		InnerJoin("sys_roles pr", "pr.id = r.id OR FIND_IN_SET(pr.id, r.ancestors)").
+		Fields("pr.id, pr.data_scope").
+		Where("ur.user_id = ? AND r.tenant_id = ? AND r.status = ? AND r.deleted_at IS NULL",
+			identity.Id, identity.TenantId, entity.RoleStatusEnabled).
+		Where("pr.status = ? AND pr.deleted_at IS NULL", entity.RoleStatusEnabled).
+		Distinct().
+		Scan(&roles)
+	if err != nil {
+		return nil, gerror.Newf("查询用户数据权限失败: %v", err)
+	}
+
+	scope := &Scope{UserId: identity.Id, TenantId: identity.TenantId}
+	var (
+		deptIds       = make(map[int64]struct{})
+		withSubDept   bool
+		customRoleIds []int64
+	)
+	for _, role := range roles {
+		switch role.DataScope {
+		case entity.DataScopeAll:
+			scope.All = true
+			return scope, nil
+		case entity.DataScopeDept:
+			if identity.DeptId > 0 {
+				deptIds[identity.DeptId] = struct{}{}
+			}
+		case entity.DataScopeDeptAndSub:
+			if identity.DeptId > 0 {
+				deptIds[identity.DeptId] = struct{}{}
+				withSubDept = true
+			}
+		case entity.DataScopeCustom:
+			customRoleIds = append(customRoleIds, role.Id)
+		}
+	}
+
+	// 多个自定义数据权限角色的部门取并集
+	if len(customRoleIds) > 0 {
+		customIds, err := g.DB().Model("sys_role_depts").
+			Fields("dept_id").
+			Where("role_id IN(?) AND tenant_id = ?", customRoleIds, identity.TenantId).
+			Array()
+		if err != nil {
+			return nil, gerror.Newf("查询角色数据权限部门失败: %v", err)
+		}
+		for _, id := range customIds {
+			deptIds[id.Int64()] = struct{}{}
+		}
+	}
+
+	if withSubDept {
+		subIds, err := g.DB().Model("sys_depts").
+			Where("tenant_id = ? AND FIND_IN_SET(?, ancestors)", identity.TenantId, identity.DeptId).
+			Array("id")
+		if err != nil {
+			return nil, gerror.Newf("查询下级部门失败: %v", err)
+		}
+		for _, id := range subIds {
+			deptIds[id.Int64()] = struct{}{}
+		}
+	}
+
+	for id := range deptIds {
+		scope.DeptIds = append(scope.DeptIds, id)
+	}
+	sort.Slice(scope.DeptIds, func(i, j int) bool { return scope.DeptIds[i] < scope.DeptIds[j] })
+	return scope, nil
+}
+
+// Filter 按当前身份的数据权限过滤查询
+func Filter(ctx context.Context, m *gdb.Model, identity *model.Identity, opts Options) (*gdb.Model, error) {
+	scope, err := Resolve(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	return scope.Apply(m, opts, identity.IsSystemAdmin()), nil
+}
+
+// Apply 将数据权限范围应用到查询，crossTenant 为 true 时不限制租户
+func (s *Scope) Apply(m *gdb.Model, opts Options, crossTenant bool) *gdb.Model {
+	if opts.TenantField != "" && !crossTenant {
+		m = m.Where(fmt.Sprintf("%s = ?", opts.TenantField), s.TenantId)
+	}
+	if s.All {
+		return m
+	}
+
+	owner := opts.OwnerField
+	if owner == "" {
+		owner = DefaultOwnerField
+	}
+	if len(s.DeptIds) == 0 {
+		return m.Where(fmt.Sprintf("%s = ?", owner), s.UserId)
+	}
+
+	if opts.DeptField != "" {
+		return m.Where(fmt.Sprintf("(%s = ? OR %s IN(?))", owner, opts.DeptField), s.UserId, s.DeptIds)
+	}
+
+	// 数据没有部门字段时，按所有者所在部门判断
+	deptUsers := g.DB().Model("sys_users").Fields("id").Where("tenant_id = ? AND dept_id IN(?)", s.TenantId, s.DeptIds)
+	return m.Where(fmt.Sprintf("(%s = ? OR %s IN(?))", owner, owner), s.UserId, deptUsers)
+}
@@ -0,0 +1,81 @@
+// Package notify
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package notify
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// 通知渠道
+const (
+	ChannelEmail = "email"
+	ChannelSms   = "sms"
+)
+
+// Message 通知消息
+type Message struct {
+	Channel   string            `json:"channel"`   // 通知渠道
+	To        string            `json:"to"`        // 接收人，邮箱或手机号
+	Subject   string            `json:"subject"`   // 标题
+	Content   string            `json:"content"`   // 正文
+	Template  string            `json:"template"`  // 模板标识，便于外部发送服务按模板渲染
+	Data      map[string]string `json:"data"`      // 模板变量
+	CreatedAt *gtime.Time       `json:"createdAt"` // 创建时间
+}
+
+// Notifier 通知发送器
+type Notifier interface {
+	// Name 发送器名称，对应配置 notify.driver
+	Name() string
+	// Send 发送通知
+	Send(ctx context.Context, msg *Message) error
+}
+
+var (
+	notifiers = make(map[string]Notifier)
+	mu        sync.RWMutex
+)
+
+func init() {
+	Register(NewOutbox())
+}
+
+// Register 注册通知发送器，同名会被覆盖
+func Register(n Notifier) {
+	mu.Lock()
+	defer mu.Unlock()
+	notifiers[n.Name()] = n
+}
+
+// Get 获取配置的通知发送器
+func Get(ctx context.Context) (Notifier, error) {
+	driver := g.Cfg().MustGet(ctx, "notify.driver", "outbox").String()
+
+	mu.RLock()
+	defer mu.RUnlock()
+	n, ok := notifiers[driver]
+	if !ok {
+		return nil, gerror.Newf("通知发送器[%s]未注册", driver)
+	}
+	return n, nil
+}
+
+// Send 使用配置的通知发送器发送通知
+func Send(ctx context.Context, msg *Message) error {
+	n, err := Get(ctx)
+	if err != nil {
+		return err
+	}
+	if msg.CreatedAt == nil {
+		msg.CreatedAt = gtime.Now()
+	}
+	return n.Send(ctx, msg)
+}
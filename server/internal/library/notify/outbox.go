@@ -0,0 +1,50 @@
+// Package notify
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package notify
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+)
+
+// Outbox 将通知按行写入本地文件，用于本地开发和测试，不会真正发送
+type Outbox struct {
+	mu sync.Mutex
+}
+
+// NewOutbox 创建文件发件箱
+func NewOutbox() *Outbox {
+	return &Outbox{}
+}
+
+// Name 发送器名称
+func (o *Outbox) Name() string {
+	return "outbox"
+}
+
+// Send 追加写入发件箱文件，每行一条JSON
+func (o *Outbox) Send(ctx context.Context, msg *Message) error {
+	path := g.Cfg().MustGet(ctx, "notify.outbox.path", "./storage/outbox/notify.log").String()
+
+	line, err := gjson.Marshal(msg)
+	if err != nil {
+		return gerror.Wrap(err, "序列化通知消息失败")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if err = gfile.PutBytesAppend(path, append(line, '\n')); err != nil {
+		return gerror.Wrapf(err, "写入发件箱失败: %s", path)
+	}
+
+	g.Log().Debugf(ctx, "通知已写入发件箱: channel=%s to=%s subject=%s", msg.Channel, msg.To, msg.Subject)
+	return nil
+}
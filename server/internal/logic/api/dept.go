@@ -0,0 +1,670 @@
+package api
+
+import (
+	"client-app/internal/model"
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
+	"context"
+	"strings"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+type sDept struct{}
+
+func init() {
+	service.RegisterDept(NewDept())
+}
+
+func NewDept() *sDept {
+	return &sDept{}
+}
+
+// GetDeptTree 获取当前租户的部门树
+// 按名称筛选时，命中部门的上级部门一并返回，保证树结构完整
+func (s *sDept) GetDeptTree(ctx context.Context, in *sysin.DeptTreeInp) (res []*sysout.DeptTreeModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	operator, err := s.getOperator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := g.DB().Model("sys_depts").Where("tenant_id = ?", operator.TenantId)
+	if in.Status >= 0 {
+		db = db.Where("status = ?", in.Status)
+	}
+
+	var depts []*entity.Dept
+	if err = db.Order("sort ASC, id ASC").Scan(&depts); err != nil {
+		return nil, gerror.Newf("查询部门失败: %v", err)
+	}
+
+	if in.Name != "" {
+		keep := make(map[int64]bool)
+		for _, dept := range depts {
+			if strings.Contains(dept.Name, in.Name) {
+				for _, id := range dept.Path() {
+					keep[id] = true
+				}
+			}
+		}
+		filtered := depts[:0]
+		for _, dept := range depts {
+			if keep[dept.Id] {
+				filtered = append(filtered, dept)
+			}
+		}
+		depts = filtered
+	}
+
+	models, err := s.convertDepts(ctx, operator.TenantId, depts)
+	if err != nil {
+		return nil, err
+	}
+	userCounts, err := s.countDeptUsers(ctx, operator.TenantId, depts)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[int64]*sysout.DeptTreeModel, len(models))
+	for _, m := range models {
+		nodes[m.Id] = &sysout.DeptTreeModel{DeptModel: m, UserCount: userCounts[m.Id]}
+	}
+
+	res = []*sysout.DeptTreeModel{}
+	for _, m := range models {
+		node := nodes[m.Id]
+		// 上级部门被筛选掉时作为根节点展示
+		if parent, ok := nodes[m.ParentId]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			res = append(res, node)
+		}
+	}
+	return res, nil
+}
+
+// GetDeptDetail 获取部门详情，包含上级部门
+func (s *sDept) GetDeptDetail(ctx context.Context, in *sysin.DeptDetailInp) (res *sysout.DeptDetailModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	operator, err := s.getOperator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dept, err := s.getTenantDept(ctx, operator.TenantId, in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	depts := []*entity.Dept{dept}
+	if ancestorIds := dept.AncestorIds(); len(ancestorIds) > 0 {
+		var parents []*entity.Dept
+		err = g.DB().Model("sys_depts").
+			Where("tenant_id = ? AND id IN(?)", operator.TenantId, ancestorIds).
+			Scan(&parents)
+		if err != nil {
+			return nil, gerror.Newf("查询上级部门失败: %v", err)
+		}
+
+		// 按ID链顺序排列上级部门
+		parentMap := make(map[int64]*entity.Dept, len(parents))
+		for _, parent := range parents {
+			parentMap[parent.Id] = parent
+		}
+		for _, id := range ancestorIds {
+			if parent, ok := parentMap[id]; ok {
+				depts = append(depts, parent)
+			}
+		}
+	}
+
+	models, err := s.convertDepts(ctx, operator.TenantId, depts)
+	if err != nil {
+		return nil, err
+	}
+	userCounts, err := s.countDeptUsers(ctx, operator.TenantId, depts[:1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &sysout.DeptDetailModel{
+		DeptModel: models[0],
+		Parents:   models[1:],
+		UserCount: userCounts[dept.Id],
+	}, nil
+}
+
+// CreateDept 创建部门
+func (s *sDept) CreateDept(ctx context.Context, in *sysin.CreateDeptInp) (res *sysout.DeptModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	operator, err := s.getOperator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors := ""
+	if in.ParentId > 0 {
+		parent, err := s.getTenantDept(ctx, operator.TenantId, in.ParentId)
+		if err != nil {
+			return nil, gerror.New("上级部门不存在")
+		}
+		if !parent.IsEnabled() && in.Status == entity.DeptStatusEnabled {
+			return nil, gerror.New("上级部门已禁用，不能创建启用的下级部门")
+		}
+		if len(parent.Path()) >= entity.DeptMaxDepth {
+			return nil, gerror.Newf("部门层级不能超过%d级", entity.DeptMaxDepth)
+		}
+		ancestors = parent.ChildAncestors()
+	}
+
+	if err = s.checkDeptCode(ctx, operator.TenantId, 0, in.Code); err != nil {
+		return nil, err
+	}
+	if err = s.checkDeptLeader(ctx, operator.TenantId, in.LeaderId); err != nil {
+		return nil, err
+	}
+
+	id, err := g.DB().Model("sys_depts").Data(g.Map{
+		"tenant_id":  operator.TenantId,
+		"parent_id":  in.ParentId,
+		"ancestors":  ancestors,
+		"name":       in.Name,
+		"code":       in.Code,
+		"type":       in.Type,
+		"leader_id":  in.LeaderId,
+		"phone":      in.Phone,
+		"email":      in.Email,
+		"sort":       in.Sort,
+		"status":     in.Status,
+		"remark":     in.Remark,
+		"created_by": operator.Id,
+		"updated_by": operator.Id,
+		"created_at": gtime.Now(),
+		"updated_at": gtime.Now(),
+	}).InsertAndGetId()
+	if err != nil {
+		return nil, gerror.Newf("创建部门失败: %v", err)
+	}
+
+	service.Middleware().LogAudit(ctx, "CREATE", "dept", "success", id, in.Name)
+	return s.getDeptModel(ctx, operator.TenantId, id)
+}
+
+// UpdateDept 更新部门
+func (s *sDept) UpdateDept(ctx context.Context, in *sysin.UpdateDeptInp) (res *sysout.DeptModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	operator, err := s.getOperator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dept, err := s.getTenantDept(ctx, operator.TenantId, in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.checkDeptCode(ctx, operator.TenantId, dept.Id, in.Code); err != nil {
+		return nil, err
+	}
+	if err = s.checkDeptLeader(ctx, operator.TenantId, in.LeaderId); err != nil {
+		return nil, err
+	}
+
+	_, err = g.DB().Model("sys_depts").Where("id = ?", dept.Id).Update(g.Map{
+		"name":       in.Name,
+		"code":       in.Code,
+		"type":       in.Type,
+		"leader_id":  in.LeaderId,
+		"phone":      in.Phone,
+		"email":      in.Email,
+		"sort":       in.Sort,
+		"remark":     in.Remark,
+		"updated_by": operator.Id,
+		"updated_at": gtime.Now(),
+	})
+	if err != nil {
+		return nil, gerror.Newf("更新部门失败: %v", err)
+	}
+
+	// 部门类型缓存在成员的权限快照中
+	if dept.Type != in.Type {
+		s.invalidateDeptUsers(ctx, operator.TenantId, dept.Id)
+	}
+
+	service.Middleware().LogAudit(ctx, "UPDATE", "dept", "success", dept.Id, in.Name)
+	return s.getDeptModel(ctx, operator.TenantId, dept.Id)
+}
+
+// MoveDept 移动部门到新的上级部门，下级部门的ID链随之更新
+func (s *sDept) MoveDept(ctx context.Context, in *sysin.MoveDeptInp) error {
+	if err := in.Filter(ctx); err != nil {
+		return err
+	}
+
+	operator, err := s.getOperator(ctx)
+	if err != nil {
+		return err
+	}
+	dept, err := s.getTenantDept(ctx, operator.TenantId, in.Id)
+	if err != nil {
+		return err
+	}
+
+	newAncestors := []int64{}
+	if in.ParentId > 0 {
+		parent, err := s.getTenantDept(ctx, operator.TenantId, in.ParentId)
+		if err != nil {
+			return gerror.New("目标上级部门不存在")
+		}
+		// 目标部门是自身或下级部门时会形成循环
+		for _, id := range parent.Path() {
+			if id == dept.Id {
+				return gerror.New("不能将部门移动到自身或下级部门")
+			}
+		}
+		if !parent.IsEnabled() && dept.IsEnabled() {
+			return gerror.New("目标上级部门已禁用")
+		}
+		newAncestors = parent.Path()
+	}
+
+	descendants, err := s.getDescendants(ctx, operator.TenantId, dept.Id)
+	if err != nil {
+		return err
+	}
+
+	// 下级部门的ID链 = 新的ID链 + 当前部门 + 原ID链中当前部门之后的部分
+	oldDepth := len(dept.AncestorIds())
+	updates := make(map[int64]string, len(descendants))
+	for _, child := range descendants {
+		childAncestors := child.AncestorIds()
+		if len(childAncestors) <= oldDepth {
+			continue
+		}
+		path := append(append([]int64{}, newAncestors...), childAncestors[oldDepth:]...)
+		if len(path)+1 > entity.DeptMaxDepth {
+			return gerror.Newf("部门层级不能超过%d级", entity.DeptMaxDepth)
+		}
+		updates[child.Id] = entity.FormatDeptAncestors(path)
+	}
+	if len(newAncestors)+1 > entity.DeptMaxDepth {
+		return gerror.Newf("部门层级不能超过%d级", entity.DeptMaxDepth)
+	}
+
+	data := g.Map{
+		"parent_id":  in.ParentId,
+		"ancestors":  entity.FormatDeptAncestors(newAncestors),
+		"updated_by": operator.Id,
+		"updated_at": gtime.Now(),
+	}
+	if in.Sort != nil {
+		data["sort"] = *in.Sort
+	}
+
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		if _, err := tx.Model("sys_depts").Where("id = ?", dept.Id).Update(data); err != nil {
+			return gerror.Newf("移动部门失败: %v", err)
+		}
+		for id, ancestors := range updates {
+			if _, err := tx.Model("sys_depts").Where("id = ?", id).Data("ancestors", ancestors).Update(); err != nil {
+				return gerror.Newf("更新下级部门失败: %v", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 部门及下级部门成员的权限快照中缓存了上级部门ID链
+	deptIds := []int64{dept.Id}
+	for _, child := range descendants {
+		deptIds = append(deptIds, child.Id)
+	}
+	s.invalidateDeptUsers(ctx, operator.TenantId, deptIds...)
+
+	service.Middleware().LogAudit(ctx, "MOVE", "dept", "success", dept.Id, dept.ParentId, in.ParentId)
+	return nil
+}
+
+// UpdateDeptStatus 启用或禁用部门
+// 禁用时下级部门一并禁用；启用时要求上级部门已启用，下级部门需单独启用
+func (s *sDept) UpdateDeptStatus(ctx context.Context, in *sysin.UpdateDeptStatusInp) error {
+	if err := in.Filter(ctx); err != nil {
+		return err
+	}
+
+	operator, err := s.getOperator(ctx)
+	if err != nil {
+		return err
+	}
+	dept, err := s.getTenantDept(ctx, operator.TenantId, in.Id)
+	if err != nil {
+		return err
+	}
+	if dept.Status == in.Status {
+		return nil
+	}
+
+	data := g.Map{
+		"status":     in.Status,
+		"updated_by": operator.Id,
+		"updated_at": gtime.Now(),
+	}
+	if in.Status == entity.DeptStatusEnabled {
+		if dept.ParentId > 0 {
+			parent, err := s.getTenantDept(ctx, operator.TenantId, dept.ParentId)
+			if err != nil {
+				return err
+			}
+			if !parent.IsEnabled() {
+				return gerror.New("上级部门已禁用，请先启用上级部门")
+			}
+		}
+		_, err = g.DB().Model("sys_depts").Where("id = ?", dept.Id).Update(data)
+	} else {
+		_, err = g.DB().Model("sys_depts").
+			Where("tenant_id = ?", operator.TenantId).
+			Where("id = ? OR FIND_IN_SET(?, ancestors)", dept.Id, dept.Id).
+			Update(data)
+	}
+	if err != nil {
+		return gerror.Newf("修改部门状态失败: %v", err)
+	}
+
+	service.Middleware().LogAudit(ctx, "UPDATE_STATUS", "dept", "success", dept.Id, in.Status)
+	return nil
+}
+
+// DeleteDept 删除部门，存在下级部门或用户时不允许删除
+func (s *sDept) DeleteDept(ctx context.Context, in *sysin.DeleteDeptInp) error {
+	if err := in.Filter(ctx); err != nil {
+		return err
+	}
+
+	operator, err := s.getOperator(ctx)
+	if err != nil {
+		return err
+	}
+	dept, err := s.getTenantDept(ctx, operator.TenantId, in.Id)
+	if err != nil {
+		return err
+	}
+
+	count, err := g.DB().Model("sys_depts").Where("tenant_id = ? AND parent_id = ?", operator.TenantId, dept.Id).Count()
+	if err != nil {
+		return gerror.Newf("检查下级部门失败: %v", err)
+	}
+	if count > 0 {
+		return gerror.New("存在下级部门，无法删除")
+	}
+
+	count, err = g.DB().Model("sys_users").Where("tenant_id = ? AND dept_id = ? AND deleted_at IS NULL", operator.TenantId, dept.Id).Count()
+	if err != nil {
+		return gerror.Newf("检查部门用户失败: %v", err)
+	}
+	if count > 0 {
+		return gerror.New("部门下存在用户，无法删除")
+	}
+
+	// 同时移除角色自定义数据权限中的该部门
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		if _, err := tx.Model("sys_depts").Where("id = ?", dept.Id).Delete(); err != nil {
+			return gerror.Newf("删除部门失败: %v", err)
+		}
+		if _, err := tx.Model("sys_role_depts").Where("dept_id = ?", dept.Id).Delete(); err != nil {
+			return gerror.Newf("删除角色部门关联失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	service.Middleware().LogAudit(ctx, "DELETE", "dept", "success", dept.Id, dept.Name)
+	return nil
+}
+
+// MoveDeptUsers 调整用户所属部门
+func (s *sDept) MoveDeptUsers(ctx context.Context, in *sysin.MoveDeptUsersInp) error {
+	if err := in.Filter(ctx); err != nil {
+		return err
+	}
+
+	operator, err := s.getOperator(ctx)
+	if err != nil {
+		return err
+	}
+	if len(in.UserIds) == 0 {
+		return gerror.New("用户ID列表不能为空")
+	}
+
+	if in.DeptId > 0 {
+		dept, err := s.getTenantDept(ctx, operator.TenantId, in.DeptId)
+		if err != nil {
+			return err
+		}
+		if !dept.IsEnabled() {
+			return gerror.New("目标部门已禁用")
+		}
+	}
+
+	count, err := g.DB().Model("sys_users").
+		Where("tenant_id = ? AND id IN(?) AND deleted_at IS NULL", operator.TenantId, in.UserIds).
+		Count()
+	if err != nil {
+		return gerror.Newf("查询用户失败: %v", err)
+	}
+	if count != len(in.UserIds) {
+		return gerror.New("用户不存在")
+	}
+
+	_, err = g.DB().Model("sys_users").
+		Where("tenant_id = ? AND id IN(?)", operator.TenantId, in.UserIds).
+		Update(g.Map{
+			"dept_id":    in.DeptId,
+			"updated_by": operator.Id,
+			"updated_at": gtime.Now(),
+		})
+	if err != nil {
+		return gerror.Newf("调整用户部门失败: %v", err)
+	}
+
+	// 快照中的部门用于数据权限，调整后需重新加载
+	if err = service.PermCache().InvalidateUsers(ctx, in.UserIds...); err != nil {
+		g.Log().Warningf(ctx, "失效用户%v的权限快照失败: %v", in.UserIds, err)
+	}
+
+	service.Middleware().LogAudit(ctx, "MOVE_USERS", "dept", "success", in.DeptId, in.UserIds)
+	return nil
+}
+
+// GetDept 获取租户内的部门，不存在时返回nil
+func (s *sDept) GetDept(ctx context.Context, tenantId, deptId int64) (*entity.Dept, error) {
+	var dept *entity.Dept
+	err := g.DB().Model("sys_depts").Where("id = ? AND tenant_id = ?", deptId, tenantId).Scan(&dept)
+	if err != nil {
+		return nil, gerror.Newf("查询部门失败: %v", err)
+	}
+	return dept, nil
+}
+
+// invalidateDeptUsers 失效部门成员的权限快照，失败只记录日志
+func (s *sDept) invalidateDeptUsers(ctx context.Context, tenantId int64, deptIds ...int64) {
+	userIds, err := g.DB().Model("sys_users").Fields("id").
+		Where("tenant_id = ? AND dept_id IN(?) AND deleted_at IS NULL", tenantId, deptIds).
+		Array()
+	if err != nil {
+		g.Log().Warningf(ctx, "查询部门%v用户失败: %v", deptIds, err)
+		return
+	}
+	if len(userIds) == 0 {
+		return
+	}
+
+	ids := make([]int64, 0, len(userIds))
+	for _, id := range userIds {
+		ids = append(ids, id.Int64())
+	}
+	if err = service.PermCache().InvalidateUsers(ctx, ids...); err != nil {
+		g.Log().Warningf(ctx, "失效部门%v用户的权限快照失败: %v", deptIds, err)
+	}
+}
+
+// getOperator 获取当前操作人，部门操作范围限定在其所属租户
+func (s *sDept) getOperator(ctx context.Context) (*model.Identity, error) {
+	operator := service.Middleware().GetCurrentUser(ctx)
+	if operator == nil {
+		return nil, gerror.New("用户未登录")
+	}
+	if operator.TenantId <= 0 {
+		return nil, gerror.New("租户信息无效")
+	}
+	return operator, nil
+}
+
+// getTenantDept 获取租户内的部门，不存在时返回错误
+func (s *sDept) getTenantDept(ctx context.Context, tenantId, deptId int64) (*entity.Dept, error) {
+	dept, err := s.GetDept(ctx, tenantId, deptId)
+	if err != nil {
+		return nil, err
+	}
+	if dept == nil {
+		return nil, gerror.New("部门不存在")
+	}
+	return dept, nil
+}
+
+// getDeptModel 获取部门信息模型
+func (s *sDept) getDeptModel(ctx context.Context, tenantId, deptId int64) (*sysout.DeptModel, error) {
+	dept, err := s.getTenantDept(ctx, tenantId, deptId)
+	if err != nil {
+		return nil, err
+	}
+	models, err := s.convertDepts(ctx, tenantId, []*entity.Dept{dept})
+	if err != nil {
+		return nil, err
+	}
+	return models[0], nil
+}
+
+// getDescendants 获取所有下级部门
+func (s *sDept) getDescendants(ctx context.Context, tenantId, deptId int64) ([]*entity.Dept, error) {
+	var depts []*entity.Dept
+	err := g.DB().Model("sys_depts").
+		Where("tenant_id = ? AND FIND_IN_SET(?, ancestors)", tenantId, deptId).
+		Scan(&depts)
+	if err != nil {
+		return nil, gerror.Newf("查询下级部门失败: %v", err)
+	}
+	return depts, nil
+}
+
+// checkDeptCode 检查部门编码在租户内唯一
+func (s *sDept) checkDeptCode(ctx context.Context, tenantId, excludeId int64, code string) error {
+	db := g.DB().Model("sys_depts").Where("tenant_id = ? AND code = ?", tenantId, code)
+	if excludeId > 0 {
+		db = db.Where("id != ?", excludeId)
+	}
+	count, err := db.Count()
+	if err != nil {
+		return gerror.Newf("查询部门失败: %v", err)
+	}
+	if count > 0 {
+		return gerror.New("部门编码已存在")
+	}
+	return nil
+}
+
+// checkDeptLeader 检查负责人是租户内的有效用户
+func (s *sDept) checkDeptLeader(ctx context.Context, tenantId, leaderId int64) error {
+	if leaderId == 0 {
+		return nil
+	}
+	count, err := g.DB().Model("sys_users").Where("id = ? AND tenant_id = ? AND deleted_at IS NULL", leaderId, tenantId).Count()
+	if err != nil {
+		return gerror.Newf("查询负责人失败: %v", err)
+	}
+	if count == 0 {
+		return gerror.New("负责人不存在")
+	}
+	return nil
+}
+
+// convertDepts 转换部门模型并填充负责人姓名
+func (s *sDept) convertDepts(ctx context.Context, tenantId int64, depts []*entity.Dept) ([]*sysout.DeptModel, error) {
+	var leaderIds []int64
+	for _, dept := range depts {
+		if dept.LeaderId > 0 {
+			leaderIds = append(leaderIds, dept.LeaderId)
+		}
+	}
+
+	leaderNames := make(map[int64]string)
+	if len(leaderIds) > 0 {
+		var leaders []*entity.User
+		err := g.DB().Model("sys_users").
+			Fields("id", "username", "real_name").
+			Where("tenant_id = ? AND id IN(?) AND deleted_at IS NULL", tenantId, leaderIds).
+			Scan(&leaders)
+		if err != nil {
+			return nil, gerror.Newf("查询部门负责人失败: %v", err)
+		}
+		for _, leader := range leaders {
+			leaderNames[leader.Id] = leader.RealName
+			if leader.RealName == "" {
+				leaderNames[leader.Id] = leader.Username
+			}
+		}
+	}
+
+	models := make([]*sysout.DeptModel, 0, len(depts))
+	for _, dept := range depts {
+		m := sysout.ConvertToDeptModel(dept)
+		m.LeaderName = leaderNames[dept.LeaderId]
+		models = append(models, m)
+	}
+	return models, nil
+}
+
+// countDeptUsers 统计部门直属用户数
+func (s *sDept) countDeptUsers(ctx context.Context, tenantId int64, depts []*entity.Dept) (map[int64]int, error) {
+	counts := make(map[int64]int, len(depts))
+	if len(depts) == 0 {
+		return counts, nil
+	}
+
+	deptIds := make([]int64, 0, len(depts))
+	for _, dept := range depts {
+		deptIds = append(deptIds, dept.Id)
+	}
+
+	result, err := g.DB().Model("sys_users").
+		Fields("dept_id, COUNT(*) AS total").
+		Where("tenant_id = ? AND dept_id IN(?) AND deleted_at IS NULL", tenantId, deptIds).
+		Group("dept_id").
+		All()
+	if err != nil {
+		return nil, gerror.Newf("统计部门用户失败: %v", err)
+	}
+	for _, record := range result {
+		counts[record["dept_id"].Int64()] = record["total"].Int()
+	}
+	return counts, nil
+}
@@ -321,6 +321,9 @@ func (s *sMenu) UpdateMenu(ctx context.Context, in *sysin.UpdateMenuInp) (res *s
 		return nil, gerror.Newf("更新菜单失败: %v", err)
 	}
 
+	// 权限标识或状态可能已变化
+	s.invalidateMenuPerms(ctx, in.Id)
+
 	// 查询更新后的菜单
 	var menu *entity.Menu
 	err = g.DB().Model("sys_menus").Where("id", in.Id).Scan(&menu)
@@ -432,6 +435,7 @@ func (s *sMenu) UpdateMenuStatus(ctx context.Context, in *sysin.UpdateMenuStatus
 		return gerror.Newf("更新菜单状态失败: %v", err)
 	}
 
+	s.invalidateMenuPerms(ctx, in.Id)
 	return nil
 }
 
@@ -719,3 +723,11 @@ func (s *sMenu) isChildRouter(router *sysout.RouterModel, parentId int64) bool {
 	}
 	return menu.ParentId == parentId
 }
+
+// invalidateMenuPerms 菜单权限变化后失效可访问这些菜单的用户权限快照
+// 变更已提交，失效失败只记录日志，快照到期后自动重新加载
+func (s *sMenu) invalidateMenuPerms(ctx context.Context, menuIds ...int64) {
+	if err := service.PermCache().InvalidateMenus(ctx, menuIds...); err != nil {
+		g.Log().Warningf(ctx, "失效菜单%v的用户权限快照失败: %v", menuIds, err)
+	}
+}
@@ -1,10 +1,12 @@
 package api
 
 import (
+	"client-app/internal/library/datascope"
 	"client-app/internal/model/entity"
 	"client-app/internal/model/input/sysin"
 	"client-app/internal/model/output/sysout"
 	"client-app/internal/service"
+	"client-app/utility/permission"
 	"context"
 	"fmt"
 
@@ -17,6 +19,12 @@ import (
 
 type sRole struct{}
 
+// userRoleChainSQL 用户启用的角色 r 及其继承链上启用的角色 pr（包括 r 本身）
+// 下级角色拥有继承链上所有角色的菜单权限
+const userRoleChainSQL = `sys_user_roles ur
+			JOIN sys_roles r ON ur.role_id = r.id AND r.status = 1 AND r.deleted_at IS NULL
+			JOIN sys_roles pr ON (pr.id = r.id OR FIND_IN_SET(pr.id, r.ancestors)) AND pr.status = 1 AND pr.deleted_at IS NULL`
+
 func NewRole() *sRole {
 	return &sRole{}
 }
@@ -32,8 +40,17 @@ func (s *sRole) GetRoleList(ctx context.Context, in *sysin.RoleListInp) (*sysout
 		return nil, err
 	}
 
-	// 构建查询条件
-	db := g.DB().Model("sys_roles").Where("deleted_at IS NULL")
+	// 构建查询条件，按当前用户的租户和数据权限过滤
+	identity := service.Middleware().GetCurrentUser(ctx)
+	if identity == nil {
+		return nil, gerror.New("用户未登录")
+	}
+	db, err := datascope.Filter(ctx, g.DB().Model("sys_roles").Where("deleted_at IS NULL"), identity, datascope.Options{
+		TenantField: "tenant_id",
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	// 状态筛选
 	if in.Status >= 0 {
@@ -157,12 +174,27 @@ func (s *sRole) CreateRole(ctx context.Context, in *sysin.CreateRoleInp) (*sysou
 		return nil, gerror.New("角色名称已存在")
 	}
 
+	// 新角色创建在当前用户所属租户，上级角色必须属于同一租户
+	identity := service.Middleware().GetCurrentUser(ctx)
+	if identity == nil {
+		return nil, gerror.New("用户未登录")
+	}
+	tenantId := identity.TenantId
+
+	// 校验上级角色
+	ancestors, err := s.resolveRoleAncestors(ctx, 0, in.ParentId, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
 	var resultRole *sysout.RoleModel
 
 	// 开启事务
 	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
 		// 插入角色记录
 		roleData := &entity.Role{
+			ParentId:    in.ParentId,
+			Ancestors:   entity.FormatDeptAncestors(ancestors),
 			Name:        in.Name,
 			Code:        in.Code,
 			Description: in.Description,
@@ -180,7 +212,9 @@ func (s *sRole) CreateRole(ctx context.Context, in *sysin.CreateRoleInp) (*sysou
 			roleData.UpdatedBy = userId
 		}
 
-		result, err := tx.Model("sys_roles").Data(roleData).Insert()
+		data := gconv.Map(roleData)
+		data["tenant_id"] = tenantId
+		result, err := tx.Model("sys_roles").Data(data).Insert()
 		if err != nil {
 			return gerror.Newf("创建角色失败: %v", err)
 		}
@@ -253,6 +287,24 @@ func (s *sRole) UpdateRole(ctx context.Context, in *sysin.UpdateRoleInp) (*sysou
 		return nil, gerror.New("角色名称已存在")
 	}
 
+	// 调整上级角色时校验循环继承，并重新计算下级角色的ID链
+	var (
+		ancestors   []int64
+		descendants map[int64]string
+	)
+	if in.ParentId != role.ParentId {
+		tenantId, err := g.DB().Model("sys_roles").Where("id = ?", role.Id).Value("tenant_id")
+		if err != nil {
+			return nil, gerror.Newf("查询角色租户失败: %v", err)
+		}
+		if ancestors, err = s.resolveRoleAncestors(ctx, role.Id, in.ParentId, tenantId.Int64()); err != nil {
+			return nil, err
+		}
+		if descendants, err = s.rebaseRoleDescendants(ctx, role, ancestors); err != nil {
+			return nil, err
+		}
+	}
+
 	var resultRole *sysout.RoleModel
 
 	// 开启事务
@@ -269,6 +321,11 @@ func (s *sRole) UpdateRole(ctx context.Context, in *sysin.UpdateRoleInp) (*sysou
 			"updated_at":  gtime.Now(),
 		}
 
+		if ancestors != nil {
+			updateData["parent_id"] = in.ParentId
+			updateData["ancestors"] = entity.FormatDeptAncestors(ancestors)
+		}
+
 		// 获取当前用户ID
 		if userId := s.getCurrentUserId(ctx); userId > 0 {
 			updateData["updated_by"] = userId
@@ -279,6 +336,13 @@ func (s *sRole) UpdateRole(ctx context.Context, in *sysin.UpdateRoleInp) (*sysou
 			return gerror.Newf("更新角色失败: %v", err)
 		}
 
+		// 更新下级角色的ID链
+		for id, childAncestors := range descendants {
+			if _, err = tx.Model("sys_roles").Where("id = ?", id).Data("ancestors", childAncestors).Update(); err != nil {
+				return gerror.Newf("更新下级角色失败: %v", err)
+			}
+		}
+
 		// 更新菜单权限
 		if err := s.updateRoleMenus(ctx, tx, in.Id, in.MenuIds); err != nil {
 			return err
@@ -299,6 +363,8 @@ func (s *sRole) UpdateRole(ctx context.Context, in *sysin.UpdateRoleInp) (*sysou
 		return nil, err
 	}
 
+	// 状态、菜单或上级角色变化后，拥有该角色及其下级角色的用户权限随之变化
+	s.invalidateRolePerms(ctx, in.Id)
 	return resultRole, nil
 }
 
@@ -329,6 +395,15 @@ func (s *sRole) DeleteRole(ctx context.Context, in *sysin.DeleteRoleInp) error {
 		return gerror.New("该角色正在被用户使用，无法删除")
 	}
 
+	// 检查是否有下级角色继承该角色
+	descendantIds, err := s.getRoleDescendantIds(ctx, in.Id)
+	if err != nil {
+		return err
+	}
+	if len(descendantIds) > 0 {
+		return gerror.New("该角色存在下级角色，无法删除")
+	}
+
 	// 开启事务删除
 	return g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
 		// 软删除角色
@@ -352,6 +427,12 @@ func (s *sRole) DeleteRole(ctx context.Context, in *sysin.DeleteRoleInp) error {
 			return gerror.Newf("删除角色菜单关联失败: %v", err)
 		}
 
+		// 删除角色部门关联
+		_, err = tx.Model("sys_role_depts").Where("role_id = ?", in.Id).Delete()
+		if err != nil {
+			return gerror.Newf("删除角色部门关联失败: %v", err)
+		}
+
 		return nil
 	})
 }
@@ -363,7 +444,11 @@ func (s *sRole) BatchDeleteRole(ctx context.Context, in *sysin.BatchDeleteRoleIn
 		return err
 	}
 
-	// 检查所有角色
+	// 检查所有角色，下级角色需一并删除
+	deleting := make(map[int64]bool, len(in.Ids))
+	for _, roleId := range in.Ids {
+		deleting[roleId] = true
+	}
 	for _, roleId := range in.Ids {
 		role, err := s.getRoleById(ctx, roleId)
 		if err != nil {
@@ -381,6 +466,16 @@ func (s *sRole) BatchDeleteRole(ctx context.Context, in *sysin.BatchDeleteRoleIn
 		if hasUsers {
 			return gerror.Newf("角色 %s 正在被用户使用，无法删除", role.Name)
 		}
+
+		descendantIds, err := s.getRoleDescendantIds(ctx, roleId)
+		if err != nil {
+			return err
+		}
+		for _, id := range descendantIds {
+			if !deleting[id] {
+				return gerror.Newf("角色 %s 存在下级角色，无法删除", role.Name)
+			}
+		}
 	}
 
 	// 开启事务批量删除
@@ -406,6 +501,12 @@ func (s *sRole) BatchDeleteRole(ctx context.Context, in *sysin.BatchDeleteRoleIn
 			return gerror.Newf("删除角色菜单关联失败: %v", err)
 		}
 
+		// 删除角色部门关联
+		_, err = tx.Model("sys_role_depts").Where("role_id IN(?)", in.Ids).Delete()
+		if err != nil {
+			return gerror.Newf("删除角色部门关联失败: %v", err)
+		}
+
 		return nil
 	})
 }
@@ -441,6 +542,8 @@ func (s *sRole) UpdateRoleStatus(ctx context.Context, in *sysin.UpdateRoleStatus
 		return gerror.Newf("更新角色状态失败: %v", err)
 	}
 
+	// 启用或禁用角色后，拥有该角色的用户权限随之变化
+	s.invalidateRolePerms(ctx, in.Id)
 	return nil
 }
 
@@ -451,8 +554,8 @@ func (s *sRole) CopyRole(ctx context.Context, in *sysin.CopyRoleInp) (*sysout.Ro
 		return nil, err
 	}
 
-	// 获取源角色信息
-	sourceRole, err := s.getRoleById(ctx, in.Id)
+	// 获取源角色信息，复制的角色和源角色属于同一租户
+	sourceRole, tenantId, err := s.getManagedRole(ctx, in.Id)
 	if err != nil {
 		return nil, err
 	}
@@ -481,12 +584,20 @@ func (s *sRole) CopyRole(ctx context.Context, in *sysin.CopyRoleInp) (*sysout.Ro
 		return nil, err
 	}
 
+	// 获取源角色自定义数据权限的部门
+	deptIds, err := s.getRoleDeptIds(ctx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+
 	var resultRole *sysout.RoleModel
 
 	// 开启事务复制
 	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
 		// 创建新角色
 		newRole := &entity.Role{
+			ParentId:    sourceRole.ParentId,
+			Ancestors:   sourceRole.Ancestors,
 			Name:        in.Name,
 			Code:        in.Code,
 			Description: sourceRole.Description,
@@ -503,7 +614,9 @@ func (s *sRole) CopyRole(ctx context.Context, in *sysin.CopyRoleInp) (*sysout.Ro
 			newRole.UpdatedBy = userId
 		}
 
-		result, err := tx.Model("sys_roles").Data(newRole).Insert()
+		data := gconv.Map(newRole)
+		data["tenant_id"] = tenantId
+		result, err := tx.Model("sys_roles").Data(data).Insert()
 		if err != nil {
 			return gerror.Newf("复制角色失败: %v", err)
 		}
@@ -522,6 +635,11 @@ func (s *sRole) CopyRole(ctx context.Context, in *sysin.CopyRoleInp) (*sysout.Ro
 			}
 		}
 
+		// 复制自定义数据权限的部门
+		if err := s.updateRoleDepts(ctx, tx, tenantId, newRoleId, deptIds); err != nil {
+			return err
+		}
+
 		resultRole = sysout.ConvertToRoleModel(newRole)
 		return nil
 	})
@@ -584,9 +702,15 @@ func (s *sRole) UpdateRoleMenus(ctx context.Context, in *sysin.UpdateRoleMenuInp
 	}
 
 	// 开启事务更新
-	return g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
 		return s.updateRoleMenus(ctx, tx, in.RoleId, in.MenuIds)
 	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateRolePerms(ctx, in.RoleId)
+	return nil
 }
 
 // GetRolePermissions 获取角色权限详情
@@ -614,7 +738,136 @@ func (s *sRole) GetRolePermissions(ctx context.Context, in *sysin.RolePermission
 		return nil, err
 	}
 
-	return sysout.ConvertToRolePermissionModel(role, menuIds, permissions), nil
+	res := sysout.ConvertToRolePermissionModel(role, menuIds, permissions)
+
+	// 从启用的上级角色继承的权限，已直接分配的不再重复列出
+	ancestors, err := s.getRoleAncestors(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		inheritIds []int64
+		scopes     = []int{role.DataScope}
+	)
+	for _, ancestor := range ancestors {
+		res.Ancestors = append(res.Ancestors, sysout.ConvertToRoleOptionModel(ancestor))
+		if ancestor.IsEnabled() {
+			inheritIds = append(inheritIds, ancestor.Id)
+			scopes = append(scopes, ancestor.DataScope)
+		}
+	}
+	res.EffectiveDataScope = entity.WidestDataScope(scopes...)
+	if len(inheritIds) == 0 {
+		return res, nil
+	}
+
+	inheritedMenuIds, err := s.getRolesMenuIds(ctx, inheritIds)
+	if err != nil {
+		return nil, err
+	}
+	direct := make(map[int64]bool, len(menuIds))
+	for _, id := range menuIds {
+		direct[id] = true
+	}
+	for _, id := range inheritedMenuIds {
+		if !direct[id] {
+			res.InheritedMenuIds = append(res.InheritedMenuIds, id)
+		}
+	}
+
+	inheritedPermissions, err := s.getRolesPermissions(ctx, inheritIds)
+	if err != nil {
+		return nil, err
+	}
+	directPerms := make(map[string]bool, len(permissions))
+	for _, perm := range permissions {
+		directPerms[perm] = true
+	}
+	for _, perm := range inheritedPermissions {
+		if !directPerms[perm] {
+			res.InheritedPermissions = append(res.InheritedPermissions, perm)
+		}
+	}
+	return res, nil
+}
+
+// GetRoleDataScope 获取角色数据权限
+func (s *sRole) GetRoleDataScope(ctx context.Context, in *sysin.RoleDataScopeInp) (*sysout.RoleDataScopeModel, error) {
+	// 参数过滤
+	if err := in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	// 检查角色是否存在
+	role, _, err := s.getManagedRole(ctx, in.RoleId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取自定义数据权限的部门
+	deptIds := []int64{}
+	if role.DataScope == entity.DataScopeCustom {
+		if deptIds, err = s.getRoleDeptIds(ctx, role.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	return &sysout.RoleDataScopeModel{
+		RoleId:        role.Id,
+		RoleName:      role.Name,
+		RoleCode:      role.Code,
+		DataScope:     role.DataScope,
+		DataScopeName: role.GetDataScopeName(),
+		DeptIds:       deptIds,
+	}, nil
+}
+
+// UpdateRoleDataScope 更新角色数据权限，自定义数据权限的部门必须属于角色所在租户
+func (s *sRole) UpdateRoleDataScope(ctx context.Context, in *sysin.UpdateRoleDataScopeInp) error {
+	// 参数过滤
+	if err := in.Filter(ctx); err != nil {
+		return err
+	}
+
+	// 检查角色是否存在
+	role, tenantId, err := s.getManagedRole(ctx, in.RoleId)
+	if err != nil {
+		return err
+	}
+
+	// 检查部门是否属于角色所在租户
+	if len(in.DeptIds) > 0 {
+		count, err := g.DB().Model("sys_depts").Where("id IN(?) AND tenant_id = ?", in.DeptIds, tenantId).Count()
+		if err != nil {
+			return gerror.Newf("查询部门失败: %v", err)
+		}
+		if count != len(in.DeptIds) {
+			return gerror.New("部门不存在或不属于角色所在租户")
+		}
+	}
+
+	// 开启事务更新
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		updateData := g.Map{
+			"data_scope": in.DataScope,
+			"updated_at": gtime.Now(),
+		}
+		if identity := service.Middleware().GetCurrentUser(ctx); identity != nil {
+			updateData["updated_by"] = identity.Id
+		}
+
+		_, err := tx.Model("sys_roles").Where("id = ?", role.Id).Data(updateData).Update()
+		if err != nil {
+			return gerror.Newf("更新角色数据权限失败: %v", err)
+		}
+		return s.updateRoleDepts(ctx, tx, tenantId, role.Id, in.DeptIds)
+	})
+	if err != nil {
+		return err
+	}
+
+	service.Middleware().LogAudit(ctx, "UPDATE_DATA_SCOPE", "role", "success", role.Id, in.DataScope, in.DeptIds)
+	return nil
 }
 
 // GetRoleOptions 获取角色选项
@@ -724,10 +977,15 @@ func (s *sRole) checkRoleHasUsers(ctx context.Context, roleId int64) (bool, erro
 	return count > 0, nil
 }
 
-// getRoleMenuIds 获取角色的菜单ID列表
+// getRoleMenuIds 获取角色直接分配的菜单ID列表
 func (s *sRole) getRoleMenuIds(ctx context.Context, roleId int64) ([]int64, error) {
+	return s.getRolesMenuIds(ctx, []int64{roleId})
+}
+
+// getRolesMenuIds 获取多个角色的菜单ID列表，已去重
+func (s *sRole) getRolesMenuIds(ctx context.Context, roleIds []int64) ([]int64, error) {
 	var menuIds []int64
-	result, err := g.DB().Model("sys_role_menus").Fields("menu_id").Where("role_id = ?", roleId).Array()
+	result, err := g.DB().Model("sys_role_menus").Fields("menu_id").Where("role_id IN(?)", roleIds).Distinct().Array()
 	if err != nil {
 		return nil, gerror.Newf("查询角色菜单权限失败: %v", err)
 	}
@@ -742,16 +1000,21 @@ func (s *sRole) getRoleMenuIds(ctx context.Context, roleId int64) ([]int64, erro
 	return menuIds, nil
 }
 
-// getRolePermissions 获取角色的权限标识列表
+// getRolePermissions 获取角色直接分配的权限标识列表
 func (s *sRole) getRolePermissions(ctx context.Context, roleId int64) ([]string, error) {
+	return s.getRolesPermissions(ctx, []int64{roleId})
+}
+
+// getRolesPermissions 获取多个角色的权限标识列表，已去重
+func (s *sRole) getRolesPermissions(ctx context.Context, roleIds []int64) ([]string, error) {
 	var permissions []string
 
 	sql := `SELECT DISTINCT m.permission 
 			FROM sys_role_menus rm 
-			JOIN menus m ON rm.menu_id = m.id 
-			WHERE rm.role_id = ? AND m.status = 1 AND m.permission != ''`
+			JOIN sys_menus m ON rm.menu_id = m.id 
+			WHERE rm.role_id IN(?) AND m.status = 1 AND m.permission != ''`
 
-	result, err := g.DB().Raw(sql, roleId).Array()
+	result, err := g.DB().Raw(sql, roleIds).Array()
 	if err != nil {
 		return nil, gerror.Newf("查询角色权限标识失败: %v", err)
 	}
@@ -808,6 +1071,166 @@ func (s *sRole) updateRoleMenus(ctx context.Context, tx gdb.TX, roleId int64, me
 	return nil
 }
 
+// getManagedRole 获取当前用户可管理的角色及其租户ID，非系统管理员只能管理本租户的角色
+func (s *sRole) getManagedRole(ctx context.Context, roleId int64) (*entity.Role, int64, error) {
+	role, err := s.getRoleById(ctx, roleId)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tenantId, err := g.DB().Model("sys_roles").Where("id = ?", roleId).Value("tenant_id")
+	if err != nil {
+		return nil, 0, gerror.Newf("查询角色租户失败: %v", err)
+	}
+
+	identity := service.Middleware().GetCurrentUser(ctx)
+	if identity == nil {
+		return nil, 0, gerror.New("用户未登录")
+	}
+	if !identity.IsSystemAdmin() && identity.TenantId != tenantId.Int64() {
+		return nil, 0, gerror.New("角色不存在")
+	}
+	return role, tenantId.Int64(), nil
+}
+
+// getRoleDeptIds 获取角色自定义数据权限的部门ID列表
+func (s *sRole) getRoleDeptIds(ctx context.Context, roleId int64) ([]int64, error) {
+	result, err := g.DB().Model("sys_role_depts").Fields("dept_id").Where("role_id = ?", roleId).Array()
+	if err != nil {
+		return nil, gerror.Newf("查询角色数据权限部门失败: %v", err)
+	}
+
+	deptIds := make([]int64, 0, len(result))
+	for _, v := range result {
+		if id := v.Int64(); id > 0 {
+			deptIds = append(deptIds, id)
+		}
+	}
+	return deptIds, nil
+}
+
+// updateRoleDepts 覆盖角色自定义数据权限的部门
+func (s *sRole) updateRoleDepts(ctx context.Context, tx gdb.TX, tenantId, roleId int64, deptIds []int64) error {
+	_, err := tx.Model("sys_role_depts").Where("role_id = ?", roleId).Delete()
+	if err != nil {
+		return gerror.Newf("删除角色原有数据权限部门失败: %v", err)
+	}
+	if len(deptIds) == 0 {
+		return nil
+	}
+
+	data := make([]g.Map, 0, len(deptIds))
+	for _, deptId := range deptIds {
+		data = append(data, g.Map{
+			"tenant_id":  tenantId,
+			"role_id":    roleId,
+			"dept_id":    deptId,
+			"created_at": gtime.Now(),
+		})
+	}
+
+	if _, err = tx.Model("sys_role_depts").Data(data).Insert(); err != nil {
+		return gerror.Newf("分配角色数据权限部门失败: %v", err)
+	}
+	return nil
+}
+
+// resolveRoleAncestors 校验上级角色并返回角色新的上级角色ID链
+// 上级角色不能是自身或下级角色，tenantId 大于0时上级角色必须属于该租户
+func (s *sRole) resolveRoleAncestors(ctx context.Context, roleId, parentId, tenantId int64) ([]int64, error) {
+	if parentId == 0 {
+		return []int64{}, nil
+	}
+
+	parent, parentTenantId, err := s.getManagedRole(ctx, parentId)
+	if err != nil {
+		return nil, gerror.New("上级角色不存在")
+	}
+	if tenantId > 0 && parentTenantId != tenantId {
+		return nil, gerror.New("上级角色必须属于同一租户")
+	}
+
+	// 上级角色是自身或下级角色时会形成循环继承
+	path := parent.Path()
+	for _, id := range path {
+		if id == roleId {
+			return nil, gerror.New("不能将自身或下级角色设为上级角色")
+		}
+	}
+	if len(path)+1 > entity.RoleMaxDepth {
+		return nil, gerror.Newf("角色继承不能超过%d级", entity.RoleMaxDepth)
+	}
+	return path, nil
+}
+
+// rebaseRoleDescendants 计算角色调整上级后下级角色的新ID链
+// 下级角色的ID链 = 新的ID链 + 当前角色 + 原ID链中当前角色之后的部分
+func (s *sRole) rebaseRoleDescendants(ctx context.Context, role *entity.Role, newAncestors []int64) (map[int64]string, error) {
+	var descendants []*entity.Role
+	err := g.DB().Model("sys_roles").Where("FIND_IN_SET(?, ancestors) AND deleted_at IS NULL", role.Id).Scan(&descendants)
+	if err != nil {
+		return nil, gerror.Newf("查询下级角色失败: %v", err)
+	}
+
+	oldDepth := len(role.AncestorIds())
+	updates := make(map[int64]string, len(descendants))
+	for _, child := range descendants {
+		childAncestors := child.AncestorIds()
+		if len(childAncestors) <= oldDepth {
+			continue
+		}
+		path := append(append([]int64{}, newAncestors...), childAncestors[oldDepth:]...)
+		if len(path)+1 > entity.RoleMaxDepth {
+			return nil, gerror.Newf("角色继承不能超过%d级", entity.RoleMaxDepth)
+		}
+		updates[child.Id] = entity.FormatDeptAncestors(path)
+	}
+	return updates, nil
+}
+
+// getRoleDescendantIds 获取继承该角色的所有下级角色ID
+func (s *sRole) getRoleDescendantIds(ctx context.Context, roleId int64) ([]int64, error) {
+	result, err := g.DB().Model("sys_roles").
+		Fields("id").
+		Where("FIND_IN_SET(?, ancestors) AND deleted_at IS NULL", roleId).
+		Array()
+	if err != nil {
+		return nil, gerror.Newf("查询下级角色失败: %v", err)
+	}
+
+	ids := make([]int64, 0, len(result))
+	for _, v := range result {
+		ids = append(ids, v.Int64())
+	}
+	return ids, nil
+}
+
+// getRoleAncestors 获取角色的上级角色链，从顶级角色到直属上级
+func (s *sRole) getRoleAncestors(ctx context.Context, role *entity.Role) ([]*entity.Role, error) {
+	ancestorIds := role.AncestorIds()
+	if len(ancestorIds) == 0 {
+		return nil, nil
+	}
+
+	var roles []*entity.Role
+	err := g.DB().Model("sys_roles").Where("id IN(?) AND deleted_at IS NULL", ancestorIds).Scan(&roles)
+	if err != nil {
+		return nil, gerror.Newf("查询上级角色失败: %v", err)
+	}
+
+	roleMap := make(map[int64]*entity.Role, len(roles))
+	for _, r := range roles {
+		roleMap[r.Id] = r
+	}
+	ancestors := make([]*entity.Role, 0, len(roles))
+	for _, id := range ancestorIds {
+		if r, ok := roleMap[id]; ok {
+			ancestors = append(ancestors, r)
+		}
+	}
+	return ancestors, nil
+}
+
 // getCurrentUserId 获取当前用户ID（这里需要根据实际的认证机制实现）
 func (s *sRole) getCurrentUserId(ctx context.Context) int64 {
 	// 这里应该从JWT token或session中获取当前用户ID
@@ -815,34 +1238,88 @@ func (s *sRole) getCurrentUserId(ctx context.Context) int64 {
 	return 1
 }
 
-// AssignUserRoles 分配用户角色
+// AssignUserRoles 为用户追加角色，已分配的角色保持不变，用户没有主要角色时第一个新角色设为主要角色
 func (s *sRole) AssignUserRoles(ctx context.Context, userId int64, roleIds []int64, assignedBy int64) error {
+	return s.saveUserRoles(ctx, userId, roleIds, assignedBy, false)
+}
+
+// ReplaceUserRoles 重新分配用户角色，原有角色全部移除，第一个角色设为主要角色
+func (s *sRole) ReplaceUserRoles(ctx context.Context, userId int64, roleIds []int64, assignedBy int64) error {
+	return s.saveUserRoles(ctx, userId, roleIds, assignedBy, true)
+}
+
+// saveUserRoles 保存用户角色关联，replace为true时先清除原有角色
+func (s *sRole) saveUserRoles(ctx context.Context, userId int64, roleIds []int64, assignedBy int64, replace bool) error {
 	if len(roleIds) == 0 {
 		return gerror.New("角色ID列表不能为空")
 	}
 
 	// 开启事务
-	return g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
-		// 批量插入用户角色关联
+	err := g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		user, err := tx.Model("sys_users").Fields("tenant_id").Where("id = ?", userId).One()
+		if err != nil {
+			return gerror.Newf("查询用户失败: %v", err)
+		}
+		if user.IsEmpty() {
+			return gerror.New("用户不存在")
+		}
+
+		hasPrimary := false
+		assigned := make(map[int64]bool)
+		if replace {
+			if _, err = tx.Model("sys_user_roles").Where("user_id = ?", userId).Delete(); err != nil {
+				return gerror.Newf("清除用户角色失败: %v", err)
+			}
+		} else {
+			existing, err := tx.Model("sys_user_roles").Fields("role_id", "is_primary").Where("user_id = ?", userId).All()
+			if err != nil {
+				return gerror.Newf("查询用户角色失败: %v", err)
+			}
+			for _, record := range existing {
+				assigned[record["role_id"].Int64()] = true
+				if record["is_primary"].Int() == 1 {
+					hasPrimary = true
+				}
+			}
+		}
+
+		// 批量插入用户角色关联，跳过已分配的角色避免违反唯一索引
 		var data []g.Map
-		for i, roleId := range roleIds {
+		for _, roleId := range roleIds {
+			if assigned[roleId] {
+				continue
+			}
+			assigned[roleId] = true
 			data = append(data, g.Map{
+				"tenant_id":   user["tenant_id"].Uint64(),
 				"user_id":     userId,
 				"role_id":     roleId,
-				"is_primary":  gconv.Int(i == 0), // 第一个角色设为主要角色
+				"is_primary":  gconv.Int(!hasPrimary && len(data) == 0),
 				"assigned_by": assignedBy,
 				"created_at":  gtime.Now(),
 				"updated_at":  gtime.Now(),
 			})
 		}
+		if len(data) == 0 {
+			return nil
+		}
 
-		_, err := tx.Model("sys_user_roles").Data(data).Insert()
+		_, err = tx.Model("sys_user_roles").Data(data).Insert()
 		if err != nil {
 			return gerror.Newf("分配用户角色失败: %v", err)
 		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// 在调用方的事务中时由调用方在提交后失效
+	if gdb.TXFromCtx(ctx, g.DB().GetGroup()) == nil {
+		s.invalidateUserPerms(ctx, userId)
+	}
+	return nil
 }
 
 // RemoveUserRoles 移除用户角色
@@ -856,6 +1333,7 @@ func (s *sRole) RemoveUserRoles(ctx context.Context, userId int64, roleIds []int
 		return gerror.Newf("移除用户角色失败: %v", err)
 	}
 
+	s.invalidateUserPerms(ctx, userId)
 	return nil
 }
 
@@ -908,17 +1386,14 @@ func (s *sRole) SetUserPrimaryRole(ctx context.Context, userId int64, roleId int
 	})
 }
 
-// CheckUserPermission 检查用户权限
-func (s *sRole) CheckUserPermission(ctx context.Context, userId int64, permission string) (bool, error) {
-	sql := `SELECT COUNT(*) FROM sys_user_roles ur
-			JOIN sys_role_menus rm ON ur.role_id = rm.role_id
+// CheckUserPermission 检查用户权限，角色拥有 role:* 等通配权限时同样视为拥有，包括从上级角色继承的权限
+func (s *sRole) CheckUserPermission(ctx context.Context, userId int64, required string) (bool, error) {
+	sql := `SELECT COUNT(*) FROM ` + userRoleChainSQL + `
+			JOIN sys_role_menus rm ON pr.id = rm.role_id
 			JOIN sys_menus m ON rm.menu_id = m.id
-			JOIN sys_roles r ON ur.role_id = r.id
-			WHERE ur.user_id = ? AND m.permission = ? 
-			AND r.status = 1 AND m.status = 1 
-			AND r.deleted_at IS NULL`
+			WHERE ur.user_id = ? AND m.permission IN(?) AND m.status = 1`
 
-	count, err := g.DB().Raw(sql, userId, permission).Count()
+	count, err := g.DB().Raw(sql, userId, permission.Patterns(required)).Count()
 	if err != nil {
 		return false, gerror.Newf("检查用户权限失败: %v", err)
 	}
@@ -941,17 +1416,14 @@ func (s *sRole) CheckUserRole(ctx context.Context, userId int64, roleCode string
 	return count > 0, nil
 }
 
-// GetUserPermissions 获取用户权限列表
+// GetUserPermissions 获取用户权限列表，包括从上级角色继承的权限
 func (s *sRole) GetUserPermissions(ctx context.Context, userId int64) ([]string, error) {
 	var permissions []string
 
-	sql := `SELECT DISTINCT m.permission FROM sys_user_roles ur
-			JOIN sys_role_menus rm ON ur.role_id = rm.role_id
+	sql := `SELECT DISTINCT m.permission FROM ` + userRoleChainSQL + `
+			JOIN sys_role_menus rm ON pr.id = rm.role_id
 			JOIN sys_menus m ON rm.menu_id = m.id
-			JOIN sys_roles r ON ur.role_id = r.id
-			WHERE ur.user_id = ? AND m.permission != ''
-			AND r.status = 1 AND m.status = 1 
-			AND r.deleted_at IS NULL`
+			WHERE ur.user_id = ? AND m.permission != '' AND m.status = 1`
 
 	result, err := g.DB().Raw(sql, userId).Array()
 	if err != nil {
@@ -968,14 +1440,14 @@ func (s *sRole) GetUserPermissions(ctx context.Context, userId int64) ([]string,
 	return permissions, nil
 }
 
-// GetUserMenus 获取用户菜单ID列表
+// GetUserMenus 获取用户启用的菜单ID列表，包括从上级角色继承的菜单
 func (s *sRole) GetUserMenus(ctx context.Context, userId int64) ([]int64, error) {
 	var menuIds []int64
 
-	sql := `SELECT DISTINCT rm.menu_id FROM sys_user_roles ur
-			JOIN sys_role_menus rm ON ur.role_id = rm.role_id
-			JOIN sys_roles r ON ur.role_id = r.id
-			WHERE ur.user_id = ? AND r.status = 1 AND r.deleted_at IS NULL`
+	sql := `SELECT DISTINCT rm.menu_id FROM ` + userRoleChainSQL + `
+			JOIN sys_role_menus rm ON pr.id = rm.role_id
+			JOIN sys_menus m ON rm.menu_id = m.id
+			WHERE ur.user_id = ? AND m.status = 1`
 
 	result, err := g.DB().Raw(sql, userId).Array()
 	if err != nil {
@@ -992,41 +1464,35 @@ func (s *sRole) GetUserMenus(ctx context.Context, userId int64) ([]int64, error)
 	return menuIds, nil
 }
 
-// GetUserDataScope 获取用户数据权限范围
+// GetUserDataScope 获取用户数据权限范围，取所有角色及其上级角色中范围最大的，没有角色时只能访问本人数据
 func (s *sRole) GetUserDataScope(ctx context.Context, userId int64) (int, error) {
-	sql := `SELECT MIN(r.data_scope) FROM sys_user_roles ur
-			JOIN sys_roles r ON ur.role_id = r.id
-			WHERE ur.user_id = ? AND r.status = 1 AND r.deleted_at IS NULL`
+	sql := `SELECT DISTINCT pr.data_scope FROM ` + userRoleChainSQL + `
+			WHERE ur.user_id = ?`
 
-	var dataScope int
-	err := g.DB().Raw(sql, userId).Scan(&dataScope)
+	result, err := g.DB().Raw(sql, userId).Array()
 	if err != nil {
 		return entity.DataScopeSelf, gerror.Newf("获取用户数据权限范围失败: %v", err)
 	}
 
-	// 如果没有角色，返回最小权限
-	if dataScope == 0 {
-		dataScope = entity.DataScopeSelf
+	scopes := make([]int, 0, len(result))
+	for _, v := range result {
+		scopes = append(scopes, v.Int())
 	}
-
-	return dataScope, nil
+	return entity.WidestDataScope(scopes...), nil
 }
 
 // CheckUsersPermission 批量检查用户权限
-func (s *sRole) CheckUsersPermission(ctx context.Context, userIds []int64, permission string) (map[int64]bool, error) {
+func (s *sRole) CheckUsersPermission(ctx context.Context, userIds []int64, required string) (map[int64]bool, error) {
 	if len(userIds) == 0 {
 		return make(map[int64]bool), nil
 	}
 
-	sql := `SELECT DISTINCT ur.user_id FROM sys_user_roles ur
-			JOIN sys_role_menus rm ON ur.role_id = rm.role_id
+	sql := `SELECT DISTINCT ur.user_id FROM ` + userRoleChainSQL + `
+			JOIN sys_role_menus rm ON pr.id = rm.role_id
 			JOIN sys_menus m ON rm.menu_id = m.id
-			JOIN sys_roles r ON ur.role_id = r.id
-			WHERE ur.user_id IN(?) AND m.permission = ? 
-			AND r.status = 1 AND m.status = 1 
-			AND r.deleted_at IS NULL`
+			WHERE ur.user_id IN(?) AND m.permission IN(?) AND m.status = 1`
 
-	result, err := g.DB().Raw(sql, userIds, permission).Array()
+	result, err := g.DB().Raw(sql, userIds, permission.Patterns(required)).Array()
 	if err != nil {
 		return nil, gerror.Newf("批量检查用户权限失败: %v", err)
 	}
@@ -1055,20 +1521,17 @@ func (s *sRole) CheckUsersPermission(ctx context.Context, userIds []int64, permi
 }
 
 // FilterUsersByPermission 根据权限过滤用户
-func (s *sRole) FilterUsersByPermission(ctx context.Context, userIds []int64, permission string) ([]int64, error) {
+func (s *sRole) FilterUsersByPermission(ctx context.Context, userIds []int64, required string) ([]int64, error) {
 	if len(userIds) == 0 {
 		return []int64{}, nil
 	}
 
-	sql := `SELECT DISTINCT ur.user_id FROM sys_user_roles ur
-			JOIN sys_role_menus rm ON ur.role_id = rm.role_id
+	sql := `SELECT DISTINCT ur.user_id FROM ` + userRoleChainSQL + `
+			JOIN sys_role_menus rm ON pr.id = rm.role_id
 			JOIN sys_menus m ON rm.menu_id = m.id
-			JOIN sys_roles r ON ur.role_id = r.id
-			WHERE ur.user_id IN(?) AND m.permission = ? 
-			AND r.status = 1 AND m.status = 1 
-			AND r.deleted_at IS NULL`
+			WHERE ur.user_id IN(?) AND m.permission IN(?) AND m.status = 1`
 
-	queryResult, err := g.DB().Raw(sql, userIds, permission).Array()
+	queryResult, err := g.DB().Raw(sql, userIds, permission.Patterns(required)).Array()
 	if err != nil {
 		return nil, gerror.Newf("根据权限过滤用户失败: %v", err)
 	}
@@ -1083,3 +1546,18 @@ func (s *sRole) FilterUsersByPermission(ctx context.Context, userIds []int64, pe
 
 	return result, nil
 }
+
+// invalidateRolePerms 角色权限变化后失效拥有这些角色及其下级角色的用户权限快照
+// 变更已提交，失效失败只记录日志，快照到期后自动重新加载
+func (s *sRole) invalidateRolePerms(ctx context.Context, roleIds ...int64) {
+	if err := service.PermCache().InvalidateRoles(ctx, roleIds...); err != nil {
+		g.Log().Warningf(ctx, "失效角色%v的用户权限快照失败: %v", roleIds, err)
+	}
+}
+
+// invalidateUserPerms 用户角色变化后失效其权限快照
+func (s *sRole) invalidateUserPerms(ctx context.Context, userIds ...int64) {
+	if err := service.PermCache().InvalidateUsers(ctx, userIds...); err != nil {
+		g.Log().Warningf(ctx, "失效用户%v的权限快照失败: %v", userIds, err)
+	}
+}
@@ -1,11 +1,13 @@
 package api
 
 import (
+	"client-app/internal/library/datascope"
 	"client-app/internal/model/entity"
 	"client-app/internal/model/input/sysin"
 	"client-app/internal/model/output/sysout"
 	"client-app/internal/service"
-	"client-app/utility/encrypt"
+	"client-app/utility/simple"
+	"client-app/utility/transport"
 	"context"
 	"encoding/json"
 	"github.com/gogf/gf/v2/database/gdb"
@@ -30,8 +32,17 @@ func init() {
 
 // GetTenantList 获取租户列表
 func (s *sTenant) GetTenantList(ctx context.Context, in *sysin.TenantListInp) (*sysout.TenantListModel, error) {
-	// 构建查询条件
-	m := g.DB().Model("sys_tenants").Where("deleted_at IS NULL")
+	// 构建查询条件，非系统管理员只能查看本租户，并按数据权限过滤
+	identity := service.Middleware().GetCurrentUser(ctx)
+	if identity == nil {
+		return nil, gerror.New("用户未登录")
+	}
+	m, err := datascope.Filter(ctx, g.DB().Model("sys_tenants").Where("deleted_at IS NULL"), identity, datascope.Options{
+		TenantField: "id",
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	// 按条件筛选
 	if in.Name != "" {
@@ -144,6 +155,20 @@ func (s *sTenant) CreateTenant(ctx context.Context, in *sysin.CreateTenantInp) (
 		return nil, gerror.New("管理员用户名已存在")
 	}
 
+	// 管理员密码支持传输加密，兼容旧版时仍接受明文
+	adminPassword := in.AdminPassword
+	if _, ok := transport.ParseEnvelope(in.AdminPassword); ok || !service.Crypto().LegacyEnabled(ctx) {
+		if adminPassword, err = service.Crypto().Decrypt(ctx, in.AdminPassword); err != nil {
+			return nil, err
+		}
+	}
+
+	// 新租户还没有租户配置，按系统密码策略校验管理员密码
+	passwordPolicy := getPasswordPolicy(ctx, 0)
+	if err = passwordPolicy.Validate(adminPassword, in.AdminName); err != nil {
+		return nil, gerror.Wrap(err, "管理员密码不符合要求")
+	}
+
 	// 开启事务
 	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
 		// 1. 创建租户记录
@@ -173,22 +198,25 @@ func (s *sTenant) CreateTenant(ctx context.Context, in *sysin.CreateTenantInp) (
 		}
 
 		// 2. 创建管理员用户
-		salt := encrypt.GenerateSalt()
-		hashedPassword := encrypt.HashPassword(in.AdminPassword, salt)
+		hashedPassword, err := simple.HashPassword(ctx, adminPassword)
+		if err != nil {
+			return gerror.Wrap(err, "生成管理员密码失败")
+		}
 
 		adminUserData := g.Map{
-			"tenant_id":  tenantId,
-			"username":   in.AdminName,
-			"password":   hashedPassword,
-			"salt":       salt,
-			"email":      in.AdminEmail,
-			"real_name":  "租户管理员",
-			"nickname":   "管理员",
-			"status":     1,
-			"created_by": 0,
-			"updated_by": 0,
-			"created_at": gtime.Now(),
-			"updated_at": gtime.Now(),
+			"tenant_id":           tenantId,
+			"username":            in.AdminName,
+			"password":            hashedPassword,
+			"salt":                "",
+			"password_changed_at": gtime.Now(),
+			"email":               in.AdminEmail,
+			"real_name":           "租户管理员",
+			"nickname":            "管理员",
+			"status":              1,
+			"created_by":          0,
+			"updated_by":          0,
+			"created_at":          gtime.Now(),
+			"updated_at":          gtime.Now(),
 		}
 
 		adminResult, err := tx.Model("sys_users").Data(adminUserData).Insert()
@@ -200,6 +228,9 @@ func (s *sTenant) CreateTenant(ctx context.Context, in *sysin.CreateTenantInp) (
 		if err != nil {
 			return gerror.Wrap(err, "获取管理员用户ID失败")
 		}
+		if err = savePasswordHistory(ctx, tx, adminUserId, hashedPassword, passwordPolicy); err != nil {
+			return err
+		}
 
 		// 3. 更新租户的管理员用户ID
 		_, err = tx.Model("sys_tenants").Where("id", tenantId).Data(g.Map{
@@ -433,10 +464,35 @@ func (s *sTenant) DeleteTenant(ctx context.Context, in *sysin.DeleteTenantInp) e
 			return gerror.Wrap(err, "删除租户角色菜单关联失败")
 		}
 
+		// 删除租户的角色部门关联
+		_, err = tx.Model("sys_role_depts").Where("tenant_id", in.Id).Delete()
+		if err != nil {
+			return gerror.Wrap(err, "删除租户角色部门关联失败")
+		}
+
+		// 删除租户的部门
+		_, err = tx.Model("sys_depts").Where("tenant_id", in.Id).Delete()
+		if err != nil {
+			return gerror.Wrap(err, "删除租户部门失败")
+		}
+
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	// 租户已删除，其用户的刷新令牌、会话和已签发的访问令牌全部失效
+	if err = service.User().RevokeTenantSessions(ctx, in.Id); err != nil {
+		g.Log().Warningf(ctx, "撤销租户[%d]登录状态失败: %v", in.Id, err)
+	}
+	if err = service.ApiKey().RevokeByTenant(ctx, in.Id); err != nil {
+		g.Log().Warningf(ctx, "撤销租户[%d]API密钥失败: %v", in.Id, err)
+	}
+	if err = service.PermCache().InvalidateTenant(ctx, in.Id); err != nil {
+		g.Log().Warningf(ctx, "失效租户[%d]用户的权限快照失败: %v", in.Id, err)
+	}
+	return nil
 }
 
 // GetTenantDetail 获取租户详情
@@ -527,6 +583,18 @@ func (s *sTenant) UpdateTenantStatus(ctx context.Context, in *sysin.TenantStatus
 		return gerror.Wrap(err, "更新租户状态失败")
 	}
 
+	// 租户被锁定或禁用时，其用户的刷新令牌、会话和已签发的访问令牌全部失效
+	if in.Status != entity.TenantStatusNormal {
+		if err = service.User().RevokeTenantSessions(ctx, in.Id); err != nil {
+			g.Log().Warningf(ctx, "撤销租户[%d]登录状态失败: %v", in.Id, err)
+		}
+		if err = service.ApiKey().RevokeByTenant(ctx, in.Id); err != nil {
+			g.Log().Warningf(ctx, "撤销租户[%d]API密钥失败: %v", in.Id, err)
+		}
+	}
+	if err = service.PermCache().InvalidateTenant(ctx, in.Id); err != nil {
+		g.Log().Warningf(ctx, "失效租户[%d]用户的权限快照失败: %v", in.Id, err)
+	}
 	return nil
 }
 
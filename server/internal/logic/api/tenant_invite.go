@@ -0,0 +1,121 @@
+package api
+
+import (
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
+	"context"
+	"crypto/rand"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// 邀请码字符集，去掉了容易混淆的 0/O、1/I/L
+const inviteCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// CreateInviteCode 创建注册邀请码
+func (s *sTenant) CreateInviteCode(ctx context.Context, in *sysin.InviteCodeCreateInp) (*sysout.InviteCodeModel, error) {
+	operator := service.Middleware().GetCurrentUser(ctx)
+	if operator == nil {
+		return nil, gerror.New("用户未登录")
+	}
+	tenantId := s.inviteTenantId(operator.IsSystemAdmin(), operator.TenantId, in.TenantId)
+
+	code, err := generateInviteCode(8)
+	if err != nil {
+		return nil, err
+	}
+
+	invite := &entity.InviteCode{
+		TenantId:  tenantId,
+		Code:      code,
+		MaxUses:   in.MaxUses,
+		ExpiresAt: in.ExpiresAt,
+		Remark:    in.Remark,
+		CreatedBy: operator.Id,
+		CreatedAt: gtime.Now(),
+	}
+	invite.Id, err = g.DB().Model("sys_invite_codes").Data(g.Map{
+		"tenant_id":  invite.TenantId,
+		"code":       invite.Code,
+		"max_uses":   invite.MaxUses,
+		"expires_at": invite.ExpiresAt,
+		"remark":     invite.Remark,
+		"created_by": invite.CreatedBy,
+		"created_at": invite.CreatedAt,
+	}).InsertAndGetId()
+	if err != nil {
+		return nil, gerror.Wrap(err, "创建邀请码失败")
+	}
+
+	return convertInviteCode(invite), nil
+}
+
+// GetInviteCodeList 获取注册邀请码列表
+func (s *sTenant) GetInviteCodeList(ctx context.Context, in *sysin.InviteCodeListInp) (*sysout.InviteCodeListModel, error) {
+	operator := service.Middleware().GetCurrentUser(ctx)
+	if operator == nil {
+		return nil, gerror.New("用户未登录")
+	}
+	tenantId := s.inviteTenantId(operator.IsSystemAdmin(), operator.TenantId, in.TenantId)
+
+	m := g.DB().Model("sys_invite_codes").Where("tenant_id", tenantId)
+	total, err := m.Count()
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取邀请码总数失败")
+	}
+
+	var list []*entity.InviteCode
+	err = m.Order("id DESC").Limit(in.PageSize).Offset((in.Page - 1) * in.PageSize).Scan(&list)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询邀请码列表失败")
+	}
+
+	res := &sysout.InviteCodeListModel{
+		List:     make([]*sysout.InviteCodeModel, 0, len(list)),
+		Total:    int64(total),
+		Page:     in.Page,
+		PageSize: in.PageSize,
+	}
+	for _, invite := range list {
+		res.List = append(res.List, convertInviteCode(invite))
+	}
+	return res, nil
+}
+
+// inviteTenantId 邀请码所属租户，只有系统管理员可以操作其他租户
+func (s *sTenant) inviteTenantId(isSystemAdmin bool, operatorTenantId int64, tenantId uint64) uint64 {
+	if isSystemAdmin && tenantId > 0 {
+		return tenantId
+	}
+	return uint64(operatorTenantId)
+}
+
+// convertInviteCode 将邀请码实体转换为输出模型
+func convertInviteCode(invite *entity.InviteCode) *sysout.InviteCodeModel {
+	return &sysout.InviteCodeModel{
+		Id:        invite.Id,
+		TenantId:  invite.TenantId,
+		Code:      invite.Code,
+		MaxUses:   invite.MaxUses,
+		UsedCount: invite.UsedCount,
+		ExpiresAt: invite.ExpiresAt,
+		Remark:    invite.Remark,
+		CreatedAt: invite.CreatedAt,
+	}
+}
+
+// generateInviteCode 生成随机邀请码
+func generateInviteCode(length int) (string, error) {
+	buf := make([]byte, length)
+	if _, err := rand.Read(buf); err != nil {
+		return "", gerror.Wrap(err, "生成邀请码失败")
+	}
+	for i := range buf {
+		buf[i] = inviteCodeAlphabet[int(buf[i])%len(inviteCodeAlphabet)]
+	}
+	return string(buf), nil
+}
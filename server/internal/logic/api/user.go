@@ -9,18 +9,11 @@ import (
 	"client-app/utility/captcha"
 	"client-app/utility/simple"
 	"context"
-	"crypto/rand"
-	"encoding/base64"
-	"fmt"
-	"time"
 
-	"github.com/gogf/gf/v2/crypto/gmd5"
 	"github.com/gogf/gf/v2/database/gdb"
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
-	"github.com/gogf/gf/v2/os/gcache"
 	"github.com/gogf/gf/v2/os/gtime"
-	"github.com/gogf/gf/v2/util/gconv"
 )
 
 type sUser struct{}
@@ -42,17 +35,29 @@ func (s *sUser) Login(ctx context.Context, in *sysin.UserLoginInp) (res *sysout.
 // Logout 用户退出
 func (s *sUser) Logout(ctx context.Context, in *sysin.UserLogoutInp) error {
 	// 从token中解析用户信息
-	secretKey := simple.GetJWTSecretKey(ctx)
-	payload, err := simple.ParseJWTToken(in.Token, secretKey)
+	payload, err := simple.ParseJWTToken(ctx, in.Token)
 	if err != nil {
 		// 即使token无效也继续执行，确保退出操作成功
 		g.Log().Warningf(ctx, "解析退出token失败: %v", err)
 	}
 
-	// 将token加入黑名单（可选，这里简化处理）
+	// 撤销访问令牌，ApiAuth 会拒绝已撤销的令牌
 	if payload != nil {
-		cacheKey := fmt.Sprintf("blacklist_token_%s", in.Token)
-		gcache.Set(ctx, cacheKey, 1, time.Duration(payload.Exp-time.Now().Unix())*time.Second)
+		if err = service.TokenRevocation().RevokeToken(ctx, payload.Jti, payload.Exp); err != nil {
+			return gerror.Wrap(err, "撤销访问令牌失败")
+		}
+		// 同时结束登录会话，会话下的刷新令牌不能再使用
+		if payload.Sid != "" {
+			if err = s.revokeSession(ctx, payload.Sid, entity.RefreshTokenRevokeLogout); err != nil {
+				return err
+			}
+		}
+		// 模拟登录令牌没有会话，退出即结束模拟登录
+		if payload.Act != nil {
+			if err = s.endImpersonation(ctx, payload, entity.ImpersonationEndLogout); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -77,15 +82,15 @@ func (s *sUser) GetProfile(ctx context.Context, userId int64) (res *sysout.UserM
 
 // RefreshToken 刷新访问令牌
 func (s *sUser) RefreshToken(ctx context.Context, refreshToken string) (res *service.TokenInfo, err error) {
-	// 验证刷新令牌
-	userId, err := s.validateRefreshToken(ctx, refreshToken)
+	// 验证并轮换刷新令牌，旧令牌被标记为已使用
+	record, err := s.rotateRefreshToken(ctx, refreshToken)
 	if err != nil {
 		return nil, err
 	}
 
 	// 获取用户信息
 	var user *entity.User
-	err = g.DB().Model("sys_users").Where("id = ? AND deleted_at IS NULL", userId).Scan(&user)
+	err = g.DB().Model("sys_users").Where("id = ? AND deleted_at IS NULL", record.UserId).Scan(&user)
 	if err != nil {
 		return nil, gerror.Newf("查询用户信息失败: %v", err)
 	}
@@ -99,6 +104,16 @@ func (s *sUser) RefreshToken(ctx context.Context, refreshToken string) (res *ser
 		return nil, gerror.New("用户状态异常，无法刷新令牌")
 	}
 
+	// 租户被禁用、锁定、过期或删除后不能再刷新令牌
+	var tenantCode string
+	if record.TenantId > 0 {
+		tenant, err := s.getTenantById(ctx, record.TenantId)
+		if err != nil {
+			return nil, err
+		}
+		tenantCode = tenant.Code
+	}
+
 	// 获取用户角色信息
 	userRole, err := s.getUserPrimaryRole(ctx, user.Id)
 	if err != nil {
@@ -107,29 +122,34 @@ func (s *sUser) RefreshToken(ctx context.Context, refreshToken string) (res *ser
 
 	// 生成新的访问令牌
 	payload := &simple.JWTPayload{
-		UserId:   user.Id,
-		Username: user.Username,
-		RoleId:   userRole.RoleId,
-		RoleKey:  userRole.RoleCode,
-		DeptId:   user.DeptId,
-		App:      consts.AppApi,
-	}
-
-	secretKey := simple.GetJWTSecretKey(ctx)
-	newAccessToken, err := simple.GenerateJWTToken(payload, secretKey)
+		UserId:      user.Id,
+		TenantId:    int64(record.TenantId),
+		TenantCode:  tenantCode,
+		Username:    user.Username,
+		RoleId:      userRole.RoleId,
+		RoleKey:     userRole.RoleCode,
+		DeptId:      user.DeptId,
+		App:         consts.AppApi,
+		Sid:         record.FamilyId,
+		PermVersion: user.PermVersion,
+	}
+	// 刷新令牌不能绕过密码过期限制
+	payload.Mcp = s.isPasswordExpired(ctx, user.Id, record.TenantId)
+
+	newAccessToken, err := simple.GenerateJWTToken(ctx, payload)
 	if err != nil {
 		return nil, gerror.Newf("生成访问令牌失败: %v", err)
 	}
 
-	// 生成新的刷新令牌
-	newRefreshToken, err := s.generateRefreshToken(ctx, user.Id)
+	// 在同一令牌族中生成新的刷新令牌
+	newRefreshToken, err := s.generateRefreshToken(ctx, user.Id, record.TenantId, record.FamilyId, record.Id)
 	if err != nil {
 		return nil, gerror.Newf("生成刷新令牌失败: %v", err)
 	}
 
-	// 删除旧的刷新令牌
-	cacheKey := fmt.Sprintf("refresh_token_%s", refreshToken)
-	gcache.Remove(ctx, cacheKey)
+	if err = service.Session().Renew(ctx, record.FamilyId); err != nil {
+		g.Log().Warningf(ctx, "延长登录会话失败: %v", err)
+	}
 
 	res = &service.TokenInfo{
 		AccessToken:  newAccessToken,
@@ -141,7 +161,7 @@ func (s *sUser) RefreshToken(ctx context.Context, refreshToken string) (res *ser
 }
 
 // ChangePassword 修改密码
-func (s *sUser) ChangePassword(ctx context.Context, userId int64, oldPassword, newPassword string) error {
+func (s *sUser) ChangePassword(ctx context.Context, userId int64, oldPassword, newPassword, confirmPassword string) error {
 	// 获取用户信息
 	var user *entity.User
 	err := g.DB().Model("sys_users").Where("id = ?", userId).Scan(&user)
@@ -154,69 +174,53 @@ func (s *sUser) ChangePassword(ctx context.Context, userId int64, oldPassword, n
 	}
 
 	// 验证原密码
-	if err := simple.CheckPassword(oldPassword, user.Salt, user.Password); err != nil {
+	plainOldPassword, err := service.Crypto().Decrypt(ctx, oldPassword)
+	if err != nil {
+		return err
+	}
+	if _, err = simple.CheckPassword(ctx, plainOldPassword, user.Salt, user.Password); err != nil {
 		return gerror.New("原密码错误")
 	}
 
-	// 生成新密码hash
-	newSalt := gmd5.MustEncryptString(gconv.String(time.Now().UnixNano()))
-	decryptedPassword, err := simple.DecryptText(newPassword)
+	tenantId, err := g.DB().Model("sys_users").Where("id = ?", userId).Value("tenant_id")
 	if err != nil {
-		return gerror.Newf("密码解密失败: %v", err)
+		return gerror.Newf("查询用户租户失败: %v", err)
 	}
-	newPasswordHash := gmd5.MustEncryptString(decryptedPassword + newSalt)
 
-	// 更新密码
-	_, err = g.DB().Model("sys_users").Where("id = ?", userId).Update(g.Map{
-		"password":   newPasswordHash,
-		"salt":       newSalt,
-		"updated_at": gtime.Now(),
-	})
+	// 按租户密码策略校验并生成新密码hash
+	newPasswordHash, policy, err := s.hashNewPassword(ctx, tenantId.Uint64(), userId, user.Username, newPassword, confirmPassword)
 	if err != nil {
-		return gerror.Newf("更新密码失败: %v", err)
+		return err
 	}
 
-	return nil
+	// 更新密码
+	return s.updatePassword(ctx, userId, newPasswordHash, policy)
 }
 
-// GenerateCaptcha 生成验证码
-func (s *sUser) GenerateCaptcha(ctx context.Context) (captchaId, captchaImage string, err error) {
-	// 生成验证码ID
-	captchaId = gmd5.MustEncryptString(gconv.String(time.Now().UnixNano()))
-
-	// 生成4位数字验证码
-	code := captcha.GenerateCode(4)
-
-	// 将验证码存储到缓存中，有效期5分钟
-	cacheKey := fmt.Sprintf("captcha_%s", captchaId)
-	gcache.Set(ctx, cacheKey, code, 5*time.Minute)
-
-	// 生成验证码图片
-	captchaImage, err = captcha.GenerateImage(code)
-	if err != nil {
-		return "", "", gerror.Newf("生成验证码图片失败: %v", err)
+// GenerateCaptcha 生成验证码，验证码类型按租户配置选择，请求语音验证码时优先使用语音
+func (s *sUser) GenerateCaptcha(ctx context.Context, in *sysin.CaptchaInp) (res *sysout.CaptchaModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+	if in.Audio {
+		return service.Captcha().Generate(ctx, captcha.TypeAudio)
 	}
 
-	return captchaId, captchaImage, nil
+	var captchaType string
+	if in.TenantCode != "" {
+		// 租户不存在或不可用时使用默认类型，避免通过验证码接口探测租户
+		if tenant, err := s.getTenantByCode(ctx, in.TenantCode); err == nil {
+			if tenantConfig, err := tenant.ParseConfig(); err == nil {
+				captchaType = tenantConfig.CaptchaType()
+			}
+		}
+	}
+	return service.Captcha().Generate(ctx, captchaType)
 }
 
 // VerifyCaptcha 验证验证码
 func (s *sUser) VerifyCaptcha(ctx context.Context, captchaId, captcha string) error {
-	// 从缓存中获取验证码
-	cacheKey := fmt.Sprintf("captcha_%s", captchaId)
-	cachedCode, err := gcache.Get(ctx, cacheKey)
-	if err != nil {
-		return gerror.New("验证码已过期或不存在")
-	}
-
-	// 验证验证码
-	if gconv.String(cachedCode) != captcha {
-		return gerror.New("验证码错误")
-	}
-
-	// 验证成功后删除验证码
-	gcache.Remove(ctx, cacheKey)
-	return nil
+	return service.Captcha().Verify(ctx, captchaId, captcha)
 }
 
 // GetUserByUsername 根据用户名获取用户
@@ -248,13 +252,40 @@ func (s *sUser) ValidateUser(ctx context.Context, username, password string) (us
 	}
 
 	// 验证密码
-	if err := simple.CheckPassword(password, userEntity.Salt, userEntity.Password); err != nil {
+	plainPassword, err := service.Crypto().Decrypt(ctx, password)
+	if err != nil {
+		return nil, err
+	}
+	needsRehash, err := simple.CheckPassword(ctx, plainPassword, userEntity.Salt, userEntity.Password)
+	if err != nil {
 		return nil, gerror.New("用户名或密码错误")
 	}
+	if needsRehash {
+		s.rehashPassword(ctx, userEntity.Id, plainPassword)
+	}
 
 	return sysout.ConvertToUserModel(userEntity), nil
 }
 
+// rehashPassword 使用当前配置的算法重新生成密码哈希，用于登录时升级历史密码
+// 升级失败不影响本次登录，下次登录时会再次尝试
+// password 为解密后的明文
+func (s *sUser) rehashPassword(ctx context.Context, userId int64, password string) {
+	hash, err := simple.HashPassword(ctx, password)
+	if err != nil {
+		g.Log().Warningf(ctx, "升级用户[%d]密码哈希失败: %v", userId, err)
+		return
+	}
+
+	_, err = g.DB().Model("sys_users").Where("id = ?", userId).Update(g.Map{
+		"password": hash,
+		"salt":     "",
+	})
+	if err != nil {
+		g.Log().Warningf(ctx, "升级用户[%d]密码哈希失败: %v", userId, err)
+	}
+}
+
 // UserRoleWithCode 用户角色信息（包含角色编码）
 type UserRoleWithCode struct {
 	entity.UserRole
@@ -280,40 +311,6 @@ func (s *sUser) getUserPrimaryRole(ctx context.Context, userId int64) (*UserRole
 	return userRoleWithCode, nil
 }
 
-// generateRefreshToken 生成刷新令牌
-func (s *sUser) generateRefreshToken(ctx context.Context, userId int64) (string, error) {
-	// 生成随机字符串作为刷新令牌
-	tokenBytes := make([]byte, 32)
-	_, err := rand.Read(tokenBytes)
-	if err != nil {
-		return "", err
-	}
-
-	token := base64.URLEncoding.EncodeToString(tokenBytes)
-
-	// 将刷新令牌存储到缓存中，有效期7天
-	cacheKey := fmt.Sprintf("refresh_token_%s", token)
-	gcache.Set(ctx, cacheKey, userId, 7*24*time.Hour)
-
-	return token, nil
-}
-
-// validateRefreshToken 验证刷新令牌
-func (s *sUser) validateRefreshToken(ctx context.Context, token string) (int64, error) {
-	cacheKey := fmt.Sprintf("refresh_token_%s", token)
-	userIdVal, err := gcache.Get(ctx, cacheKey)
-	if err != nil {
-		return 0, gerror.New("刷新令牌无效或已过期")
-	}
-
-	userId := gconv.Int64(userIdVal)
-	if userId <= 0 {
-		return 0, gerror.New("刷新令牌无效")
-	}
-
-	return userId, nil
-}
-
 // updateLoginInfo 更新用户登录信息
 func (s *sUser) updateLoginInfo(ctx context.Context, userId int64) error {
 	// 这里可以获取客户端IP等信息
@@ -325,39 +322,19 @@ func (s *sUser) updateLoginInfo(ctx context.Context, userId int64) error {
 	return err
 }
 
-// getUserPermissions 获取用户权限
+// getUserPermissions 获取用户的权限标识和菜单ID，包括从上级角色继承的权限，与鉴权使用的权限快照一致
 func (s *sUser) getUserPermissions(ctx context.Context, userId int64) (permissions []string, menuIds []int64, err error) {
-	// 通过角色获取权限
-	var results []struct {
-		Permission string `json:"permission"`
-		MenuId     int64  `json:"menu_id"`
-	}
-
-	err = g.DB().Model("sys_user_roles ur").
-		LeftJoin("sys_role_menus rm", "ur.role_id = rm.role_id").
-		LeftJoin("sys_menus m", "rm.menu_id = m.id").
-		Where("ur.user_id = ? AND m.deleted_at IS NULL AND m.status = ?", userId, 1).
-		Fields("m.permission, m.id as menu_id").
-		Scan(&results)
-	if err != nil {
+	if permissions, err = service.Role().GetUserPermissions(ctx, userId); err != nil {
 		return nil, nil, err
 	}
-
-	permissions = make([]string, 0)
-	menuIds = make([]int64, 0)
-	permissionSet := make(map[string]bool)
-	menuIdSet := make(map[int64]bool)
-
-	for _, result := range results {
-		if result.Permission != "" && !permissionSet[result.Permission] {
-			permissions = append(permissions, result.Permission)
-			permissionSet[result.Permission] = true
-		}
-		if result.MenuId > 0 && !menuIdSet[result.MenuId] {
-			menuIds = append(menuIds, result.MenuId)
-			menuIdSet[result.MenuId] = true
-		}
+	if menuIds, err = service.Role().GetUserMenus(ctx, userId); err != nil {
+		return nil, nil, err
+	}
+	if permissions == nil {
+		permissions = make([]string, 0)
+	}
+	if menuIds == nil {
+		menuIds = make([]int64, 0)
 	}
-
 	return permissions, menuIds, nil
 }
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"client-app/internal/consts"
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
+	"client-app/utility/simple"
+	"context"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// Impersonate 系统管理员模拟登录租户用户，签发不可刷新的短期令牌，令牌同时携带操作人和目标用户
+func (s *sUser) Impersonate(ctx context.Context, in *sysin.ImpersonateInp) (res *sysout.ImpersonateModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	operator := service.Middleware().GetCurrentUser(ctx)
+	if operator == nil {
+		return nil, gerror.New("用户未登录")
+	}
+	if !operator.IsSystemAdmin() || operator.IsApiKey() || operator.IsImpersonated() {
+		service.Middleware().LogSecurity(ctx, "IMPERSONATE_DENIED", "high", "非系统管理员尝试模拟登录", operator.Id, in.UserId)
+		return nil, gerror.New("只有系统管理员可以模拟登录")
+	}
+	if in.UserId == operator.Id {
+		return nil, gerror.New("不能模拟登录自己")
+	}
+
+	var user *entity.User
+	if err = g.DB().Model("sys_users").Where("id = ? AND deleted_at IS NULL", in.UserId).Scan(&user); err != nil {
+		return nil, gerror.Newf("查询用户失败: %v", err)
+	}
+	if user == nil {
+		return nil, gerror.New("用户不存在")
+	}
+	if user.Status == entity.UserStatusDisabled {
+		return nil, gerror.New("用户已被禁用")
+	}
+
+	tenantId, err := g.DB().Model("sys_users").Where("id = ?", user.Id).Value("tenant_id")
+	if err != nil {
+		return nil, gerror.Newf("查询用户租户失败: %v", err)
+	}
+	var tenant *entity.Tenant
+	if err = g.DB().Model("sys_tenants").Where("id = ? AND deleted_at IS NULL", tenantId.Uint64()).Scan(&tenant); err != nil {
+		return nil, gerror.Wrap(err, "查询租户失败")
+	}
+	if tenant == nil {
+		return nil, gerror.New("用户所属租户不存在")
+	}
+	// 系统租户的用户拥有平台管理权限，不允许通过模拟登录互相借用身份
+	if tenant.IsSystemTenant() {
+		return nil, gerror.New("不能模拟登录系统租户的用户")
+	}
+
+	userRole, err := s.getUserPrimaryRoleWithTenant(ctx, user.Id, tenant.Id)
+	if err != nil {
+		return nil, gerror.Newf("获取用户角色失败: %v", err)
+	}
+
+	expire := g.Cfg().MustGet(ctx, "impersonate.expire", "30m").Duration()
+	expiresAt := time.Now().Add(expire)
+	payload := &simple.JWTPayload{
+		UserId:      user.Id,
+		TenantId:    int64(tenant.Id),
+		TenantCode:  tenant.Code,
+		Username:    user.Username,
+		RoleId:      userRole.RoleId,
+		RoleKey:     userRole.RoleCode,
+		DeptId:      user.DeptId,
+		App:         consts.AppApi,
+		PermVersion: user.PermVersion,
+		Act: &simple.JWTActor{
+			UserId:   operator.Id,
+			TenantId: operator.TenantId,
+			Username: operator.Username,
+		},
+		Exp: expiresAt.Unix(),
+	}
+	accessToken, err := simple.GenerateJWTToken(ctx, payload)
+	if err != nil {
+		return nil, gerror.Newf("生成访问令牌失败: %v", err)
+	}
+
+	var clientIp string
+	if r := g.RequestFromCtx(ctx); r != nil {
+		clientIp = r.GetClientIp()
+	}
+	_, err = g.DB().Model("sys_impersonations").Data(g.Map{
+		"jti":                    payload.Jti,
+		"impersonator_id":        operator.Id,
+		"impersonator_tenant_id": operator.TenantId,
+		"user_id":                user.Id,
+		"tenant_id":              tenant.Id,
+		"reason":                 in.Reason,
+		"ip":                     clientIp,
+		"started_at":             gtime.Now(),
+		"expires_at":             gtime.New(expiresAt),
+	}).Insert()
+	if err != nil {
+		return nil, gerror.Wrap(err, "记录模拟登录失败")
+	}
+
+	service.Middleware().LogAudit(ctx, "IMPERSONATE_START", "USER", "SUCCESS", "模拟登录", operator.Id, user.Id, tenant.Code, in.Reason)
+	service.Middleware().LogSecurity(ctx, "IMPERSONATE_START", "medium", "系统管理员模拟登录", operator.Id, user.Id, payload.Jti)
+
+	permissions, menuIds, err := s.getUserPermissions(ctx, user.Id)
+	if err != nil {
+		g.Log().Warningf(ctx, "获取用户权限失败: %v", err)
+		permissions = []string{}
+		menuIds = []int64{}
+	}
+
+	res = &sysout.ImpersonateModel{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(expire.Seconds()),
+		ExpiresAt:   gtime.New(expiresAt),
+		UserInfo:    sysout.ConvertToUserModel(user),
+		Permissions: permissions,
+		MenuIds:     menuIds,
+	}
+	return res, nil
+}
+
+// StopImpersonate 结束模拟登录，撤销当前模拟登录令牌
+func (s *sUser) StopImpersonate(ctx context.Context) error {
+	identity := service.Middleware().GetCurrentUser(ctx)
+	if identity == nil {
+		return gerror.New("用户未登录")
+	}
+	if !identity.IsImpersonated() {
+		return gerror.New("当前不是模拟登录")
+	}
+
+	r := g.RequestFromCtx(ctx)
+	if r == nil {
+		return gerror.New("无法获取当前令牌")
+	}
+	token, err := simple.ExtractTokenFromHeader(r.Header.Get("Authorization"))
+	if err != nil {
+		return err
+	}
+	payload, err := simple.ParseJWTToken(ctx, token)
+	if err != nil {
+		return gerror.Wrap(err, "解析访问令牌失败")
+	}
+
+	if err = service.TokenRevocation().RevokeToken(ctx, payload.Jti, payload.Exp); err != nil {
+		return gerror.Wrap(err, "撤销访问令牌失败")
+	}
+	return s.endImpersonation(ctx, payload, entity.ImpersonationEndStop)
+}
+
+// endImpersonation 记录模拟登录结束并写入审计日志
+func (s *sUser) endImpersonation(ctx context.Context, payload *simple.JWTPayload, reason string) error {
+	_, err := g.DB().Model("sys_impersonations").
+		Where("jti = ? AND ended_at IS NULL", payload.Jti).
+		Data(g.Map{"ended_at": gtime.Now(), "end_reason": reason}).
+		Update()
+	if err != nil {
+		return gerror.Wrap(err, "更新模拟登录记录失败")
+	}
+
+	service.Middleware().LogAudit(ctx, "IMPERSONATE_STOP", "USER", "SUCCESS", "结束模拟登录", payload.Act.UserId, payload.UserId, reason)
+	return nil
+}
@@ -0,0 +1,521 @@
+package api
+
+import (
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
+	"client-app/utility/charset"
+	"client-app/utility/sheet"
+	"client-app/utility/simple"
+	"client-app/utility/validate"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// 导入导出的表格列
+const (
+	userColumnUsername = iota
+	userColumnEmail
+	userColumnPhone
+	userColumnDept
+	userColumnRoles
+)
+
+// userSheetHeader 导出表头，导入时同样识别
+var userSheetHeader = []string{"username", "email", "phone", "department", "roles"}
+
+// userSheetHeaderAlias 导入时识别的中文表头
+var userSheetHeaderAlias = map[string]int{
+	"用户名": userColumnUsername,
+	"邮箱":  userColumnEmail,
+	"手机号": userColumnPhone,
+	"部门":  userColumnDept,
+	"角色":  userColumnRoles,
+}
+
+// userImportBatchSize 批量查询唯一性时每次的IN条件数量
+const userImportBatchSize = 500
+
+// userImportRow 待导入的一行用户数据
+type userImportRow struct {
+	Row      int
+	Username string
+	Email    string
+	Phone    string
+	DeptId   int64
+	RoleIds  []int64
+	Errors   []string
+}
+
+func (r *userImportRow) fail(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+// ImportUsers 从CSV或XLSX批量导入当前租户的用户
+// 逐行校验后返回错误报告，commit模式下全部通过才会在同一事务中创建用户并分配角色
+func (s *sUser) ImportUsers(ctx context.Context, in *sysin.ImportUserInp) (res *sysout.UserImportModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	operator, err := s.getUserManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantId := uint64(operator.TenantId)
+
+	records, err := readUserImportFile(in)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.parseUserImportRows(ctx, tenantId, records)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := getPasswordPolicy(ctx, tenantId)
+	password := in.Password
+	if password != "" {
+		if password, err = service.Crypto().Decrypt(ctx, password); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if err = policy.Validate(password, row.Username); err != nil {
+				row.fail("初始密码不符合密码策略: %v", err)
+			}
+		}
+	}
+
+	if err = s.checkUserImportUnique(ctx, tenantId, rows); err != nil {
+		return nil, err
+	}
+
+	res = &sysout.UserImportModel{
+		Mode:   in.Mode,
+		Total:  len(rows),
+		Errors: []*sysout.UserImportErrorModel{},
+	}
+	for _, row := range rows {
+		if len(row.Errors) == 0 {
+			res.Valid++
+			continue
+		}
+		res.Invalid++
+		res.Errors = append(res.Errors, &sysout.UserImportErrorModel{
+			Row:      row.Row,
+			Username: row.Username,
+			Messages: row.Errors,
+		})
+	}
+
+	if res.Total == 0 {
+		return nil, gerror.New("导入文件中没有用户数据")
+	}
+	if err = s.checkUserImportCapacity(ctx, tenantId, res.Valid, false); err != nil {
+		return nil, err
+	}
+	if in.Mode != sysin.UserImportModeCommit || res.Invalid > 0 {
+		return res, nil
+	}
+
+	// 未指定初始密码时使用无人知晓的随机密码，导入用户需通过找回密码设置
+	if password == "" {
+		password = string(charset.RandomCreateBytes(32))
+	}
+	passwordHash, err := simple.HashPassword(ctx, password)
+	if err != nil {
+		return nil, gerror.Newf("生成密码hash失败: %v", err)
+	}
+
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		// 锁定租户记录，避免并发创建超出用户数上限
+		if err := s.checkUserImportCapacity(ctx, tenantId, len(rows), true); err != nil {
+			return err
+		}
+
+		now := gtime.Now()
+		for _, row := range rows {
+			userId, err := tx.Model("sys_users").Data(g.Map{
+				"tenant_id":           tenantId,
+				"username":            row.Username,
+				"password":            passwordHash,
+				"salt":                "",
+				"password_changed_at": now,
+				"email":               nullIfEmpty(row.Email),
+				"phone":               nullIfEmpty(row.Phone),
+				"nickname":            row.Username,
+				"dept_id":             row.DeptId,
+				"status":              in.Status,
+				"created_by":          operator.Id,
+				"updated_by":          operator.Id,
+				"created_at":          now,
+				"updated_at":          now,
+			}).InsertAndGetId()
+			if err != nil {
+				return gerror.Newf("第%d行创建用户失败: %v", row.Row, err)
+			}
+			if err = savePasswordHistory(ctx, tx, userId, passwordHash, policy); err != nil {
+				return err
+			}
+			if err = service.Role().AssignUserRoles(ctx, userId, row.RoleIds, operator.Id); err != nil {
+				return gerror.Wrapf(err, "第%d行分配角色失败", row.Row)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res.Created = len(rows)
+	service.Middleware().LogAudit(ctx, "IMPORT", "user", "success", res.Created)
+	return res, nil
+}
+
+// ExportUsers 按列表筛选条件导出当前租户的用户，列与导入模板一致
+func (s *sUser) ExportUsers(ctx context.Context, in *sysin.ExportUserInp) (res *sysout.UserExportModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	operator, err := s.getUserManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := s.userListQuery(ctx, operator, &in.UserListInp)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []*entity.User
+	err = db.Fields("id", "username", "email", "phone", "dept_id").
+		Order(fmt.Sprintf("%s %s", in.OrderBy, in.OrderType)).
+		Limit(sheet.MaxRows - 1).
+		Scan(&users)
+	if err != nil {
+		return nil, gerror.Newf("查询用户列表失败: %v", err)
+	}
+
+	userIds := make([]int64, 0, len(users))
+	for _, user := range users {
+		userIds = append(userIds, user.Id)
+	}
+	roleCodes := make(map[int64][]string, len(users))
+	if len(userIds) > 0 {
+		var userRoles []struct {
+			UserId int64
+			Code   string
+		}
+		err = g.DB().Model("sys_user_roles ur").
+			InnerJoin("sys_roles r", "r.id = ur.role_id").
+			Fields("ur.user_id, r.code").
+			Where("ur.user_id IN(?) AND r.deleted_at IS NULL", userIds).
+			Order("ur.is_primary DESC, r.sort ASC").
+			Scan(&userRoles)
+		if err != nil {
+			return nil, gerror.Newf("查询用户角色失败: %v", err)
+		}
+		for _, ur := range userRoles {
+			roleCodes[ur.UserId] = append(roleCodes[ur.UserId], ur.Code)
+		}
+	}
+
+	depts, err := s.getTenantDeptCodes(ctx, uint64(operator.TenantId), false)
+	if err != nil {
+		return nil, err
+	}
+	deptCodes := make(map[int64]string, len(depts))
+	for code, id := range depts {
+		deptCodes[id] = code
+	}
+
+	records := [][]string{userSheetHeader}
+	for _, user := range users {
+		dept := deptCodes[user.DeptId]
+		if dept == "" && user.DeptId > 0 {
+			dept = strconv.FormatInt(user.DeptId, 10)
+		}
+		records = append(records, []string{user.Username, user.Email, user.Phone, dept, strings.Join(roleCodes[user.Id], ",")})
+	}
+
+	content, err := sheet.Write(in.Format, records)
+	if err != nil {
+		return nil, gerror.Newf("生成导出文件失败: %v", err)
+	}
+
+	service.Middleware().LogAudit(ctx, "EXPORT", "user", "success", len(users))
+	return &sysout.UserExportModel{
+		Filename:    fmt.Sprintf("users_%s.%s", gtime.Now().Format("YmdHis"), in.Format),
+		ContentType: sheet.ContentType(in.Format),
+		Content:     content,
+	}, nil
+}
+
+// readUserImportFile 读取上传的导入文件
+func readUserImportFile(in *sysin.ImportUserInp) ([][]string, error) {
+	file, err := in.File.Open()
+	if err != nil {
+		return nil, gerror.Wrap(err, "读取导入文件失败")
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, sysin.UserImportMaxFileSize+1))
+	if err != nil {
+		return nil, gerror.Wrap(err, "读取导入文件失败")
+	}
+	if len(data) > sysin.UserImportMaxFileSize {
+		return nil, gerror.Newf("导入文件不能超过%dMB", sysin.UserImportMaxFileSize>>20)
+	}
+	return sheet.Read(sheet.FormatOf(in.File.Filename), data)
+}
+
+// parseUserImportRows 按表头解析数据行，完成格式校验、角色编码解析和文件内重复检查
+func (s *sUser) parseUserImportRows(ctx context.Context, tenantId uint64, records [][]string) ([]*userImportRow, error) {
+	headerIndex := -1
+	for i, record := range records {
+		if !sheet.IsEmptyRow(record) {
+			headerIndex = i
+			break
+		}
+	}
+	if headerIndex < 0 {
+		return nil, gerror.New("导入文件中没有用户数据")
+	}
+
+	columns := make(map[int]int)
+	for i, title := range records[headerIndex] {
+		title = strings.ToLower(strings.TrimSpace(title))
+		for column, name := range userSheetHeader {
+			if title == name {
+				columns[column] = i
+			}
+		}
+		if column, ok := userSheetHeaderAlias[title]; ok {
+			columns[column] = i
+		}
+	}
+	for _, column := range []int{userColumnUsername, userColumnRoles} {
+		if _, ok := columns[column]; !ok {
+			return nil, gerror.Newf("导入文件缺少%s列", userSheetHeader[column])
+		}
+	}
+
+	roles, err := s.getTenantRoleCodes(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	depts, err := s.getTenantDeptCodes(ctx, tenantId, true)
+	if err != nil {
+		return nil, err
+	}
+	deptIds := make(map[int64]bool, len(depts))
+	for _, id := range depts {
+		deptIds[id] = true
+	}
+
+	var (
+		rows      []*userImportRow
+		usernames = make(map[string]int)
+		emails    = make(map[string]int)
+		phones    = make(map[string]int)
+	)
+	for i := headerIndex + 1; i < len(records); i++ {
+		record := records[i]
+		if sheet.IsEmptyRow(record) {
+			continue
+		}
+		cell := func(column int) string {
+			if index, ok := columns[column]; ok && index < len(record) {
+				return record[index]
+			}
+			return ""
+		}
+
+		row := &userImportRow{
+			Row:      i + 1,
+			Username: cell(userColumnUsername),
+			Email:    cell(userColumnEmail),
+			Phone:    cell(userColumnPhone),
+		}
+		rows = append(rows, row)
+
+		if err := sysin.ValidateUsername(ctx, row.Username); err != nil {
+			row.fail("%s", err.Error())
+		} else if first, ok := usernames[strings.ToLower(row.Username)]; ok {
+			row.fail("用户名与第%d行重复", first)
+		} else {
+			usernames[strings.ToLower(row.Username)] = row.Row
+		}
+
+		if row.Email != "" {
+			if !validate.IsEmail(row.Email) || len(row.Email) > 100 {
+				row.fail("邮箱格式不正确")
+			} else if first, ok := emails[strings.ToLower(row.Email)]; ok {
+				row.fail("邮箱与第%d行重复", first)
+			} else {
+				emails[strings.ToLower(row.Email)] = row.Row
+			}
+		}
+
+		if row.Phone != "" {
+			if !validate.IsMobile(row.Phone) {
+				row.fail("手机号格式不正确")
+			} else if first, ok := phones[row.Phone]; ok {
+				row.fail("手机号与第%d行重复", first)
+			} else {
+				phones[row.Phone] = row.Row
+			}
+		}
+
+		// 部门按编码匹配，兼容直接填写部门ID
+		if dept := cell(userColumnDept); dept != "" {
+			if deptId, ok := depts[dept]; ok {
+				row.DeptId = deptId
+			} else if deptId, err := strconv.ParseInt(dept, 10, 64); err == nil && deptIds[deptId] {
+				row.DeptId = deptId
+			} else {
+				row.fail("部门[%s]不存在或已禁用", dept)
+			}
+		}
+
+		codes := strings.FieldsFunc(cell(userColumnRoles), func(r rune) bool {
+			return r == ',' || r == '，' || r == ';' || r == '；' || r == '|' || r == ' '
+		})
+		if len(codes) == 0 {
+			row.fail("角色不能为空")
+		}
+		seen := make(map[int64]struct{}, len(codes))
+		for _, code := range codes {
+			roleId, ok := roles[code]
+			if !ok {
+				row.fail("角色[%s]不存在或已停用", code)
+				continue
+			}
+			if _, ok = seen[roleId]; !ok {
+				seen[roleId] = struct{}{}
+				row.RoleIds = append(row.RoleIds, roleId)
+			}
+		}
+	}
+	return rows, nil
+}
+
+// getTenantRoleCodes 获取租户内已启用角色的编码与ID映射
+func (s *sUser) getTenantRoleCodes(ctx context.Context, tenantId uint64) (map[string]int64, error) {
+	var roles []*entity.Role
+	err := g.DB().Model("sys_roles").
+		Fields("id", "code").
+		Where("tenant_id = ? AND status = ? AND deleted_at IS NULL", tenantId, entity.RoleStatusEnabled).
+		Scan(&roles)
+	if err != nil {
+		return nil, gerror.Newf("查询角色失败: %v", err)
+	}
+
+	codes := make(map[string]int64, len(roles))
+	for _, role := range roles {
+		codes[role.Code] = role.Id
+	}
+	return codes, nil
+}
+
+// getTenantDeptCodes 获取租户内部门的编码与ID映射，enabledOnly 为 true 时只返回已启用的部门
+func (s *sUser) getTenantDeptCodes(ctx context.Context, tenantId uint64, enabledOnly bool) (map[string]int64, error) {
+	db := g.DB().Model("sys_depts").Fields("id", "code").Where("tenant_id = ?", tenantId)
+	if enabledOnly {
+		db = db.Where("status = ?", entity.DeptStatusEnabled)
+	}
+
+	var depts []*entity.Dept
+	if err := db.Scan(&depts); err != nil {
+		return nil, gerror.Newf("查询部门失败: %v", err)
+	}
+
+	codes := make(map[string]int64, len(depts))
+	for _, dept := range depts {
+		codes[dept.Code] = dept.Id
+	}
+	return codes, nil
+}
+
+// checkUserImportUnique 批量检查用户名、邮箱、手机号是否已被占用，规则与 checkUserUnique 一致
+func (s *sUser) checkUserImportUnique(ctx context.Context, tenantId uint64, rows []*userImportRow) error {
+	checks := []struct {
+		column  string
+		value   func(row *userImportRow) string
+		message string
+	}{
+		{"username", func(row *userImportRow) string { return row.Username }, "用户名已存在"},
+		{"email", func(row *userImportRow) string { return row.Email }, "邮箱已被使用"},
+		{"phone", func(row *userImportRow) string { return row.Phone }, "手机号已被使用"},
+	}
+
+	for _, check := range checks {
+		var values []string
+		for _, row := range rows {
+			if value := check.value(row); value != "" {
+				values = append(values, value)
+			}
+		}
+
+		taken := make(map[string]struct{})
+		for start := 0; start < len(values); start += userImportBatchSize {
+			end := start + userImportBatchSize
+			if end > len(values) {
+				end = len(values)
+			}
+
+			db := g.DB().Model("sys_users").WhereIn(check.column, values[start:end])
+			if check.column == "username" {
+				db = db.Where("tenant_id = ?", tenantId)
+			}
+			array, err := db.Array(check.column)
+			if err != nil {
+				return gerror.Newf("查询用户失败: %v", err)
+			}
+			for _, v := range array {
+				taken[strings.ToLower(v.String())] = struct{}{}
+			}
+		}
+
+		for _, row := range rows {
+			if _, ok := taken[strings.ToLower(check.value(row))]; ok {
+				row.fail("%s", check.message)
+			}
+		}
+	}
+	return nil
+}
+
+// checkUserImportCapacity 检查租户剩余可创建的用户数，lock 为 true 时需在事务中调用
+func (s *sUser) checkUserImportCapacity(ctx context.Context, tenantId uint64, count int, lock bool) error {
+	model := g.DB().Model("sys_tenants").Ctx(ctx).Where("id", tenantId)
+	if lock {
+		model = model.LockUpdate()
+	}
+	maxUsers, err := model.Value("max_users")
+	if err != nil {
+		return gerror.Wrap(err, "查询租户失败")
+	}
+	if maxUsers.Int() <= 0 {
+		return nil
+	}
+
+	userCount, err := g.DB().Model("sys_users").Ctx(ctx).Where("tenant_id = ? AND deleted_at IS NULL", tenantId).Count()
+	if err != nil {
+		return gerror.Wrap(err, "统计用户数量失败")
+	}
+	if remain := maxUsers.Int() - userCount; count > remain {
+		return gerror.Newf("租户用户数已达上限，剩余可创建%d个，本次导入%d个", max(remain, 0), count)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"client-app/utility/simple"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// loginCounterStore 登录失败计数存储
+type loginCounterStore interface {
+	// incr 原子加一并将过期时间顺延ttl，返回加一后的次数，已过期的计数从1重新开始
+	incr(ctx context.Context, key string, ttl time.Duration) (int, error)
+	// get 获取当前次数，不存在或已过期返回0
+	get(ctx context.Context, key string) (int, error)
+	// remove 清除计数
+	remove(ctx context.Context, key string) error
+}
+
+var (
+	loginCounterOnce sync.Once
+	loginCounter     loginCounterStore
+)
+
+// getLoginCounterStore 根据配置选择存储，未配置时集群部署使用db，单机使用memory
+func getLoginCounterStore(ctx context.Context) loginCounterStore {
+	loginCounterOnce.Do(func() {
+		driver := g.Cfg().MustGet(ctx, "login.guard.store").String()
+		if driver == "" {
+			driver = "memory"
+			if simple.IsCluster(ctx) {
+				driver = "db"
+			}
+		}
+		switch driver {
+		case "db":
+			loginCounter = newLoginCounterDBStore()
+		default:
+			if simple.IsCluster(ctx) {
+				g.Log().Warning(ctx, "集群部署下登录失败计数使用memory存储，失败次数无法在节点间共享")
+			}
+			loginCounter = newLoginCounterMemoryStore()
+		}
+	})
+	return loginCounter
+}
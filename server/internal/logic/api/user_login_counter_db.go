@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// loginCounterDBStore 数据库存储，集群部署时各节点共享失败次数
+type loginCounterDBStore struct{}
+
+func newLoginCounterDBStore() *loginCounterDBStore {
+	return &loginCounterDBStore{}
+}
+
+func (d *loginCounterDBStore) incr(ctx context.Context, key string, ttl time.Duration) (int, error) {
+	now := gtime.Now()
+
+	// 先删除已过期的计数，再由数据库原子累加，并发失败不会丢失次数
+	_, err := g.DB().Model("sys_login_counters").Where("counter_key = ? AND expires_at <= ?", key, now).Delete()
+	if err != nil {
+		return 0, gerror.Wrap(err, "清理登录失败计数失败")
+	}
+	_, err = g.DB().Model("sys_login_counters").Data(g.Map{
+		"counter_key": key,
+		"count":       1,
+		"expires_at":  now.Add(ttl),
+	}).OnConflict("counter_key").OnDuplicate(g.Map{
+		"count":      &gdb.Counter{Field: "count", Value: 1},
+		"expires_at": "expires_at",
+	}).Save()
+	if err != nil {
+		return 0, gerror.Wrap(err, "保存登录失败计数失败")
+	}
+
+	count, err := g.DB().Model("sys_login_counters").Where("counter_key", key).Value("count")
+	if err != nil {
+		return 0, gerror.Wrap(err, "查询登录失败计数失败")
+	}
+	return count.Int(), nil
+}
+
+func (d *loginCounterDBStore) get(ctx context.Context, key string) (int, error) {
+	count, err := g.DB().Model("sys_login_counters").
+		Where("counter_key = ? AND expires_at > ?", key, gtime.Now()).
+		Value("count")
+	if err != nil {
+		return 0, gerror.Wrap(err, "查询登录失败计数失败")
+	}
+	return count.Int(), nil
+}
+
+func (d *loginCounterDBStore) remove(ctx context.Context, key string) error {
+	if _, err := g.DB().Model("sys_login_counters").Where("counter_key", key).Delete(); err != nil {
+		return gerror.Wrap(err, "清除登录失败计数失败")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/os/gcache"
+)
+
+// loginCounterMemoryStore 进程内存储，仅适用于单机部署
+type loginCounterMemoryStore struct {
+	mu    sync.Mutex
+	cache *gcache.Cache
+}
+
+func newLoginCounterMemoryStore() *loginCounterMemoryStore {
+	return &loginCounterMemoryStore{cache: gcache.New()}
+}
+
+func (m *loginCounterMemoryStore) incr(ctx context.Context, key string, ttl time.Duration) (int, error) {
+	// 读取和写入需在同一把锁内完成，否则并发失败会相互覆盖计数
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	val, err := m.cache.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	count := val.Int() + 1
+	if err = m.cache.Set(ctx, key, count, ttl); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (m *loginCounterMemoryStore) get(ctx context.Context, key string) (int, error) {
+	val, err := m.cache.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	return val.Int(), nil
+}
+
+func (m *loginCounterMemoryStore) remove(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := m.cache.Remove(ctx, key)
+	return err
+}
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"client-app/internal/consts"
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/service"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// loginGuardConfig 登录保护配置
+type loginGuardConfig struct {
+	Window       time.Duration // 计数窗口
+	CaptchaAfter int           // 失败达到该次数后需要验证码，0表示始终需要
+	LockAfter    int           // 同一账号失败达到该次数后临时锁定，0表示不锁定
+	LockDuration time.Duration // 临时锁定时长
+	IpLimit      int           // 同一IP失败达到该次数后拒绝登录，0表示不限制
+}
+
+// loginAttempt 一次登录尝试
+type loginAttempt struct {
+	conf       *loginGuardConfig
+	tenantCode string
+	username   string
+	clientIp   string
+}
+
+// newLoginAttempt 读取登录保护配置
+func newLoginAttempt(ctx context.Context, in *sysin.UserLoginInp) *loginAttempt {
+	attempt := &loginAttempt{
+		conf: &loginGuardConfig{
+			Window:       g.Cfg().MustGet(ctx, "login.guard.window", "15m").Duration(),
+			CaptchaAfter: g.Cfg().MustGet(ctx, "login.guard.captchaAfter", 3).Int(),
+			LockAfter:    g.Cfg().MustGet(ctx, "login.guard.lockAfter", 5).Int(),
+			LockDuration: g.Cfg().MustGet(ctx, "login.guard.lockDuration", "15m").Duration(),
+			IpLimit:      g.Cfg().MustGet(ctx, "login.guard.ipLimit", 50).Int(),
+		},
+		tenantCode: in.TenantCode,
+		username:   in.Username,
+	}
+	if r := g.RequestFromCtx(ctx); r != nil {
+		attempt.clientIp = r.GetClientIp()
+	}
+	return attempt
+}
+
+func (a *loginAttempt) userKey() string {
+	return fmt.Sprintf("login_fail_user_%s_%s", a.tenantCode, strings.ToLower(a.username))
+}
+
+func (a *loginAttempt) ipKey() string {
+	return fmt.Sprintf("login_fail_ip_%s", a.clientIp)
+}
+
+// lockKey 临时锁定标记，账号不存在时同样设置，避免通过锁定提示探测账号
+func (a *loginAttempt) lockKey() string {
+	return fmt.Sprintf("login_lock_%s_%s", a.tenantCode, strings.ToLower(a.username))
+}
+
+// failures 获取账号和IP的失败次数
+func (a *loginAttempt) failures(ctx context.Context) (userFails, ipFails int, err error) {
+	store := getLoginCounterStore(ctx)
+	if userFails, err = store.get(ctx, a.userKey()); err != nil {
+		return
+	}
+	ipFails, err = store.get(ctx, a.ipKey())
+	return
+}
+
+// locked 账号是否处于临时锁定中
+func (a *loginAttempt) locked(ctx context.Context) (bool, error) {
+	n, err := getLoginCounterStore(ctx).get(ctx, a.lockKey())
+	return n > 0, err
+}
+
+// captchaRequired 是否需要验证码
+func (a *loginAttempt) captchaRequired(userFails, ipFails int) bool {
+	n := a.conf.CaptchaAfter
+	return n <= 0 || userFails >= n || ipFails >= n
+}
+
+// checkLoginAttempt 登录前检查IP限制、临时锁定和验证码
+func (s *sUser) checkLoginAttempt(ctx context.Context, attempt *loginAttempt, in *sysin.UserLoginInp) error {
+	userFails, ipFails, err := attempt.failures(ctx)
+	if err != nil {
+		return err
+	}
+
+	if attempt.conf.IpLimit > 0 && ipFails >= attempt.conf.IpLimit {
+		service.Middleware().LogSecurity(ctx, "LOGIN_IP_BLOCKED", "medium", "IP登录失败次数过多", attempt.clientIp, ipFails)
+		return gerror.NewCode(gcode.New(consts.CodeLoginLocked, "", nil), "登录失败次数过多，请稍后再试")
+	}
+
+	locked, err := attempt.locked(ctx)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return s.lockedError(attempt)
+	}
+
+	if !attempt.captchaRequired(userFails, ipFails) {
+		return nil
+	}
+	if in.Captcha == "" || in.CaptchaId == "" {
+		return gerror.NewCode(gcode.New(consts.CodeCaptchaRequired, "", nil), "请输入验证码")
+	}
+	if err = s.VerifyCaptcha(ctx, in.CaptchaId, in.Captcha); err != nil {
+		return gerror.NewCode(gcode.New(consts.CodeCaptchaRequired, "", nil), err.Error())
+	}
+	return nil
+}
+
+// checkAccountLock 检查账号临时锁定，锁定到期的账号自动解锁
+// 管理员锁定的账号没有解锁时间，交由后续的用户状态检查处理
+func (s *sUser) checkAccountLock(ctx context.Context, attempt *loginAttempt, tenantId uint64) error {
+	var user *entity.User
+	err := g.DB().Model("sys_users").
+		Fields("id, status, locked_until").
+		Where("username = ? AND tenant_id = ? AND deleted_at IS NULL", attempt.username, tenantId).
+		Scan(&user)
+	if err != nil {
+		return gerror.Newf("查询用户失败: %v", err)
+	}
+	if user == nil || !user.IsLocked() || user.LockedUntil == nil {
+		return nil
+	}
+
+	if user.IsLockExpired() {
+		s.unlockUser(ctx, user.Id)
+		return nil
+	}
+	return s.lockedError(attempt)
+}
+
+// loginFailed 记录登录失败，达到阈值时锁定账号或要求验证码
+func (s *sUser) loginFailed(ctx context.Context, attempt *loginAttempt, tenantId uint64, cause error) error {
+	// 计数由存储原子累加，并发的失败请求不会绕过阈值
+	store := getLoginCounterStore(ctx)
+	userFails, err := store.incr(ctx, attempt.userKey(), attempt.conf.Window)
+	if err != nil {
+		g.Log().Warningf(ctx, "记录登录失败次数失败: %v", err)
+	}
+	ipFails, err := store.incr(ctx, attempt.ipKey(), attempt.conf.Window)
+	if err != nil {
+		g.Log().Warningf(ctx, "记录登录失败次数失败: %v", err)
+	}
+
+	service.Middleware().LogSecurity(ctx, "LOGIN_FAILED", "low", "登录失败", attempt.tenantCode, attempt.username, attempt.clientIp, userFails)
+
+	if attempt.conf.LockAfter > 0 && userFails >= attempt.conf.LockAfter {
+		if _, err = store.incr(ctx, attempt.lockKey(), attempt.conf.LockDuration); err != nil {
+			g.Log().Warningf(ctx, "记录账号锁定失败: %v", err)
+		}
+		_ = store.remove(ctx, attempt.userKey())
+		if tenantId > 0 {
+			s.lockUser(ctx, attempt, tenantId)
+		}
+		return s.lockedError(attempt)
+	}
+
+	if attempt.captchaRequired(userFails, ipFails) {
+		return gerror.NewCode(gcode.New(consts.CodeCaptchaRequired, "", nil), gerror.Current(cause).Error())
+	}
+	return cause
+}
+
+// loginSucceeded 登录成功后清除账号失败计数
+func (s *sUser) loginSucceeded(ctx context.Context, attempt *loginAttempt) {
+	_ = getLoginCounterStore(ctx).remove(ctx, attempt.userKey())
+}
+
+// lockUser 临时锁定账号
+func (s *sUser) lockUser(ctx context.Context, attempt *loginAttempt, tenantId uint64) {
+	userId, err := g.DB().Model("sys_users").
+		Where("username = ? AND tenant_id = ? AND deleted_at IS NULL", attempt.username, tenantId).
+		Value("id")
+	if err != nil {
+		g.Log().Warningf(ctx, "查询待锁定用户失败: %v", err)
+		return
+	}
+	if userId.IsNil() {
+		return
+	}
+
+	result, err := g.DB().Model("sys_users").
+		Where("id = ? AND status = ?", userId.Int64(), entity.UserStatusNormal).
+		Update(g.Map{
+			"status":       entity.UserStatusLocked,
+			"locked_until": gtime.Now().Add(attempt.conf.LockDuration),
+		})
+	if err != nil {
+		g.Log().Warningf(ctx, "锁定用户[%d]失败: %v", userId.Int64(), err)
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected > 0 {
+		s.invalidateUserPerms(ctx, userId.Int64())
+		service.Middleware().LogSecurity(ctx, "ACCOUNT_LOCKED", "medium", "登录失败次数过多，账号已临时锁定", userId.Int64(), attempt.clientIp, attempt.conf.LockDuration.String())
+	}
+}
+
+// unlockUser 解除到期的临时锁定
+func (s *sUser) unlockUser(ctx context.Context, userId int64) {
+	result, err := g.DB().Model("sys_users").
+		Where("id = ? AND status = ? AND locked_until <= ?", userId, entity.UserStatusLocked, gtime.Now()).
+		Update(g.Map{
+			"status":       entity.UserStatusNormal,
+			"locked_until": nil,
+		})
+	if err != nil {
+		g.Log().Warningf(ctx, "解锁用户[%d]失败: %v", userId, err)
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected > 0 {
+		s.invalidateUserPerms(ctx, userId)
+		service.Middleware().LogSecurity(ctx, "ACCOUNT_UNLOCKED", "low", "临时锁定到期，账号已自动解锁", userId)
+	}
+}
+
+// lockedError 账号临时锁定提示
+func (s *sUser) lockedError(attempt *loginAttempt) error {
+	return gerror.NewCode(gcode.New(consts.CodeLoginLocked, "", nil),
+		fmt.Sprintf("登录失败次数过多，账号已被锁定，请%d分钟后再试", int(attempt.conf.LockDuration.Minutes())))
+}
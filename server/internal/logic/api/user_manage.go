@@ -0,0 +1,626 @@
+package api
+
+import (
+	"client-app/internal/library/datascope"
+	"client-app/internal/model"
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
+	"context"
+	"fmt"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// GetUserList 获取当前租户的用户列表
+func (s *sUser) GetUserList(ctx context.Context, in *sysin.UserListInp) (res *sysout.UserListModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	operator, err := s.getUserManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := s.userListQuery(ctx, operator, in)
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount, err := db.Count()
+	if err != nil {
+		return nil, gerror.Newf("查询用户总数失败: %v", err)
+	}
+
+	res = &sysout.UserListModel{
+		List:     []*sysout.UserModel{},
+		Total:    totalCount,
+		Page:     in.Page,
+		PageSize: in.PageSize,
+	}
+	if totalCount == 0 {
+		return res, nil
+	}
+
+	var users []*entity.User
+	err = db.Order(fmt.Sprintf("%s %s", in.OrderBy, in.OrderType)).
+		Page(in.Page, in.PageSize).
+		Scan(&users)
+	if err != nil {
+		return nil, gerror.Newf("查询用户列表失败: %v", err)
+	}
+
+	for _, user := range users {
+		res.List = append(res.List, sysout.ConvertToUserModel(user))
+	}
+	return res, nil
+}
+
+// GetUserDetail 获取当前租户的用户详情，包含角色和权限
+func (s *sUser) GetUserDetail(ctx context.Context, in *sysin.UserDetailInp) (res *sysout.UserDetailModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	operator, err := s.getUserManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.getManagedUser(ctx, operator, in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var userRoles []struct {
+		entity.Role
+		IsPrimary int `json:"isPrimary"`
+	}
+	err = g.DB().Model("sys_user_roles ur").
+		InnerJoin("sys_roles r", "r.id = ur.role_id").
+		Fields("r.*, ur.is_primary").
+		Where("ur.user_id = ? AND r.tenant_id = ? AND r.deleted_at IS NULL", user.Id, operator.TenantId).
+		Order("ur.is_primary DESC, r.sort ASC").
+		Scan(&userRoles)
+	if err != nil {
+		return nil, gerror.Newf("查询用户角色失败: %v", err)
+	}
+
+	var (
+		roles       = make([]*sysout.RoleModel, 0, len(userRoles))
+		primaryRole *sysout.RoleModel
+		dataScope   int
+	)
+	for i := range userRoles {
+		role := sysout.ConvertToRoleModel(&userRoles[i].Role)
+		roles = append(roles, role)
+		if userRoles[i].IsPrimary == entity.IsPrimaryRole && primaryRole == nil {
+			primaryRole = role
+			dataScope = userRoles[i].DataScope
+		}
+	}
+
+	permissions, menuIds, err := s.getUserPermissions(ctx, user.Id)
+	if err != nil {
+		return nil, gerror.Newf("查询用户权限失败: %v", err)
+	}
+
+	return sysout.ConvertToUserDetailModel(user, roles, primaryRole, permissions, menuIds, dataScope), nil
+}
+
+// CreateUser 在当前租户下创建用户，受租户最大用户数限制
+func (s *sUser) CreateUser(ctx context.Context, in *sysin.CreateUserInp) (res *sysout.UserModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+	if err = sysin.ValidateUsername(ctx, in.Username); err != nil {
+		return nil, err
+	}
+
+	operator, err := s.getUserManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantId := uint64(operator.TenantId)
+
+	if err = s.checkTenantRoles(ctx, tenantId, in.RoleIds); err != nil {
+		return nil, err
+	}
+	if err = s.checkUserDept(ctx, operator.TenantId, in.DeptId); err != nil {
+		return nil, err
+	}
+	if err = s.checkUserUnique(ctx, tenantId, 0, in.Username, in.Email, in.Phone); err != nil {
+		return nil, err
+	}
+
+	passwordHash, passwordPolicy, err := s.hashNewPassword(ctx, tenantId, 0, in.Username, in.Password, in.ConfirmPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	var userId int64
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		// 锁定租户记录，避免并发创建超出用户数上限
+		maxUsers, err := tx.Model("sys_tenants").Where("id", tenantId).LockUpdate().Value("max_users")
+		if err != nil {
+			return gerror.Wrap(err, "查询租户失败")
+		}
+		if maxUsers.Int() > 0 {
+			userCount, err := tx.Model("sys_users").Where("tenant_id = ? AND deleted_at IS NULL", tenantId).Count()
+			if err != nil {
+				return gerror.Wrap(err, "统计用户数量失败")
+			}
+			if userCount >= maxUsers.Int() {
+				return gerror.New("租户用户数已达上限，无法创建用户")
+			}
+		}
+
+		now := gtime.Now()
+		data := g.Map{
+			"tenant_id":           tenantId,
+			"username":            in.Username,
+			"password":            passwordHash,
+			"salt":                "",
+			"password_changed_at": now,
+			"email":               nullIfEmpty(in.Email),
+			"phone":               nullIfEmpty(in.Phone),
+			"real_name":           in.RealName,
+			"nickname":            in.Nickname,
+			"avatar":              in.Avatar,
+			"gender":              in.Gender,
+			"birthday":            in.Birthday,
+			"dept_id":             in.DeptId,
+			"position":            in.Position,
+			"status":              in.Status,
+			"remark":              in.Remark,
+			"created_by":          operator.Id,
+			"updated_by":          operator.Id,
+			"created_at":          now,
+			"updated_at":          now,
+		}
+		if data["nickname"] == "" {
+			data["nickname"] = in.Username
+		}
+
+		userId, err = tx.Model("sys_users").Data(data).InsertAndGetId()
+		if err != nil {
+			return gerror.Newf("创建用户失败: %v", err)
+		}
+		if err = savePasswordHistory(ctx, tx, userId, passwordHash, passwordPolicy); err != nil {
+			return err
+		}
+		return service.Role().AssignUserRoles(ctx, userId, in.RoleIds, operator.Id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	service.Middleware().LogAudit(ctx, "CREATE", "user", "success", userId, in.Username)
+
+	user, err := s.getManagedUser(ctx, operator, userId)
+	if err != nil {
+		return nil, err
+	}
+	return sysout.ConvertToUserModel(user), nil
+}
+
+// UpdateUser 更新当前租户的用户资料和角色
+// 双因子认证只能由管理员关闭，开启需用户本人绑定验证器
+func (s *sUser) UpdateUser(ctx context.Context, in *sysin.UpdateUserInp) (res *sysout.UserModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+	if err = sysin.ValidateUsername(ctx, in.Username); err != nil {
+		return nil, err
+	}
+
+	operator, err := s.getUserManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.getManagedUser(ctx, operator, in.Id)
+	if err != nil {
+		return nil, err
+	}
+	tenantId := uint64(operator.TenantId)
+
+	if in.Status != 0 && in.Status != user.Status && user.Id == operator.Id {
+		return nil, gerror.New("不能修改自己的状态")
+	}
+	if in.TwoFactorEnabled == entity.TwoFactorEnabled && !user.IsTwoFactorEnabled() {
+		return nil, gerror.New("双因子认证需由用户本人绑定验证器后开启")
+	}
+	if err = s.checkTenantRoles(ctx, tenantId, in.RoleIds); err != nil {
+		return nil, err
+	}
+	if in.DeptId != user.DeptId {
+		if err = s.checkUserDept(ctx, operator.TenantId, in.DeptId); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.checkUserUnique(ctx, tenantId, user.Id, in.Username, in.Email, in.Phone); err != nil {
+		return nil, err
+	}
+
+	data := g.Map{
+		"username":   in.Username,
+		"email":      nullIfEmpty(in.Email),
+		"phone":      nullIfEmpty(in.Phone),
+		"real_name":  in.RealName,
+		"nickname":   in.Nickname,
+		"avatar":     in.Avatar,
+		"gender":     in.Gender,
+		"birthday":   in.Birthday,
+		"dept_id":    in.DeptId,
+		"position":   in.Position,
+		"remark":     in.Remark,
+		"updated_by": operator.Id,
+		"updated_at": gtime.Now(),
+	}
+	if in.Status != 0 {
+		data["status"] = in.Status
+	}
+	if in.Email != user.Email {
+		data["email_verified_at"] = nil
+	}
+	if in.Phone != user.Phone {
+		data["phone_verified_at"] = nil
+	}
+
+	disableTwoFactor := user.IsTwoFactorEnabled() && in.TwoFactorEnabled == entity.TwoFactorDisabled
+	if disableTwoFactor {
+		data["two_factor_enabled"] = entity.TwoFactorDisabled
+		data["two_factor_secret"] = ""
+	}
+
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		if _, err := tx.Model("sys_users").Where("id = ?", user.Id).Update(data); err != nil {
+			return gerror.Newf("更新用户失败: %v", err)
+		}
+		if disableTwoFactor {
+			if _, err := tx.Model("sys_user_recovery_codes").Where("user_id = ?", user.Id).Delete(); err != nil {
+				return gerror.Newf("清除恢复码失败: %v", err)
+			}
+		}
+		return service.Role().ReplaceUserRoles(ctx, user.Id, in.RoleIds, operator.Id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateUserPerms(ctx, user.Id)
+
+	if in.Status != 0 && in.Status != entity.UserStatusNormal && in.Status != user.Status {
+		if err = s.revokeUserAccess(ctx, user.Id); err != nil {
+			return nil, err
+		}
+	}
+	if disableTwoFactor {
+		service.Middleware().LogSecurity(ctx, "2FA_DISABLED", "medium", "管理员关闭用户双因子认证", operator.Id, user.Id)
+	}
+	service.Middleware().LogAudit(ctx, "UPDATE", "user", "success", user.Id, in.Username)
+
+	if user, err = s.getManagedUser(ctx, operator, user.Id); err != nil {
+		return nil, err
+	}
+	return sysout.ConvertToUserModel(user), nil
+}
+
+// UpdateUserStatus 修改当前租户的用户状态，锁定或禁用后立即撤销其登录会话、访问令牌和API密钥
+func (s *sUser) UpdateUserStatus(ctx context.Context, in *sysin.UpdateUserStatusInp) error {
+	if err := in.Filter(ctx); err != nil {
+		return err
+	}
+
+	operator, err := s.getUserManager(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := s.getManagedUser(ctx, operator, in.Id)
+	if err != nil {
+		return err
+	}
+	if user.Id == operator.Id {
+		return gerror.New("不能修改自己的状态")
+	}
+	if user.Status == in.Status {
+		return nil
+	}
+
+	data := g.Map{
+		"status":     in.Status,
+		"updated_by": operator.Id,
+		"updated_at": gtime.Now(),
+	}
+	if in.Status == entity.UserStatusNormal {
+		// 解除锁定时清除登录失败记录
+		data["login_fail_count"] = 0
+		data["locked_until"] = nil
+	}
+	if _, err = g.DB().Model("sys_users").Where("id = ?", user.Id).Update(data); err != nil {
+		return gerror.Newf("修改用户状态失败: %v", err)
+	}
+	s.invalidateUserPerms(ctx, user.Id)
+
+	if in.Status != entity.UserStatusNormal {
+		if err = s.revokeUserAccess(ctx, user.Id); err != nil {
+			return err
+		}
+	}
+
+	service.Middleware().LogSecurity(ctx, "USER_STATUS_CHANGED", "medium", "管理员修改用户状态", operator.Id, user.Id, user.Status, in.Status)
+	service.Middleware().LogAudit(ctx, "UPDATE_STATUS", "user", "success", user.Id, in.Status)
+	return nil
+}
+
+// ResetUserPassword 管理员重置当前租户用户的密码，重置后用户需重新登录
+func (s *sUser) ResetUserPassword(ctx context.Context, in *sysin.ResetPasswordInp) error {
+	if err := in.Filter(ctx); err != nil {
+		return err
+	}
+
+	operator, err := s.getUserManager(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := s.getManagedUser(ctx, operator, in.Id)
+	if err != nil {
+		return err
+	}
+
+	hash, policy, err := s.hashNewPassword(ctx, uint64(operator.TenantId), user.Id, user.Username, in.NewPassword, in.ConfirmPassword)
+	if err != nil {
+		return err
+	}
+	if err = s.updatePassword(ctx, user.Id, hash, policy); err != nil {
+		return err
+	}
+
+	if err = s.revokeUserRefreshTokens(ctx, user.Id, entity.RefreshTokenRevokePasswordReset); err != nil {
+		return err
+	}
+	if err = service.TokenRevocation().RevokeUser(ctx, user.Id); err != nil {
+		return err
+	}
+
+	service.Middleware().LogSecurity(ctx, "PASSWORD_RESET_BY_ADMIN", "medium", "管理员重置用户密码", operator.Id, user.Id)
+	return nil
+}
+
+// DeleteUser 软删除当前租户的用户，同时撤销其登录会话、访问令牌和API密钥
+func (s *sUser) DeleteUser(ctx context.Context, in *sysin.DeleteUserInp) error {
+	if err := in.Filter(ctx); err != nil {
+		return err
+	}
+
+	operator, err := s.getUserManager(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := s.getManagedUser(ctx, operator, in.Id)
+	if err != nil {
+		return err
+	}
+	if user.Id == operator.Id {
+		return gerror.New("不能删除自己")
+	}
+
+	_, err = g.DB().Model("sys_users").Where("id = ?", user.Id).Update(g.Map{
+		"deleted_at": gtime.Now(),
+		"updated_by": operator.Id,
+		"updated_at": gtime.Now(),
+	})
+	if err != nil {
+		return gerror.Newf("删除用户失败: %v", err)
+	}
+	s.invalidateUserPerms(ctx, user.Id)
+
+	if err = s.revokeUserAccess(ctx, user.Id); err != nil {
+		return err
+	}
+
+	service.Middleware().LogAudit(ctx, "DELETE", "user", "success", user.Id, user.Username)
+	return nil
+}
+
+// AssignUserRoles 重新分配当前租户用户的角色，第一个角色为主要角色
+func (s *sUser) AssignUserRoles(ctx context.Context, in *sysin.AssignUserRoleInp) error {
+	if err := in.Filter(ctx); err != nil {
+		return err
+	}
+
+	operator, err := s.getUserManager(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := s.getManagedUser(ctx, operator, in.UserId)
+	if err != nil {
+		return err
+	}
+	if err = s.checkTenantRoles(ctx, uint64(operator.TenantId), in.RoleIds); err != nil {
+		return err
+	}
+
+	if err = service.Role().ReplaceUserRoles(ctx, user.Id, in.RoleIds, operator.Id); err != nil {
+		return err
+	}
+
+	service.Middleware().LogAudit(ctx, "ASSIGN_ROLES", "user", "success", user.Id, in.RoleIds)
+	return nil
+}
+
+// userListQuery 构建当前租户用户列表的查询条件，按操作人的数据权限过滤，列表与导出共用
+func (s *sUser) userListQuery(ctx context.Context, operator *model.Identity, in *sysin.UserListInp) (*gdb.Model, error) {
+	db, err := s.scopedUsers(ctx, operator)
+	if err != nil {
+		return nil, err
+	}
+	if in.Username != "" {
+		db = db.WhereLike("username", "%"+in.Username+"%")
+	}
+	if in.RealName != "" {
+		db = db.WhereLike("real_name", "%"+in.RealName+"%")
+	}
+	if in.Email != "" {
+		db = db.WhereLike("email", "%"+in.Email+"%")
+	}
+	if in.Phone != "" {
+		db = db.WhereLike("phone", "%"+in.Phone+"%")
+	}
+	if in.Status > 0 {
+		db = db.Where("status = ?", in.Status)
+	}
+	if in.Gender >= 0 {
+		db = db.Where("gender = ?", in.Gender)
+	}
+	if in.DeptId > 0 {
+		db = db.Where("dept_id = ?", in.DeptId)
+	}
+	if in.RoleId > 0 {
+		db = db.Where("id IN(?)", g.DB().Model("sys_user_roles").Fields("user_id").Where("role_id = ?", in.RoleId))
+	}
+	if in.StartDate != "" {
+		db = db.WhereGTE("created_at", in.StartDate+" 00:00:00")
+	}
+	if in.EndDate != "" {
+		db = db.WhereLTE("created_at", in.EndDate+" 23:59:59")
+	}
+	return db, nil
+}
+
+// scopedUsers 操作人所在租户且在其数据权限范围内的用户，本人创建的用户始终可见
+func (s *sUser) scopedUsers(ctx context.Context, operator *model.Identity) (*gdb.Model, error) {
+	db := g.DB().Model("sys_users").Where("tenant_id = ? AND deleted_at IS NULL", operator.TenantId)
+	return datascope.Filter(ctx, db, operator, datascope.Options{DeptField: "dept_id"})
+}
+
+// getUserManager 获取用户管理操作人，操作范围限定在其所属租户
+func (s *sUser) getUserManager(ctx context.Context) (*model.Identity, error) {
+	operator := service.Middleware().GetCurrentUser(ctx)
+	if operator == nil {
+		return nil, gerror.New("用户未登录")
+	}
+	if operator.TenantId <= 0 {
+		return nil, gerror.New("租户信息无效")
+	}
+	return operator, nil
+}
+
+// getManagedUser 获取操作人可管理的用户，其他租户或超出数据权限范围的用户视为不存在
+func (s *sUser) getManagedUser(ctx context.Context, operator *model.Identity, userId int64) (*entity.User, error) {
+	db, err := s.scopedUsers(ctx, operator)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *entity.User
+	if err = db.Where("id = ?", userId).Scan(&user); err != nil {
+		return nil, gerror.Newf("查询用户失败: %v", err)
+	}
+	if user == nil {
+		return nil, gerror.New("用户不存在")
+	}
+	return user, nil
+}
+
+// checkTenantRoles 检查角色均属于当前租户且已启用
+func (s *sUser) checkTenantRoles(ctx context.Context, tenantId uint64, roleIds []int64) error {
+	if len(roleIds) == 0 {
+		return gerror.New("角色ID列表不能为空")
+	}
+
+	unique := make(map[int64]struct{}, len(roleIds))
+	for _, roleId := range roleIds {
+		unique[roleId] = struct{}{}
+	}
+	if len(unique) != len(roleIds) {
+		return gerror.New("角色ID列表不能重复")
+	}
+
+	count, err := g.DB().Model("sys_roles").
+		Where("id IN(?) AND tenant_id = ? AND status = ? AND deleted_at IS NULL", roleIds, tenantId, entity.RoleStatusEnabled).
+		Count()
+	if err != nil {
+		return gerror.Newf("查询角色失败: %v", err)
+	}
+	if count != len(roleIds) {
+		return gerror.New("角色不存在或已停用")
+	}
+	return nil
+}
+
+// checkUserDept 检查部门属于当前租户且已启用，0表示不设置部门
+func (s *sUser) checkUserDept(ctx context.Context, tenantId, deptId int64) error {
+	if deptId == 0 {
+		return nil
+	}
+	dept, err := service.Dept().GetDept(ctx, tenantId, deptId)
+	if err != nil {
+		return err
+	}
+	if dept == nil || !dept.IsEnabled() {
+		return gerror.New("部门不存在或已禁用")
+	}
+	return nil
+}
+
+// checkUserUnique 检查用户名在租户内唯一，邮箱、手机号全局唯一
+// 与数据库唯一索引保持一致，已软删除的用户同样占用
+func (s *sUser) checkUserUnique(ctx context.Context, tenantId uint64, excludeId int64, username, email, phone string) error {
+	checks := []struct {
+		where   string
+		args    []interface{}
+		message string
+	}{
+		{"tenant_id = ? AND username = ?", []interface{}{tenantId, username}, "用户名已存在"},
+		{"email = ?", []interface{}{email}, "邮箱已被使用"},
+		{"phone = ?", []interface{}{phone}, "手机号已被使用"},
+	}
+	for _, check := range checks {
+		if check.args[len(check.args)-1] == "" {
+			continue
+		}
+		db := g.DB().Model("sys_users").Where(check.where, check.args...)
+		if excludeId > 0 {
+			db = db.Where("id != ?", excludeId)
+		}
+		count, err := db.Count()
+		if err != nil {
+			return gerror.Newf("查询用户失败: %v", err)
+		}
+		if count > 0 {
+			return gerror.New(check.message)
+		}
+	}
+	return nil
+}
+
+// nullIfEmpty 空字符串写入NULL，避免可选的唯一字段相互冲突
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// revokeUserAccess 撤销用户的登录会话、刷新令牌、访问令牌和API密钥，用于禁用、锁定或删除用户
+func (s *sUser) revokeUserAccess(ctx context.Context, userId int64) error {
+	if err := s.revokeUserRefreshTokens(ctx, userId, entity.RefreshTokenRevokeUserDisabled); err != nil {
+		return err
+	}
+	if err := service.TokenRevocation().RevokeUser(ctx, userId); err != nil {
+		return err
+	}
+	return service.ApiKey().RevokeByUser(ctx, userId)
+}
+
+// invalidateUserPerms 用户状态、部门或密码变化后失效其权限快照
+// 变更已提交，失效失败只记录日志，快照到期后自动重新加载
+func (s *sUser) invalidateUserPerms(ctx context.Context, userIds ...int64) {
+	if err := service.PermCache().InvalidateUsers(ctx, userIds...); err != nil {
+		g.Log().Warningf(ctx, "失效用户%v的权限快照失败: %v", userIds, err)
+	}
+}
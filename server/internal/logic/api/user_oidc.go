@@ -0,0 +1,346 @@
+package api
+
+import (
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
+	"client-app/utility/oidc"
+	"client-app/utility/simple"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcache"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+// oidcState 授权请求状态，回调时取出并删除，只能使用一次
+type oidcState struct {
+	TenantId     uint64 `json:"tenantId"`
+	CodeVerifier string `json:"codeVerifier"`
+	Nonce        string `json:"nonce"`
+}
+
+// OidcAuthorize 发起单点登录，返回身份提供方授权地址
+func (s *sUser) OidcAuthorize(ctx context.Context, in *sysin.OidcAuthorizeInp) (res *sysout.OidcAuthorizeModel, err error) {
+	tenant, err := s.getTenantByCode(ctx, in.TenantCode)
+	if err != nil {
+		return nil, err
+	}
+
+	conf, provider, err := s.getOidcProvider(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := oidc.RandomString(24)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := oidc.RandomString(24)
+	if err != nil {
+		return nil, err
+	}
+	verifier, challenge, err := oidc.NewPKCE()
+	if err != nil {
+		return nil, err
+	}
+
+	expire := g.Cfg().MustGet(ctx, "oidc.stateExpire", "10m").Duration()
+	err = gcache.Set(ctx, oidcStateKey(state), &oidcState{
+		TenantId:     tenant.Id,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+	}, expire)
+	if err != nil {
+		return nil, gerror.Wrap(err, "保存授权状态失败")
+	}
+
+	g.Log().Debugf(ctx, "发起单点登录: tenant=%s issuer=%s", tenant.Code, conf.Issuer)
+	return &sysout.OidcAuthorizeModel{
+		AuthUrl: provider.AuthCodeURL(state, nonce, challenge),
+		State:   state,
+	}, nil
+}
+
+// OidcCallback 单点登录回调，使用授权码换取ID Token并签发登录令牌
+func (s *sUser) OidcCallback(ctx context.Context, in *sysin.OidcCallbackInp) (res *sysout.LoginTokenModel, err error) {
+	cached, err := gcache.Remove(ctx, oidcStateKey(in.State))
+	if err != nil {
+		return nil, gerror.Wrap(err, "读取授权状态失败")
+	}
+	if cached.IsNil() {
+		return nil, gerror.New("授权状态无效或已过期，请重新登录")
+	}
+	var state *oidcState
+	if err = cached.Scan(&state); err != nil || state == nil {
+		return nil, gerror.New("授权状态无效或已过期，请重新登录")
+	}
+
+	var tenant *entity.Tenant
+	err = g.DB().Model("sys_tenants").Where("id = ? AND deleted_at IS NULL", state.TenantId).Scan(&tenant)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询租户失败")
+	}
+	if tenant == nil || !tenant.IsNormal() || tenant.IsExpired() {
+		return nil, gerror.New("租户不存在或不可用")
+	}
+
+	conf, provider, err := s.getOidcProvider(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := provider.Exchange(ctx, in.Code, state.CodeVerifier)
+	if err != nil {
+		service.Middleware().LogSecurity(ctx, "OIDC_LOGIN_FAILED", "medium", "单点登录换取令牌失败", tenant.Code, err.Error())
+		return nil, gerror.Wrap(err, "单点登录失败")
+	}
+	claims, err := provider.VerifyIdToken(ctx, token.IdToken, state.Nonce)
+	if err != nil {
+		service.Middleware().LogSecurity(ctx, "OIDC_LOGIN_FAILED", "high", "单点登录ID Token验证失败", tenant.Code, err.Error())
+		return nil, gerror.Wrap(err, "单点登录失败")
+	}
+
+	user, err := s.resolveOidcUser(ctx, tenant, conf, claims)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, gerror.New("用户已被禁用或锁定，无法登录")
+	}
+
+	service.Middleware().LogSecurity(ctx, "OIDC_LOGIN", "low", "单点登录", tenant.Code, user.Id, claims.Issuer)
+
+	// 已启用双因子认证或注册了通行密钥的用户仍需完成本系统的二次验证
+	if factors := s.secondFactors(ctx, user.Id, user.TwoFactorEnabled == entity.TwoFactorEnabled); len(factors) > 0 {
+		return s.createTwoFactorChallenge(ctx, user.Id, tenant.Id, factors)
+	}
+	return s.issueLoginToken(ctx, sysout.ConvertToUserModel(user), tenant)
+}
+
+// getOidcProvider 获取租户的身份提供方，发现结果按配置缓存
+func (s *sUser) getOidcProvider(ctx context.Context, tenant *entity.Tenant) (*entity.OidcProvider, *oidc.Provider, error) {
+	tenantConfig, err := tenant.ParseConfig()
+	if err != nil {
+		return nil, nil, gerror.Wrap(err, "解析租户配置失败")
+	}
+	conf := tenantConfig.OidcProvider()
+	if conf == nil {
+		return nil, nil, gerror.New("当前租户未启用单点登录")
+	}
+
+	// 缓存键包含配置摘要，租户修改配置后自动使用新的身份提供方
+	sum := sha256.Sum256([]byte(strings.Join([]string{conf.Issuer, conf.ClientId, conf.ClientSecret, conf.RedirectUrl, strings.Join(conf.Scopes, " ")}, "\n")))
+	key := fmt.Sprintf("oidc_provider_%d_%s", tenant.Id, hex.EncodeToString(sum[:8]))
+
+	value, err := gcache.GetOrSetFuncLock(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return oidc.NewProvider(ctx, oidc.Config{
+			Issuer:       conf.Issuer,
+			ClientId:     conf.ClientId,
+			ClientSecret: conf.ClientSecret,
+			RedirectURL:  conf.RedirectUrl,
+			Scopes:       conf.Scopes,
+		}, nil)
+	}, time.Hour)
+	if err != nil {
+		return nil, nil, gerror.Wrap(err, "连接身份提供方失败")
+	}
+	provider, ok := value.Val().(*oidc.Provider)
+	if !ok || provider == nil {
+		return nil, nil, gerror.New("连接身份提供方失败")
+	}
+	return conf, provider, nil
+}
+
+// resolveOidcUser 根据外部身份查找本地用户，未关联时按配置关联或自动创建
+func (s *sUser) resolveOidcUser(ctx context.Context, tenant *entity.Tenant, conf *entity.OidcProvider, claims *oidc.Claims) (*entity.User, error) {
+	var identity *entity.UserIdentity
+	err := g.DB().Model("sys_user_identities").
+		Where("tenant_id = ? AND issuer = ? AND subject = ?", tenant.Id, claims.Issuer, claims.Subject).
+		Scan(&identity)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询外部身份失败")
+	}
+
+	var userId int64
+	if identity != nil {
+		userId = identity.UserId
+		_, err = g.DB().Model("sys_user_identities").Where("id", identity.Id).Update(g.Map{
+			"email":         claims.Email,
+			"last_login_at": gtime.Now(),
+		})
+		if err != nil {
+			g.Log().Warningf(ctx, "更新外部身份[%d]登录时间失败: %v", identity.Id, err)
+		}
+	} else {
+		if userId, err = s.linkOidcIdentity(ctx, tenant, conf, claims); err != nil {
+			return nil, err
+		}
+	}
+
+	var user *entity.User
+	err = g.DB().Model("sys_users").
+		Where("id = ? AND tenant_id = ? AND deleted_at IS NULL", userId, tenant.Id).
+		Scan(&user)
+	if err != nil {
+		return nil, gerror.Newf("查询用户失败: %v", err)
+	}
+	if user == nil {
+		return nil, gerror.New("关联的用户不存在")
+	}
+	return user, nil
+}
+
+// linkOidcIdentity 首次单点登录时关联已有用户或自动创建用户
+// 只有身份提供方确认邮箱已验证时才按邮箱关联，防止通过伪造邮箱接管账号
+func (s *sUser) linkOidcIdentity(ctx context.Context, tenant *entity.Tenant, conf *entity.OidcProvider, claims *oidc.Claims) (userId int64, err error) {
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		if conf.LinkByEmail && claims.Email != "" && claims.EmailVerified {
+			existing, err := tx.Model("sys_users").
+				Where("tenant_id = ? AND email = ? AND deleted_at IS NULL", tenant.Id, claims.Email).
+				Value("id")
+			if err != nil {
+				return gerror.Wrap(err, "查询用户失败")
+			}
+			userId = existing.Int64()
+		}
+
+		if userId == 0 {
+			if !conf.JitProvision {
+				return gerror.New("该账号未关联本系统用户，请联系管理员")
+			}
+			if userId, err = s.provisionOidcUser(ctx, tx, tenant, conf, claims); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Model("sys_user_identities").Data(g.Map{
+			"tenant_id":     tenant.Id,
+			"user_id":       userId,
+			"issuer":        claims.Issuer,
+			"subject":       claims.Subject,
+			"email":         claims.Email,
+			"last_login_at": gtime.Now(),
+			"created_at":    gtime.Now(),
+		}).Insert()
+		if err != nil {
+			return gerror.Wrap(err, "保存外部身份失败")
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	service.Middleware().LogSecurity(ctx, "OIDC_IDENTITY_LINKED", "medium", "关联外部身份", tenant.Code, userId, claims.Issuer, claims.Subject)
+	return userId, nil
+}
+
+// provisionOidcUser 自动创建单点登录用户并分配默认角色
+func (s *sUser) provisionOidcUser(ctx context.Context, tx gdb.TX, tenant *entity.Tenant, conf *entity.OidcProvider, claims *oidc.Claims) (int64, error) {
+	// 锁定租户记录，避免并发创建超出用户数上限
+	maxUsers, err := tx.Model("sys_tenants").Where("id", tenant.Id).LockUpdate().Value("max_users")
+	if err != nil {
+		return 0, gerror.Wrap(err, "查询租户失败")
+	}
+	if maxUsers.Int() > 0 {
+		userCount, err := tx.Model("sys_users").Where("tenant_id = ? AND deleted_at IS NULL", tenant.Id).Count()
+		if err != nil {
+			return 0, gerror.Wrap(err, "统计用户数量失败")
+		}
+		if userCount >= maxUsers.Int() {
+			return 0, gerror.New("租户用户数已达上限，无法创建用户")
+		}
+	}
+
+	username := gconv.String(claims.Raw[conf.UsernameClaim])
+	if username == "" {
+		username = claims.Subject
+	}
+	count, err := tx.Model("sys_users").
+		Where("tenant_id = ? AND username = ? AND deleted_at IS NULL", tenant.Id, username).
+		Count()
+	if err != nil {
+		return 0, gerror.Wrap(err, "查询用户失败")
+	}
+	if count > 0 {
+		return 0, gerror.Newf("用户名[%s]已存在，请联系管理员关联账号", username)
+	}
+
+	roleId, err := tx.Model("sys_roles").
+		Where("tenant_id = ? AND code = ? AND status = ?", tenant.Id, conf.DefaultRole, entity.RoleStatusEnabled).
+		Value("id")
+	if err != nil {
+		return 0, gerror.Wrap(err, "查询默认角色失败")
+	}
+	if roleId.IsNil() {
+		return 0, gerror.Newf("租户未配置默认角色[%s]，请联系管理员", conf.DefaultRole)
+	}
+
+	// 单点登录用户不使用本地密码，设置随机密码使密码登录不可用
+	randomPassword, err := oidc.RandomString(32)
+	if err != nil {
+		return 0, err
+	}
+	passwordHash, err := simple.HashPassword(ctx, randomPassword)
+	if err != nil {
+		return 0, gerror.Newf("生成密码hash失败: %v", err)
+	}
+
+	nickname := claims.Name
+	if nickname == "" {
+		nickname = username
+	}
+	data := g.Map{
+		"tenant_id":  tenant.Id,
+		"username":   username,
+		"password":   passwordHash,
+		"salt":       "",
+		"email":      claims.Email,
+		"real_name":  claims.Name,
+		"nickname":   nickname,
+		"avatar":     claims.Picture,
+		"status":     entity.UserStatusNormal,
+		"created_by": 0,
+		"updated_by": 0,
+		"created_at": gtime.Now(),
+		"updated_at": gtime.Now(),
+	}
+	if claims.Email != "" && claims.EmailVerified {
+		data["email_verified_at"] = gtime.Now()
+	}
+
+	userId, err := tx.Model("sys_users").Data(data).InsertAndGetId()
+	if err != nil {
+		return 0, gerror.Wrap(err, "创建用户失败")
+	}
+
+	_, err = tx.Model("sys_user_roles").Data(g.Map{
+		"tenant_id":  tenant.Id,
+		"user_id":    userId,
+		"role_id":    roleId.Int64(),
+		"is_primary": entity.IsPrimaryRole,
+		"created_at": gtime.Now(),
+		"updated_at": gtime.Now(),
+	}).Insert()
+	if err != nil {
+		return 0, gerror.Wrap(err, "分配默认角色失败")
+	}
+
+	service.Middleware().LogSecurity(ctx, "OIDC_USER_PROVISIONED", "low", "单点登录自动创建用户", tenant.Code, userId, username)
+	return userId, nil
+}
+
+// oidcStateKey 授权状态缓存键
+func oidcStateKey(state string) string {
+	return "oidc_state_" + state
+}
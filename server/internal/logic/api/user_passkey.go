@@ -0,0 +1,579 @@
+package api
+
+import (
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
+	"client-app/utility/simple"
+	"client-app/utility/webauthn"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcache"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// 通行密钥挑战用途
+const (
+	passkeyPurposeRegister  = "register"
+	passkeyPurposeLogin     = "login"
+	passkeyPurposeTwoFactor = "2fa"
+)
+
+// passkeyCeremony 通行密钥注册或认证过程的挑战状态，完成时取出并删除，只能使用一次
+type passkeyCeremony struct {
+	Purpose        string `json:"purpose"`
+	Challenge      string `json:"challenge"`
+	UserId         int64  `json:"userId"`
+	TenantId       uint64 `json:"tenantId"`
+	ChallengeToken string `json:"challengeToken"`
+}
+
+// PasskeyRegisterBegin 获取通行密钥注册选项
+func (s *sUser) PasskeyRegisterBegin(ctx context.Context) (res *sysout.PasskeyCreationModel, err error) {
+	operator := service.Middleware().GetCurrentUser(ctx)
+	if operator == nil {
+		return nil, gerror.New("用户未登录")
+	}
+	if operator.IsApiKey() || operator.IsImpersonated() {
+		return nil, gerror.New("当前登录方式不允许注册通行密钥")
+	}
+
+	rp, err := s.getRelyingParty(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys, err := s.getUserPasskeys(ctx, operator.Id)
+	if err != nil {
+		return nil, err
+	}
+	if limit := g.Cfg().MustGet(ctx, "webauthn.maxPerUser", 10).Int(); len(passkeys) >= limit {
+		return nil, gerror.Newf("最多只能注册%d个通行密钥", limit)
+	}
+
+	user, err := s.getTwoFactorUser(ctx, operator.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	challengeId, challenge, err := s.createPasskeyCeremony(ctx, rp, &passkeyCeremony{
+		Purpose:  passkeyPurposeRegister,
+		UserId:   operator.Id,
+		TenantId: uint64(operator.TenantId),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	options := rp.CreationOptions(webauthn.User{
+		Handle:      passkeyUserHandle(user.Id),
+		Name:        user.Username,
+		DisplayName: user.RealName,
+	}, challenge, passkeyDescriptors(passkeys))
+	return &sysout.PasskeyCreationModel{ChallengeId: challengeId, PublicKey: options}, nil
+}
+
+// PasskeyRegisterFinish 校验浏览器返回的凭证并保存通行密钥
+func (s *sUser) PasskeyRegisterFinish(ctx context.Context, in *sysin.PasskeyRegisterFinishInp) (res *sysout.PasskeyModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	operator := service.Middleware().GetCurrentUser(ctx)
+	if operator == nil {
+		return nil, gerror.New("用户未登录")
+	}
+
+	ceremony, challenge, err := s.takePasskeyCeremony(ctx, in.ChallengeId, passkeyPurposeRegister)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony.UserId != operator.Id {
+		return nil, gerror.New("注册已过期，请重试")
+	}
+
+	rp, err := s.getRelyingParty(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &webauthn.AttestationResponse{}
+	if resp.ClientDataJSON, err = webauthn.DecodeBase64(in.Credential.Response.ClientDataJSON); err != nil {
+		return nil, gerror.New("凭证数据格式错误")
+	}
+	if resp.AttestationObject, err = webauthn.DecodeBase64(in.Credential.Response.AttestationObject); err != nil {
+		return nil, gerror.New("凭证数据格式错误")
+	}
+
+	credential, err := rp.VerifyRegistration(challenge, resp)
+	if err != nil {
+		service.Middleware().LogSecurity(ctx, "PASSKEY_REGISTER_FAILED", "low", "通行密钥注册校验失败", operator.Id, err.Error())
+		return nil, gerror.Wrap(err, "通行密钥注册失败")
+	}
+	credentialId := webauthn.EncodeBase64(credential.Id)
+	if credentialId != strings.TrimRight(in.Credential.Id, "=") {
+		return nil, gerror.New("凭证ID不匹配")
+	}
+
+	exists, err := g.DB().Model("sys_user_passkeys").Where("credential_id = ?", credentialId).Count()
+	if err != nil {
+		return nil, gerror.Newf("查询通行密钥失败: %v", err)
+	}
+	if exists > 0 {
+		return nil, gerror.New("该通行密钥已注册")
+	}
+
+	passkey := &entity.UserPasskey{
+		TenantId:     ceremony.TenantId,
+		UserId:       operator.Id,
+		Name:         in.Name,
+		CredentialId: credentialId,
+		PublicKey:    webauthn.EncodeBase64(credential.PublicKey),
+		Alg:          credential.Alg,
+		SignCount:    credential.SignCount,
+		Aaguid:       formatAaguid(credential.AAGUID),
+		Transports:   strings.Join(in.Credential.Response.Transports, ","),
+		CreatedAt:    gtime.Now(),
+	}
+	if credential.BackupState {
+		passkey.BackupState = 1
+	}
+
+	id, err := g.DB().Model("sys_user_passkeys").Data(g.Map{
+		"tenant_id":     passkey.TenantId,
+		"user_id":       passkey.UserId,
+		"name":          passkey.Name,
+		"credential_id": passkey.CredentialId,
+		"public_key":    passkey.PublicKey,
+		"alg":           passkey.Alg,
+		"sign_count":    passkey.SignCount,
+		"aaguid":        passkey.Aaguid,
+		"transports":    passkey.Transports,
+		"backup_state":  passkey.BackupState,
+		"created_at":    passkey.CreatedAt,
+	}).InsertAndGetId()
+	if err != nil {
+		return nil, gerror.Newf("保存通行密钥失败: %v", err)
+	}
+	passkey.Id = id
+
+	service.Middleware().LogSecurity(ctx, "PASSKEY_REGISTERED", "low", "注册通行密钥", operator.Id, passkey.Id, passkey.Name)
+	return sysout.ConvertToPasskeyModel(passkey), nil
+}
+
+// ListPasskeys 获取本人的通行密钥
+func (s *sUser) ListPasskeys(ctx context.Context) ([]*sysout.PasskeyModel, error) {
+	operator := service.Middleware().GetCurrentUser(ctx)
+	if operator == nil {
+		return nil, gerror.New("用户未登录")
+	}
+
+	passkeys, err := s.getUserPasskeys(ctx, operator.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*sysout.PasskeyModel, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		list = append(list, sysout.ConvertToPasskeyModel(passkey))
+	}
+	return list, nil
+}
+
+// DeletePasskey 删除本人的通行密钥
+func (s *sUser) DeletePasskey(ctx context.Context, in *sysin.PasskeyDeleteInp) error {
+	if err := in.Filter(ctx); err != nil {
+		return err
+	}
+
+	operator := service.Middleware().GetCurrentUser(ctx)
+	if operator == nil {
+		return gerror.New("用户未登录")
+	}
+	if operator.IsApiKey() || operator.IsImpersonated() {
+		return gerror.New("当前登录方式不允许删除通行密钥")
+	}
+
+	result, err := g.DB().Model("sys_user_passkeys").Where("id = ? AND user_id = ?", in.Id, operator.Id).Delete()
+	if err != nil {
+		return gerror.Newf("删除通行密钥失败: %v", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return gerror.New("通行密钥不存在")
+	}
+
+	service.Middleware().LogSecurity(ctx, "PASSKEY_DELETED", "medium", "删除通行密钥", operator.Id, in.Id)
+	return nil
+}
+
+// PasskeyLoginBegin 获取通行密钥登录选项
+// 指定用户名时只允许该用户的通行密钥，否则由浏览器列出当前站点可用的通行密钥
+func (s *sUser) PasskeyLoginBegin(ctx context.Context, in *sysin.PasskeyLoginBeginInp) (res *sysout.PasskeyRequestModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.getTenantByCode(ctx, in.TenantCode)
+	if err != nil {
+		return nil, err
+	}
+
+	rp, err := s.getRelyingParty(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ceremony := &passkeyCeremony{Purpose: passkeyPurposeLogin, TenantId: tenant.Id}
+	var allow []webauthn.CredentialDescriptor
+	if in.Username != "" {
+		// 用户不存在时同样返回空列表，避免通过该接口探测用户名
+		userId, err := g.DB().Model("sys_users").
+			Where("username = ? AND tenant_id = ? AND deleted_at IS NULL", in.Username, tenant.Id).
+			Value("id")
+		if err != nil {
+			return nil, gerror.Newf("查询用户失败: %v", err)
+		}
+		if !userId.IsEmpty() {
+			passkeys, err := s.getUserPasskeys(ctx, userId.Int64())
+			if err != nil {
+				return nil, err
+			}
+			allow = passkeyDescriptors(passkeys)
+			ceremony.UserId = userId.Int64()
+		}
+	}
+
+	challengeId, challenge, err := s.createPasskeyCeremony(ctx, rp, ceremony)
+	if err != nil {
+		return nil, err
+	}
+	return &sysout.PasskeyRequestModel{ChallengeId: challengeId, PublicKey: rp.RequestOptions(challenge, allow)}, nil
+}
+
+// PasskeyLoginFinish 校验通行密钥签名并签发登录令牌
+func (s *sUser) PasskeyLoginFinish(ctx context.Context, in *sysin.PasskeyLoginFinishInp) (res *sysout.LoginTokenModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	ceremony, challenge, err := s.takePasskeyCeremony(ctx, in.ChallengeId, passkeyPurposeLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	passkey, assertion, err := s.verifyPasskeyAssertion(ctx, challenge, &in.Credential, ceremony.TenantId, ceremony.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *entity.User
+	err = g.DB().Model("sys_users").Where("id = ? AND deleted_at IS NULL", passkey.UserId).Scan(&user)
+	if err != nil {
+		return nil, gerror.Newf("查询用户失败: %v", err)
+	}
+	if user == nil || !user.IsActive() {
+		return nil, gerror.New("用户已被禁用或锁定，无法登录")
+	}
+
+	tenant, err := s.getTenantById(ctx, ceremony.TenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	service.Middleware().LogSecurity(ctx, "PASSKEY_LOGIN", "low", "通行密钥登录", tenant.Code, user.Id, passkey.Id)
+
+	// 未完成用户验证（PIN、生物识别）的通行密钥只证明持有设备，已启用动态验证码的用户仍需二次验证
+	if !assertion.UserVerified && user.IsTwoFactorEnabled() {
+		return s.createTwoFactorChallenge(ctx, user.Id, tenant.Id, []string{secondFactorTotp})
+	}
+	return s.issueLoginToken(ctx, sysout.ConvertToUserModel(user), tenant)
+}
+
+// PasskeyTwoFactorBegin 获取通行密钥二次验证选项
+func (s *sUser) PasskeyTwoFactorBegin(ctx context.Context, in *sysin.PasskeyTwoFactorBeginInp) (res *sysout.PasskeyRequestModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := s.getTwoFactorChallenge(ctx, in.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !twoFactor.allows(secondFactorPasskey) {
+		return nil, gerror.New("未注册通行密钥，请使用动态验证码验证")
+	}
+
+	passkeys, err := s.getUserPasskeys(ctx, twoFactor.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if len(passkeys) == 0 {
+		return nil, gerror.New("未注册通行密钥，请使用动态验证码验证")
+	}
+
+	rp, err := s.getRelyingParty(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	challengeId, challenge, err := s.createPasskeyCeremony(ctx, rp, &passkeyCeremony{
+		Purpose:        passkeyPurposeTwoFactor,
+		UserId:         twoFactor.UserId,
+		TenantId:       twoFactor.TenantId,
+		ChallengeToken: in.ChallengeToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sysout.PasskeyRequestModel{ChallengeId: challengeId, PublicKey: rp.RequestOptions(challenge, passkeyDescriptors(passkeys))}, nil
+}
+
+// PasskeyTwoFactorFinish 使用通行密钥完成二次验证并签发登录令牌
+func (s *sUser) PasskeyTwoFactorFinish(ctx context.Context, in *sysin.PasskeyTwoFactorFinishInp) (res *sysout.LoginTokenModel, err error) {
+	if err = in.Filter(ctx); err != nil {
+		return nil, err
+	}
+
+	ceremony, challenge, err := s.takePasskeyCeremony(ctx, in.ChallengeId, passkeyPurposeTwoFactor)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony.ChallengeToken != in.ChallengeToken {
+		return nil, gerror.New("验证已过期，请重试")
+	}
+
+	twoFactor, err := s.getTwoFactorChallenge(ctx, in.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.UserId != ceremony.UserId || !twoFactor.allows(secondFactorPasskey) {
+		return nil, gerror.New("登录已过期，请重新登录")
+	}
+
+	var user *entity.User
+	err = g.DB().Model("sys_users").Where("id = ? AND deleted_at IS NULL", twoFactor.UserId).Scan(&user)
+	if err != nil {
+		return nil, gerror.Newf("查询用户失败: %v", err)
+	}
+	if user == nil || !user.IsActive() {
+		_, _ = gcache.Remove(ctx, twoFactorChallengeKey(in.ChallengeToken))
+		return nil, gerror.New("登录已过期，请重新登录")
+	}
+
+	if _, _, err = s.verifyPasskeyAssertion(ctx, challenge, &in.Credential, twoFactor.TenantId, user.Id); err != nil {
+		if failErr := s.failTwoFactorChallenge(ctx, in.ChallengeToken, twoFactor); failErr != nil {
+			return nil, failErr
+		}
+		return nil, err
+	}
+
+	return s.completeTwoFactorLogin(ctx, in.ChallengeToken, user, twoFactor.TenantId)
+}
+
+// verifyPasskeyAssertion 校验通行密钥签名并更新签名计数器，userId 为0时不限制用户
+func (s *sUser) verifyPasskeyAssertion(ctx context.Context, challenge []byte, in *sysin.PasskeyAssertion, tenantId uint64, userId int64) (*entity.UserPasskey, *webauthn.Assertion, error) {
+	resp := &webauthn.AssertionResponse{}
+	var err error
+	if resp.CredentialId, err = webauthn.DecodeBase64(in.Id); err != nil {
+		return nil, nil, gerror.New("凭证数据格式错误")
+	}
+	if resp.ClientDataJSON, err = webauthn.DecodeBase64(in.Response.ClientDataJSON); err != nil {
+		return nil, nil, gerror.New("凭证数据格式错误")
+	}
+	if resp.AuthenticatorData, err = webauthn.DecodeBase64(in.Response.AuthenticatorData); err != nil {
+		return nil, nil, gerror.New("凭证数据格式错误")
+	}
+	if resp.Signature, err = webauthn.DecodeBase64(in.Response.Signature); err != nil {
+		return nil, nil, gerror.New("凭证数据格式错误")
+	}
+	if resp.UserHandle, err = webauthn.DecodeBase64(in.Response.UserHandle); err != nil {
+		return nil, nil, gerror.New("凭证数据格式错误")
+	}
+
+	var passkey *entity.UserPasskey
+	err = g.DB().Model("sys_user_passkeys").
+		Where("credential_id = ? AND tenant_id = ?", webauthn.EncodeBase64(resp.CredentialId), tenantId).
+		Scan(&passkey)
+	if err != nil {
+		return nil, nil, gerror.Newf("查询通行密钥失败: %v", err)
+	}
+	if passkey == nil || (userId != 0 && passkey.UserId != userId) {
+		service.Middleware().LogSecurity(ctx, "PASSKEY_LOGIN_FAILED", "medium", "通行密钥未注册", tenantId, userId)
+		return nil, nil, gerror.New("通行密钥未注册或已删除")
+	}
+
+	// 可发现凭证会返回用户句柄，必须与凭证所属用户一致
+	if len(resp.UserHandle) > 0 && string(resp.UserHandle) != string(passkeyUserHandle(passkey.UserId)) {
+		service.Middleware().LogSecurity(ctx, "PASSKEY_LOGIN_FAILED", "high", "通行密钥用户句柄不匹配", passkey.UserId, passkey.Id)
+		return nil, nil, gerror.New("通行密钥验证失败")
+	}
+
+	rp, err := s.getRelyingParty(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKey, err := webauthn.DecodeBase64(passkey.PublicKey)
+	if err != nil {
+		return nil, nil, gerror.New("通行密钥数据损坏")
+	}
+	credentialId, _ := webauthn.DecodeBase64(passkey.CredentialId)
+
+	assertion, err := rp.VerifyAssertion(challenge, resp, credentialId, publicKey, passkey.SignCount)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrSignCount) {
+			service.Middleware().LogSecurity(ctx, "PASSKEY_CLONE_DETECTED", "high", "通行密钥签名计数器回退，认证器可能已被复制", passkey.UserId, passkey.Id)
+		} else {
+			service.Middleware().LogSecurity(ctx, "PASSKEY_LOGIN_FAILED", "medium", "通行密钥签名验证失败", passkey.UserId, passkey.Id, err.Error())
+		}
+		return nil, nil, gerror.New("通行密钥验证失败")
+	}
+
+	var clientIp string
+	if r := g.RequestFromCtx(ctx); r != nil {
+		clientIp = r.GetClientIp()
+	}
+
+	// 以旧计数器作为条件更新，并发使用同一计数器的请求只有一个能通过
+	result, err := g.DB().Model("sys_user_passkeys").
+		Where("id = ? AND sign_count = ?", passkey.Id, passkey.SignCount).
+		Update(g.Map{
+			"sign_count":   assertion.SignCount,
+			"backup_state": assertion.BackupState,
+			"last_used_at": gtime.Now(),
+			"last_used_ip": clientIp,
+		})
+	if err != nil {
+		return nil, nil, gerror.Newf("更新通行密钥失败: %v", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 && assertion.SignCount != 0 {
+		return nil, nil, gerror.New("通行密钥验证失败")
+	}
+	return passkey, assertion, nil
+}
+
+// getRelyingParty 根据配置创建WebAuthn依赖方，未配置 rpId 时使用 server.domain
+func (s *sUser) getRelyingParty(ctx context.Context) (*webauthn.RelyingParty, error) {
+	rpId := g.Cfg().MustGet(ctx, "webauthn.rpId").String()
+	if rpId == "" {
+		rpId = g.Cfg().MustGet(ctx, "server.domain").String()
+	}
+	origins := g.Cfg().MustGet(ctx, "webauthn.origins").Strings()
+	if len(origins) == 0 {
+		origins = []string{"https://" + rpId}
+	}
+
+	rp, err := webauthn.New(webauthn.Config{
+		RPID:             rpId,
+		RPName:           g.Cfg().MustGet(ctx, "webauthn.rpName", simple.AppName(ctx)).String(),
+		Origins:          origins,
+		UserVerification: g.Cfg().MustGet(ctx, "webauthn.userVerification", webauthn.UVPreferred).String(),
+		Timeout:          g.Cfg().MustGet(ctx, "webauthn.timeout", "5m").Duration(),
+	})
+	if err != nil {
+		return nil, gerror.Wrap(err, "通行密钥配置无效")
+	}
+	return rp, nil
+}
+
+// createPasskeyCeremony 生成挑战码并保存挑战状态，返回挑战ID
+func (s *sUser) createPasskeyCeremony(ctx context.Context, rp *webauthn.RelyingParty, ceremony *passkeyCeremony) (string, []byte, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", nil, gerror.Wrap(err, "生成挑战码失败")
+	}
+	buf := make([]byte, 16)
+	if _, err = rand.Read(buf); err != nil {
+		return "", nil, gerror.Wrap(err, "生成挑战ID失败")
+	}
+	challengeId := hex.EncodeToString(buf)
+
+	ceremony.Challenge = webauthn.EncodeBase64(challenge)
+	expire := g.Cfg().MustGet(ctx, "webauthn.timeout", "5m").Duration()
+	if err = gcache.Set(ctx, passkeyCeremonyKey(challengeId), ceremony, expire); err != nil {
+		return "", nil, gerror.Wrap(err, "保存挑战状态失败")
+	}
+	return challengeId, challenge, nil
+}
+
+// takePasskeyCeremony 取出并删除挑战状态
+func (s *sUser) takePasskeyCeremony(ctx context.Context, challengeId, purpose string) (*passkeyCeremony, []byte, error) {
+	cached, err := gcache.Remove(ctx, passkeyCeremonyKey(challengeId))
+	if err != nil {
+		return nil, nil, gerror.Wrap(err, "读取挑战状态失败")
+	}
+	if cached.IsNil() {
+		return nil, nil, gerror.New("验证已过期，请重试")
+	}
+
+	var ceremony *passkeyCeremony
+	if err = cached.Scan(&ceremony); err != nil || ceremony == nil || ceremony.Purpose != purpose {
+		return nil, nil, gerror.New("验证已过期，请重试")
+	}
+	challenge, err := webauthn.DecodeBase64(ceremony.Challenge)
+	if err != nil {
+		return nil, nil, gerror.New("验证已过期，请重试")
+	}
+	return ceremony, challenge, nil
+}
+
+// getUserPasskeys 获取用户的通行密钥
+func (s *sUser) getUserPasskeys(ctx context.Context, userId int64) ([]*entity.UserPasskey, error) {
+	var passkeys []*entity.UserPasskey
+	err := g.DB().Model("sys_user_passkeys").Where("user_id = ?", userId).OrderAsc("id").Scan(&passkeys)
+	if err != nil {
+		return nil, gerror.Newf("查询通行密钥失败: %v", err)
+	}
+	return passkeys, nil
+}
+
+// getTenantById 根据租户ID获取可用的租户
+func (s *sUser) getTenantById(ctx context.Context, tenantId uint64) (*entity.Tenant, error) {
+	var tenant *entity.Tenant
+	err := g.DB().Model("sys_tenants").Where("id = ? AND deleted_at IS NULL", tenantId).Scan(&tenant)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询租户失败")
+	}
+	if tenant == nil || !tenant.IsNormal() || tenant.IsExpired() {
+		return nil, gerror.New("租户不存在或不可用")
+	}
+	return tenant, nil
+}
+
+// passkeyDescriptors 将通行密钥转换为凭证描述
+func passkeyDescriptors(passkeys []*entity.UserPasskey) []webauthn.CredentialDescriptor {
+	descriptors := make([]webauthn.CredentialDescriptor, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		id, err := webauthn.DecodeBase64(passkey.CredentialId)
+		if err != nil {
+			continue
+		}
+		descriptors = append(descriptors, webauthn.NewCredentialDescriptor(id, passkey.TransportList()))
+	}
+	return descriptors
+}
+
+// passkeyUserHandle 用户句柄，使用用户ID避免在认证器中保存个人信息
+func passkeyUserHandle(userId int64) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(userId))
+}
+
+// formatAaguid 将AAGUID格式化为UUID字符串
+func formatAaguid(aaguid []byte) string {
+	if len(aaguid) != 16 {
+		return ""
+	}
+	h := hex.EncodeToString(aaguid)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+func passkeyCeremonyKey(challengeId string) string {
+	return "passkey_ceremony_" + challengeId
+}
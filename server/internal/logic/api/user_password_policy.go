@@ -0,0 +1,180 @@
+package api
+
+import (
+	"client-app/internal/model/entity"
+	"client-app/internal/service"
+	"client-app/utility/encrypt"
+	"client-app/utility/pwdpolicy"
+	"client-app/utility/simple"
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+// getPasswordPolicy 获取密码策略，依次使用默认策略、系统配置 password.policy 和租户配置 settings.passwordPolicy 覆盖
+// tenantId 为0时只使用系统配置，用于创建租户等还没有租户配置的场景
+func getPasswordPolicy(ctx context.Context, tenantId uint64) *pwdpolicy.Policy {
+	policy := pwdpolicy.Default()
+	if conf := g.Cfg().MustGet(ctx, "password.policy").Map(); len(conf) > 0 {
+		if err := gconv.Struct(conf, policy); err != nil {
+			g.Log().Warningf(ctx, "读取密码策略配置失败: %v", err)
+		}
+	}
+	if tenantId == 0 {
+		return policy
+	}
+
+	var tenant *entity.Tenant
+	if err := g.DB().Model("sys_tenants").Where("id = ?", tenantId).Scan(&tenant); err != nil || tenant == nil {
+		return policy
+	}
+	tenantConfig, err := tenant.ParseConfig()
+	if err != nil {
+		return policy
+	}
+	if conf := tenantConfig.PasswordPolicy(); len(conf) > 0 {
+		if err = gconv.Struct(conf, policy); err != nil {
+			g.Log().Warningf(ctx, "读取租户[%d]密码策略失败: %v", tenantId, err)
+		}
+	}
+	return policy
+}
+
+// hashNewPassword 解密并按租户密码策略校验新密码，返回新密码hash
+// 修改密码、找回密码和注册共用，保证校验规则一致；userId 为0表示新用户，不校验历史密码
+// 同一密码每次加密的密文都不同，确认密码需解密后与新密码比较
+func (s *sUser) hashNewPassword(ctx context.Context, tenantId uint64, userId int64, username, newPassword, confirmPassword string) (string, *pwdpolicy.Policy, error) {
+	decryptedPassword, err := service.Crypto().Decrypt(ctx, newPassword)
+	if err != nil {
+		return "", nil, err
+	}
+	decryptedConfirm, err := service.Crypto().Decrypt(ctx, confirmPassword)
+	if err != nil {
+		return "", nil, err
+	}
+	if decryptedPassword != decryptedConfirm {
+		return "", nil, gerror.New("两次输入的密码不一致")
+	}
+
+	policy := getPasswordPolicy(ctx, tenantId)
+	if err = policy.Validate(decryptedPassword, username); err != nil {
+		return "", nil, err
+	}
+	if userId > 0 && policy.HistoryCount > 0 {
+		if err = s.checkPasswordHistory(ctx, userId, decryptedPassword, policy.HistoryCount); err != nil {
+			return "", nil, err
+		}
+	}
+
+	hash, err := simple.HashPassword(ctx, decryptedPassword)
+	if err != nil {
+		return "", nil, gerror.Newf("生成密码hash失败: %v", err)
+	}
+	return hash, policy, nil
+}
+
+// checkPasswordHistory 检查新密码是否与当前密码或最近使用过的密码相同
+func (s *sUser) checkPasswordHistory(ctx context.Context, userId int64, password string, count int) error {
+	var user *entity.User
+	if err := g.DB().Model("sys_users").Fields("password", "salt").Where("id = ?", userId).Scan(&user); err != nil {
+		return gerror.Newf("查询用户信息失败: %v", err)
+	}
+	if user != nil {
+		if ok, _ := encrypt.VerifyPassword(password, user.Password, user.Salt); ok {
+			return gerror.New("新密码不能与当前密码相同")
+		}
+	}
+
+	var history []*entity.PasswordHistory
+	err := g.DB().Model("sys_password_history").
+		Where("user_id = ?", userId).
+		OrderDesc("id").
+		Limit(count).
+		Scan(&history)
+	if err != nil {
+		return gerror.Newf("查询历史密码失败: %v", err)
+	}
+	for _, item := range history {
+		if ok, _ := encrypt.VerifyPassword(password, item.Password, ""); ok {
+			return gerror.Newf("新密码不能与最近%d次使用过的密码相同", count)
+		}
+	}
+	return nil
+}
+
+// savePasswordHistory 记录新密码并清理超出保留次数的历史密码
+func savePasswordHistory(ctx context.Context, tx gdb.TX, userId int64, hash string, policy *pwdpolicy.Policy) error {
+	_, err := tx.Model("sys_password_history").Data(g.Map{
+		"user_id":    userId,
+		"password":   hash,
+		"created_at": gtime.Now(),
+	}).Insert()
+	if err != nil {
+		return gerror.Wrap(err, "记录历史密码失败")
+	}
+
+	keep := policy.HistoryCount
+	if keep < 1 {
+		keep = 1
+	}
+	boundary, err := tx.Model("sys_password_history").
+		Fields("id").
+		Where("user_id = ?", userId).
+		OrderDesc("id").
+		Limit(keep-1, 1).
+		Value()
+	if err != nil {
+		return gerror.Wrap(err, "清理历史密码失败")
+	}
+	if !boundary.IsNil() {
+		if _, err = tx.Model("sys_password_history").Where("user_id = ? AND id < ?", userId, boundary.Int64()).Delete(); err != nil {
+			return gerror.Wrap(err, "清理历史密码失败")
+		}
+	}
+	return nil
+}
+
+// updatePassword 更新用户密码并记录历史密码
+func (s *sUser) updatePassword(ctx context.Context, userId int64, hash string, policy *pwdpolicy.Policy) error {
+	err := g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		_, err := tx.Model("sys_users").Where("id = ?", userId).Update(g.Map{
+			"password":            hash,
+			"salt":                "",
+			"password_changed_at": gtime.Now(),
+			"updated_at":          gtime.Now(),
+		})
+		if err != nil {
+			return gerror.Newf("更新密码失败: %v", err)
+		}
+		return savePasswordHistory(ctx, tx, userId, hash, policy)
+	})
+	if err != nil {
+		return err
+	}
+
+	// 快照中的密码修改时间用于解除密码过期限制
+	s.invalidateUserPerms(ctx, userId)
+	return nil
+}
+
+// isPasswordExpired 判断用户密码是否已超过租户策略的最长使用天数
+func (s *sUser) isPasswordExpired(ctx context.Context, userId int64, tenantId uint64) bool {
+	policy := getPasswordPolicy(ctx, tenantId)
+	if policy.MaxAgeDays <= 0 {
+		return false
+	}
+
+	changedAt, err := g.DB().Model("sys_users").Where("id = ?", userId).Value("password_changed_at")
+	if err != nil {
+		g.Log().Warningf(ctx, "查询用户[%d]密码修改时间失败: %v", userId, err)
+		return false
+	}
+	if changedAt.IsNil() || changedAt.IsEmpty() {
+		return false
+	}
+	return policy.Expired(changedAt.GTime().Time, gtime.Now().Time)
+}
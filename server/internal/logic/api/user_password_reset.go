@@ -0,0 +1,161 @@
+package api
+
+import (
+	"client-app/internal/library/notify"
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/service"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcache"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// ForgotPassword 申请找回密码
+// 账号不存在、未绑定邮箱或状态异常时同样返回成功，避免被用于探测账号
+func (s *sUser) ForgotPassword(ctx context.Context, in *sysin.ForgotPasswordInp) error {
+	// 验证验证码
+	if err := s.VerifyCaptcha(ctx, in.CaptchaId, in.Captcha); err != nil {
+		return err
+	}
+
+	tenant, err := s.getTenantByCode(ctx, in.TenantCode)
+	if err != nil {
+		g.Log().Infof(ctx, "找回密码租户验证失败: tenant=%s err=%v", in.TenantCode, err)
+		return nil
+	}
+
+	var user *entity.User
+	err = g.DB().Model("sys_users").
+		Where("tenant_id = ? AND deleted_at IS NULL", tenant.Id).
+		Where("username = ? OR email = ?", in.Account, in.Account).
+		Scan(&user)
+	if err != nil {
+		return gerror.Newf("查询用户失败: %v", err)
+	}
+	if user == nil || user.Email == "" || !user.IsActive() {
+		g.Log().Infof(ctx, "找回密码账号不可用: tenant=%s account=%s", in.TenantCode, in.Account)
+		return nil
+	}
+
+	// 同一用户一分钟内只发送一次
+	throttleKey := fmt.Sprintf("password_reset_throttle_%d", user.Id)
+	if ok, _ := gcache.SetIfNotExist(ctx, throttleKey, 1, time.Minute); !ok {
+		return nil
+	}
+
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return gerror.Wrap(err, "生成重置令牌失败")
+	}
+	token := hex.EncodeToString(buf)
+	expire := g.Cfg().MustGet(ctx, "password.reset.expire", "30m").Duration()
+
+	// 只保存令牌哈希，重新申请会使之前的令牌失效
+	_, err = g.DB().Model("sys_users").Where("id = ?", user.Id).Update(g.Map{
+		"password_reset_token":   hashPasswordResetToken(token),
+		"password_reset_expires": gtime.Now().Add(expire),
+	})
+	if err != nil {
+		return gerror.Newf("保存重置令牌失败: %v", err)
+	}
+
+	link := g.Cfg().MustGet(ctx, "password.reset.url", "/reset-password").String() + "?token=" + url.QueryEscape(token)
+	err = notify.Send(ctx, &notify.Message{
+		Channel:  notify.ChannelEmail,
+		To:       user.Email,
+		Subject:  "重置密码",
+		Content:  fmt.Sprintf("您好 %s，请在%d分钟内点击以下链接重置密码：%s\n如非本人操作请忽略。", user.Username, int(expire.Minutes()), link),
+		Template: "password_reset",
+		Data: map[string]string{
+			"username": user.Username,
+			"link":     link,
+			"expire":   expire.String(),
+		},
+	})
+	if err != nil {
+		return gerror.Wrap(err, "发送重置邮件失败")
+	}
+
+	service.Middleware().LogSecurity(ctx, "PASSWORD_RESET_REQUESTED", "low", "申请找回密码", user.Id)
+	return nil
+}
+
+// ResetPasswordByToken 通过重置令牌设置新密码，令牌只能使用一次
+func (s *sUser) ResetPasswordByToken(ctx context.Context, in *sysin.ResetPasswordByTokenInp) error {
+	tokenHash := hashPasswordResetToken(in.Token)
+
+	var user *entity.User
+	err := g.DB().Model("sys_users").
+		Where("password_reset_token = ? AND deleted_at IS NULL", tokenHash).
+		Scan(&user)
+	if err != nil {
+		return gerror.Newf("查询用户失败: %v", err)
+	}
+	if user == nil || user.IsPasswordResetExpired() {
+		return gerror.New("重置链接无效或已过期")
+	}
+	if user.IsDisabled() {
+		return gerror.New("用户已被禁用，无法重置密码")
+	}
+
+	tenantId, err := g.DB().Model("sys_users").Where("id = ?", user.Id).Value("tenant_id")
+	if err != nil {
+		return gerror.Newf("查询用户租户失败: %v", err)
+	}
+
+	newPasswordHash, policy, err := s.hashNewPassword(ctx, tenantId.Uint64(), user.Id, user.Username, in.NewPassword, in.ConfirmPassword)
+	if err != nil {
+		return err
+	}
+
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		// 以令牌哈希为条件更新，并发请求只有一个能成功
+		result, err := tx.Model("sys_users").
+			Where("id = ? AND password_reset_token = ?", user.Id, tokenHash).
+			Update(g.Map{
+				"password":               newPasswordHash,
+				"salt":                   "",
+				"password_reset_token":   nil,
+				"password_reset_expires": nil,
+				"password_changed_at":    gtime.Now(),
+				"updated_at":             gtime.Now(),
+			})
+		if err != nil {
+			return gerror.Newf("更新密码失败: %v", err)
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return gerror.New("重置链接无效或已过期")
+		}
+		return savePasswordHistory(ctx, tx, user.Id, newPasswordHash, policy)
+	})
+	if err != nil {
+		return err
+	}
+
+	// 重置密码后之前签发的令牌全部失效
+	if err = s.revokeUserRefreshTokens(ctx, user.Id, entity.RefreshTokenRevokePasswordReset); err != nil {
+		g.Log().Warningf(ctx, "撤销用户[%d]刷新令牌失败: %v", user.Id, err)
+	}
+	if err = service.TokenRevocation().RevokeUser(ctx, user.Id); err != nil {
+		g.Log().Warningf(ctx, "撤销用户[%d]访问令牌失败: %v", user.Id, err)
+	}
+
+	service.Middleware().LogSecurity(ctx, "PASSWORD_RESET", "medium", "通过找回密码重置密码", user.Id)
+	return nil
+}
+
+// hashPasswordResetToken 重置令牌哈希
+func hashPasswordResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
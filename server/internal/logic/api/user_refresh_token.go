@@ -0,0 +1,157 @@
+package api
+
+import (
+	"client-app/internal/model/entity"
+	"client-app/internal/service"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// generateRefreshToken 生成刷新令牌
+// familyId 为登录会话ID，同一次登录轮换出的令牌共用；parentId 为轮换前的令牌ID，新登录时为0
+func (s *sUser) generateRefreshToken(ctx context.Context, userId int64, tenantId uint64, familyId string, parentId int64) (string, error) {
+	// 生成随机字符串作为刷新令牌
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
+
+	var clientIp string
+	if r := g.RequestFromCtx(ctx); r != nil {
+		clientIp = r.GetClientIp()
+	}
+
+	expire := g.Cfg().MustGet(ctx, "jwt.refreshExpire", "168h").Duration()
+	_, err := g.DB().Model("sys_refresh_tokens").Data(g.Map{
+		"user_id":    userId,
+		"tenant_id":  tenantId,
+		"family_id":  familyId,
+		"parent_id":  parentId,
+		"token_hash": hashRefreshToken(token),
+		"expires_at": gtime.Now().Add(expire),
+		"created_ip": clientIp,
+		"created_at": gtime.Now(),
+	}).Insert()
+	if err != nil {
+		return "", gerror.Wrap(err, "保存刷新令牌失败")
+	}
+
+	return token, nil
+}
+
+// rotateRefreshToken 验证刷新令牌并标记为已使用
+// 已使用的令牌再次出现说明令牌可能被盗用，此时撤销整个令牌族
+func (s *sUser) rotateRefreshToken(ctx context.Context, token string) (*entity.RefreshToken, error) {
+	var record *entity.RefreshToken
+	err := g.DB().Model("sys_refresh_tokens").Where("token_hash", hashRefreshToken(token)).Scan(&record)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询刷新令牌失败")
+	}
+	if record == nil || record.IsRevoked() || record.IsExpired() {
+		return nil, gerror.New("刷新令牌无效或已过期")
+	}
+
+	if record.IsUsed() {
+		s.revokeReusedRefreshToken(ctx, record)
+		return nil, gerror.New("刷新令牌已失效，请重新登录")
+	}
+
+	// 以 used_at IS NULL 为条件更新，并发刷新时只有一个请求能成功，其余视为重放
+	result, err := g.DB().Model("sys_refresh_tokens").
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", record.Id).
+		Update(g.Map{"used_at": gtime.Now()})
+	if err != nil {
+		return nil, gerror.Wrap(err, "更新刷新令牌失败")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		s.revokeReusedRefreshToken(ctx, record)
+		return nil, gerror.New("刷新令牌已失效，请重新登录")
+	}
+
+	return record, nil
+}
+
+// revokeReusedRefreshToken 检测到刷新令牌重放，撤销整个令牌族及其登录会话
+func (s *sUser) revokeReusedRefreshToken(ctx context.Context, record *entity.RefreshToken) {
+	if err := s.revokeSession(ctx, record.FamilyId, entity.RefreshTokenRevokeReuse); err != nil {
+		g.Log().Warningf(ctx, "撤销令牌族[%s]失败: %v", record.FamilyId, err)
+	}
+	service.Middleware().LogSecurity(ctx, "REFRESH_TOKEN_REUSE", "high", "刷新令牌被重放，已撤销令牌族", record.UserId, record.FamilyId)
+}
+
+// revokeRefreshTokenFamily 撤销令牌族
+func (s *sUser) revokeRefreshTokenFamily(ctx context.Context, familyId, reason string) error {
+	_, err := g.DB().Model("sys_refresh_tokens").
+		Where("family_id = ? AND revoked_at IS NULL", familyId).
+		Update(g.Map{
+			"revoked_at":    gtime.Now(),
+			"revoke_reason": reason,
+		})
+	if err != nil {
+		return gerror.Wrap(err, "撤销刷新令牌失败")
+	}
+	return nil
+}
+
+// revokeUserRefreshTokens 撤销用户所有刷新令牌及登录会话
+func (s *sUser) revokeUserRefreshTokens(ctx context.Context, userId int64, reason string) error {
+	_, err := g.DB().Model("sys_refresh_tokens").
+		Where("user_id = ? AND revoked_at IS NULL", userId).
+		Update(g.Map{
+			"revoked_at":    gtime.Now(),
+			"revoke_reason": reason,
+		})
+	if err != nil {
+		return gerror.Wrap(err, "撤销刷新令牌失败")
+	}
+	return service.Session().RevokeByUser(ctx, userId, reason)
+}
+
+// RevokeUserSessions 管理员强制用户下线，撤销其所有刷新令牌和访问令牌
+func (s *sUser) RevokeUserSessions(ctx context.Context, userId int64) error {
+	operator, err := s.checkUserOperable(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if err = s.revokeUserRefreshTokens(ctx, userId, entity.RefreshTokenRevokeAdmin); err != nil {
+		return err
+	}
+	if err = service.TokenRevocation().RevokeUser(ctx, userId); err != nil {
+		return err
+	}
+
+	service.Middleware().LogSecurity(ctx, "USER_SESSIONS_REVOKED", "medium", "管理员强制用户下线", operator.Id, userId)
+	return nil
+}
+
+// RevokeTenantSessions 租户被禁用、锁定或删除时，撤销租户下所有用户的刷新令牌、登录会话和访问令牌
+func (s *sUser) RevokeTenantSessions(ctx context.Context, tenantId uint64) error {
+	_, err := g.DB().Model("sys_refresh_tokens").
+		Where("tenant_id = ? AND revoked_at IS NULL", tenantId).
+		Update(g.Map{
+			"revoked_at":    gtime.Now(),
+			"revoke_reason": entity.RefreshTokenRevokeTenant,
+		})
+	if err != nil {
+		return gerror.Wrap(err, "撤销刷新令牌失败")
+	}
+	if err = service.Session().RevokeByTenant(ctx, tenantId, entity.RefreshTokenRevokeTenant); err != nil {
+		return err
+	}
+	return service.TokenRevocation().RevokeTenant(ctx, int64(tenantId))
+}
+
+// hashRefreshToken 刷新令牌哈希，数据库只保存哈希值
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
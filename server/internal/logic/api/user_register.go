@@ -0,0 +1,258 @@
+package api
+
+import (
+	"client-app/internal/library/contexts"
+	"client-app/internal/library/notify"
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+// Register 用户自助注册
+// 租户由 TenantFilter 中间件根据请求域名或 X-Tenant-Id 确定，注册策略读取租户配置 settings.registration
+func (s *sUser) Register(ctx context.Context, in *sysin.UserRegisterInp) (res *sysout.UserRegisterModel, err error) {
+	if err = s.VerifyCaptcha(ctx, in.CaptchaId, in.Captcha); err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.getRegisterTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := tenant.ParseConfig()
+	if err != nil {
+		return nil, gerror.Wrap(err, "解析租户配置失败")
+	}
+	policy := config.RegistrationPolicy()
+
+	switch policy.Mode {
+	case entity.RegisterModeOpen:
+	case entity.RegisterModeInvite:
+		if in.InviteCode == "" {
+			return nil, gerror.New("请输入邀请码")
+		}
+	default:
+		return nil, gerror.New("当前租户未开放注册")
+	}
+
+	passwordHash, passwordPolicy, err := s.hashNewPassword(ctx, tenant.Id, 0, in.Username, in.Password, in.ConfirmPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// 同一租户内用户名、邮箱唯一
+	count, err := g.DB().Model("sys_users").
+		Where("tenant_id = ? AND deleted_at IS NULL", tenant.Id).
+		Where("username = ? OR email = ?", in.Username, in.Email).
+		Count()
+	if err != nil {
+		return nil, gerror.Newf("查询用户失败: %v", err)
+	}
+	if count > 0 {
+		return nil, gerror.New("用户名或邮箱已被注册")
+	}
+
+	var (
+		userId      int64
+		verifyToken string
+	)
+	if policy.EmailVerification {
+		if verifyToken, err = generateEmailVerifyToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		// 锁定租户记录，避免并发注册超出用户数上限
+		maxUsers, err := tx.Model("sys_tenants").Where("id", tenant.Id).LockUpdate().Value("max_users")
+		if err != nil {
+			return gerror.Wrap(err, "查询租户失败")
+		}
+		if maxUsers.Int() > 0 {
+			userCount, err := tx.Model("sys_users").Where("tenant_id = ? AND deleted_at IS NULL", tenant.Id).Count()
+			if err != nil {
+				return gerror.Wrap(err, "统计用户数量失败")
+			}
+			if userCount >= maxUsers.Int() {
+				return gerror.New("租户用户数已达上限，无法注册")
+			}
+		}
+
+		if policy.Mode == entity.RegisterModeInvite {
+			if err = s.useInviteCode(ctx, tx, tenant.Id, in.InviteCode); err != nil {
+				return err
+			}
+		}
+
+		roleId, err := tx.Model("sys_roles").
+			Where("tenant_id = ? AND code = ? AND status = ?", tenant.Id, policy.DefaultRole, entity.RoleStatusEnabled).
+			Value("id")
+		if err != nil {
+			return gerror.Wrap(err, "查询默认角色失败")
+		}
+		if roleId.IsNil() {
+			return gerror.Newf("租户未配置默认角色[%s]，请联系管理员", policy.DefaultRole)
+		}
+
+		data := g.Map{
+			"tenant_id":           tenant.Id,
+			"username":            in.Username,
+			"password":            passwordHash,
+			"salt":                "",
+			"password_changed_at": gtime.Now(),
+			"email":               in.Email,
+			"real_name":           in.RealName,
+			"nickname":            in.Username,
+			"status":              entity.UserStatusNormal,
+			"created_by":          0,
+			"updated_by":          0,
+			"created_at":          gtime.Now(),
+			"updated_at":          gtime.Now(),
+		}
+		if verifyToken != "" {
+			data["email_verify_token"] = hashEmailVerifyToken(verifyToken)
+			data["email_verify_expires"] = gtime.Now().Add(g.Cfg().MustGet(ctx, "register.verifyExpire", "24h").Duration())
+		}
+
+		userId, err = tx.Model("sys_users").Data(data).InsertAndGetId()
+		if err != nil {
+			return gerror.Wrap(err, "创建用户失败")
+		}
+		if err = savePasswordHistory(ctx, tx, userId, passwordHash, passwordPolicy); err != nil {
+			return err
+		}
+
+		_, err = tx.Model("sys_user_roles").Data(g.Map{
+			"tenant_id":  tenant.Id,
+			"user_id":    userId,
+			"role_id":    roleId.Int64(),
+			"is_primary": entity.IsPrimaryRole,
+			"created_at": gtime.Now(),
+			"updated_at": gtime.Now(),
+		}).Insert()
+		if err != nil {
+			return gerror.Wrap(err, "分配默认角色失败")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res = &sysout.UserRegisterModel{
+		UserId:   userId,
+		Username: in.Username,
+	}
+	if verifyToken != "" {
+		if err = s.sendEmailVerification(ctx, in.Username, in.Email, verifyToken); err != nil {
+			g.Log().Warningf(ctx, "发送邮箱验证邮件失败: %v", err)
+		} else {
+			res.EmailVerificationSent = true
+		}
+	}
+
+	service.Middleware().LogSecurity(ctx, "USER_REGISTERED", "low", "用户自助注册", tenant.Code, userId, policy.Mode)
+	return res, nil
+}
+
+// VerifyEmail 确认邮箱验证链接
+func (s *sUser) VerifyEmail(ctx context.Context, in *sysin.VerifyEmailInp) error {
+	result, err := g.DB().Model("sys_users").
+		Where("email_verify_token = ? AND email_verify_expires > ? AND deleted_at IS NULL", hashEmailVerifyToken(in.Token), gtime.Now()).
+		Update(g.Map{
+			"email_verified_at":    gtime.Now(),
+			"email_verify_token":   nil,
+			"email_verify_expires": nil,
+		})
+	if err != nil {
+		return gerror.Newf("验证邮箱失败: %v", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return gerror.New("验证链接无效或已过期")
+	}
+	return nil
+}
+
+// getRegisterTenant 获取注册的目标租户
+func (s *sUser) getRegisterTenant(ctx context.Context) (*entity.Tenant, error) {
+	var tenantId uint64
+	if customCtx := contexts.Get(ctx); customCtx != nil {
+		tenantId = gconv.Uint64(customCtx.Data["tenantId"])
+	}
+	if tenantId == 0 {
+		return nil, gerror.New("无法确定注册租户")
+	}
+
+	var tenant *entity.Tenant
+	err := g.DB().Model("sys_tenants").Where("id = ? AND deleted_at IS NULL", tenantId).Scan(&tenant)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询租户失败")
+	}
+	if tenant == nil || !tenant.IsNormal() || tenant.IsExpired() {
+		return nil, gerror.New("租户不存在或不可用")
+	}
+	return tenant, nil
+}
+
+// useInviteCode 使用邀请码，以使用次数为条件更新，并发注册不会超出次数
+func (s *sUser) useInviteCode(ctx context.Context, tx gdb.TX, tenantId uint64, code string) error {
+	result, err := tx.Model("sys_invite_codes").
+		Where("tenant_id = ? AND code = ? AND used_count < max_uses", tenantId, code).
+		Where("expires_at IS NULL OR expires_at > ?", gtime.Now()).
+		Data(g.Map{"used_count": &gdb.Counter{Field: "used_count", Value: 1}}).
+		Update()
+	if err != nil {
+		return gerror.Wrap(err, "使用邀请码失败")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return gerror.New("邀请码无效或已过期")
+	}
+	return nil
+}
+
+// sendEmailVerification 发送邮箱验证链接
+func (s *sUser) sendEmailVerification(ctx context.Context, username, email, token string) error {
+	expire := g.Cfg().MustGet(ctx, "register.verifyExpire", "24h").Duration()
+	link := g.Cfg().MustGet(ctx, "register.verifyUrl", "/verify-email").String() + "?token=" + url.QueryEscape(token)
+	return notify.Send(ctx, &notify.Message{
+		Channel:  notify.ChannelEmail,
+		To:       email,
+		Subject:  "验证邮箱",
+		Content:  fmt.Sprintf("您好 %s，请在%d小时内点击以下链接验证邮箱：%s", username, int(expire.Hours()), link),
+		Template: "email_verify",
+		Data: map[string]string{
+			"username": username,
+			"link":     link,
+			"expire":   expire.String(),
+		},
+	})
+}
+
+// generateEmailVerifyToken 生成邮箱验证令牌
+func generateEmailVerifyToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", gerror.Wrap(err, "生成验证令牌失败")
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashEmailVerifyToken 邮箱验证令牌哈希
+func hashEmailVerifyToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
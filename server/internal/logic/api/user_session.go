@@ -0,0 +1,123 @@
+package api
+
+import (
+	"client-app/internal/model"
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// ListMySessions 获取本人的登录会话
+func (s *sUser) ListMySessions(ctx context.Context) ([]*sysout.SessionModel, error) {
+	operator := service.Middleware().GetCurrentUser(ctx)
+	if operator == nil {
+		return nil, gerror.New("用户未登录")
+	}
+	return s.listSessions(ctx, operator.Id, operator.SessionId)
+}
+
+// RevokeMySession 撤销本人的某个登录会话，用于下线其他设备
+func (s *sUser) RevokeMySession(ctx context.Context, in *sysin.SessionRevokeInp) error {
+	operator := service.Middleware().GetCurrentUser(ctx)
+	if operator == nil {
+		return gerror.New("用户未登录")
+	}
+
+	session, err := service.Session().Get(ctx, in.SessionId)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserId != operator.Id {
+		return gerror.New("会话不存在")
+	}
+
+	if err = s.revokeSession(ctx, session.SessionId, entity.RefreshTokenRevokeLogout); err != nil {
+		return err
+	}
+
+	service.Middleware().LogSecurity(ctx, "SESSION_REVOKED", "low", "用户撤销登录会话", operator.Id, session.SessionId)
+	return nil
+}
+
+// ListUserSessions 管理员获取用户的登录会话
+func (s *sUser) ListUserSessions(ctx context.Context, in *sysin.UserSessionListInp) ([]*sysout.SessionModel, error) {
+	operator, err := s.checkUserOperable(ctx, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return s.listSessions(ctx, in.UserId, operator.SessionId)
+}
+
+// RevokeUserSession 管理员撤销用户的某个登录会话
+func (s *sUser) RevokeUserSession(ctx context.Context, in *sysin.UserSessionRevokeInp) error {
+	operator, err := s.checkUserOperable(ctx, in.UserId)
+	if err != nil {
+		return err
+	}
+
+	session, err := service.Session().Get(ctx, in.SessionId)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserId != in.UserId {
+		return gerror.New("会话不存在")
+	}
+
+	if err = s.revokeSession(ctx, session.SessionId, entity.RefreshTokenRevokeAdmin); err != nil {
+		return err
+	}
+
+	service.Middleware().LogSecurity(ctx, "SESSION_REVOKED", "medium", "管理员撤销用户登录会话", operator.Id, in.UserId, session.SessionId)
+	return nil
+}
+
+// listSessions 获取用户有效会话并标记当前会话
+func (s *sUser) listSessions(ctx context.Context, userId int64, currentSessionId string) ([]*sysout.SessionModel, error) {
+	sessions, err := service.Session().ListByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*sysout.SessionModel, 0, len(sessions))
+	for _, session := range sessions {
+		list = append(list, sysout.ConvertToSessionModel(session, currentSessionId))
+	}
+	return list, nil
+}
+
+// revokeSession 撤销登录会话，会话下的刷新令牌和已签发的访问令牌一并失效
+func (s *sUser) revokeSession(ctx context.Context, sessionId, reason string) error {
+	if err := s.revokeRefreshTokenFamily(ctx, sessionId, reason); err != nil {
+		return err
+	}
+	if err := service.Session().Revoke(ctx, sessionId, reason); err != nil {
+		return err
+	}
+	return service.TokenRevocation().RevokeSession(ctx, sessionId)
+}
+
+// checkUserOperable 检查当前用户能否管理目标用户，非系统管理员只能操作本租户用户
+func (s *sUser) checkUserOperable(ctx context.Context, userId int64) (*model.Identity, error) {
+	operator := service.Middleware().GetCurrentUser(ctx)
+	if operator == nil {
+		return nil, gerror.New("用户未登录")
+	}
+
+	tenantId, err := g.DB().Model("sys_users").Where("id = ? AND deleted_at IS NULL", userId).Value("tenant_id")
+	if err != nil {
+		return nil, gerror.Newf("查询用户失败: %v", err)
+	}
+	if tenantId.IsNil() {
+		return nil, gerror.New("用户不存在")
+	}
+
+	if !operator.IsSystemAdmin() && tenantId.Int64() != operator.TenantId {
+		return nil, gerror.New("无权操作该用户")
+	}
+	return operator, nil
+}
@@ -5,6 +5,7 @@ import (
 	"client-app/internal/model/entity"
 	"client-app/internal/model/input/sysin"
 	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
 	"client-app/utility/simple"
 	"context"
 	"github.com/gogf/gf/v2/errors/gerror"
@@ -26,8 +27,13 @@ func (s *sUser) ValidateUserWithTenant(ctx context.Context, username, password s
 		return nil, gerror.New("用户名或密码错误")
 	}
 
-	// 验证密码
-	if err := simple.CheckPassword(password, userEntity.Salt, userEntity.Password); err != nil {
+	// 验证密码，密码由客户端使用传输加密公钥加密
+	plainPassword, err := service.Crypto().Decrypt(ctx, password)
+	if err != nil {
+		return nil, err
+	}
+	needsRehash, err := simple.CheckPassword(ctx, plainPassword, userEntity.Salt, userEntity.Password)
+	if err != nil {
 		return nil, gerror.New("用户名或密码错误")
 	}
 
@@ -47,27 +53,38 @@ func (s *sUser) ValidateUserWithTenant(ctx context.Context, username, password s
 		return nil, gerror.New("租户已过期")
 	}
 
+	// 历史MD5密码或参数过低的哈希在登录成功后自动升级
+	if needsRehash {
+		s.rehashPassword(ctx, userEntity.Id, plainPassword)
+	}
+
 	return sysout.ConvertToUserModel(userEntity), nil
 }
 
 // LoginWithTenant 用户登录（支持多租户）
 func (s *sUser) LoginWithTenant(ctx context.Context, in *sysin.UserLoginInp) (res *sysout.LoginTokenModel, err error) {
-	// 验证验证码
-	if err = s.VerifyCaptcha(ctx, in.CaptchaId, in.Captcha); err != nil {
+	// 登录保护：IP限制、临时锁定，失败次数过多时要求验证码
+	attempt := newLoginAttempt(ctx, in)
+	if err = s.checkLoginAttempt(ctx, attempt, in); err != nil {
 		return nil, err
 	}
 
 	// 根据租户编码获取租户信息
 	tenant, err := s.getTenantByCode(ctx, in.TenantCode)
 	if err != nil {
-		return nil, gerror.Newf("租户验证失败: %v", err)
+		return nil, s.loginFailed(ctx, attempt, 0, gerror.Newf("租户验证失败: %v", err))
+	}
+
+	if err = s.checkAccountLock(ctx, attempt, tenant.Id); err != nil {
+		return nil, err
 	}
 
 	// 验证用户密码（包含租户信息）
 	user, err := s.ValidateUserWithTenant(ctx, in.Username, in.Password, tenant.Id)
 	if err != nil {
-		return nil, err
+		return nil, s.loginFailed(ctx, attempt, tenant.Id, err)
 	}
+	s.loginSucceeded(ctx, attempt)
 
 	// 检查用户状态
 	if user.Status != entity.UserStatusNormal {
@@ -81,32 +98,56 @@ func (s *sUser) LoginWithTenant(ctx context.Context, in *sysin.UserLoginInp) (re
 		}
 	}
 
+	// 已启用双因子认证或注册了通行密钥时只返回挑战令牌，通过 /login/2fa 或 /login/2fa/passkey 换取访问令牌
+	if factors := s.secondFactors(ctx, user.Id, user.TwoFactorEnabled == entity.TwoFactorEnabled); len(factors) > 0 {
+		return s.createTwoFactorChallenge(ctx, user.Id, tenant.Id, factors)
+	}
+
+	return s.issueLoginToken(ctx, user, tenant)
+}
+
+// issueLoginToken 签发登录令牌
+func (s *sUser) issueLoginToken(ctx context.Context, user *sysout.UserModel, tenant *entity.Tenant) (res *sysout.LoginTokenModel, err error) {
 	// 获取用户角色信息（租户过滤）
 	userRole, err := s.getUserPrimaryRoleWithTenant(ctx, user.Id, tenant.Id)
 	if err != nil {
 		return nil, gerror.Newf("获取用户角色失败: %v", err)
 	}
 
+	// 创建登录会话，会话ID同时作为刷新令牌族ID
+	sessionId, err := service.Session().Create(ctx, user.Id, tenant.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 当前权限版本写入令牌，权限变化后据此发现过期的权限快照
+	permVersion, err := service.PermCache().Version(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
 	// 生成JWT Token（包含租户信息）
 	payload := &simple.JWTPayload{
-		UserId:     user.Id,
-		TenantId:   int64(tenant.Id),
-		TenantCode: tenant.Code,
-		Username:   user.Username,
-		RoleId:     userRole.RoleId,
-		RoleKey:    userRole.RoleCode,
-		DeptId:     user.DeptId,
-		App:        consts.AppApi,
-	}
-
-	secretKey := simple.GetJWTSecretKey(ctx)
-	accessToken, err := simple.GenerateJWTToken(payload, secretKey)
+		UserId:      user.Id,
+		TenantId:    int64(tenant.Id),
+		TenantCode:  tenant.Code,
+		Username:    user.Username,
+		RoleId:      userRole.RoleId,
+		RoleKey:     userRole.RoleCode,
+		DeptId:      user.DeptId,
+		App:         consts.AppApi,
+		Sid:         sessionId,
+		Mcp:         s.isPasswordExpired(ctx, user.Id, tenant.Id),
+		PermVersion: permVersion,
+	}
+
+	accessToken, err := simple.GenerateJWTToken(ctx, payload)
 	if err != nil {
 		return nil, gerror.Newf("生成访问令牌失败: %v", err)
 	}
 
 	// 生成刷新令牌
-	refreshToken, err := s.generateRefreshToken(ctx, user.Id)
+	refreshToken, err := s.generateRefreshToken(ctx, user.Id, tenant.Id, sessionId, 0)
 	if err != nil {
 		return nil, gerror.Newf("生成刷新令牌失败: %v", err)
 	}
@@ -116,8 +157,8 @@ func (s *sUser) LoginWithTenant(ctx context.Context, in *sysin.UserLoginInp) (re
 		g.Log().Warningf(ctx, "更新用户登录信息失败: %v", err)
 	}
 
-	// 获取用户权限（租户过滤）
-	permissions, menuIds, err := s.getUserPermissionsWithTenant(ctx, user.Id, tenant.Id)
+	// 获取用户权限，包括从上级角色继承的权限
+	permissions, menuIds, err := s.getUserPermissions(ctx, user.Id)
 	if err != nil {
 		g.Log().Warningf(ctx, "获取用户权限失败: %v", err)
 		permissions = []string{}
@@ -133,6 +174,8 @@ func (s *sUser) LoginWithTenant(ctx context.Context, in *sysin.UserLoginInp) (re
 		UserInfo:     user,
 		Permissions:  permissions,
 		MenuIds:      menuIds,
+
+		MustChangePassword: payload.Mcp,
 	}
 
 	return res, nil
@@ -158,64 +201,6 @@ func (s *sUser) getUserPrimaryRoleWithTenant(ctx context.Context, userId int64,
 	return userRoleWithCode, nil
 }
 
-// getUserPermissionsWithTenant 获取用户权限（租户过滤）
-func (s *sUser) getUserPermissionsWithTenant(ctx context.Context, userId int64, tenantId uint64) (permissions []string, menuIds []int64, err error) {
-	// 获取用户的所有角色
-	var roleIds []int64
-	roleIdsVal, err := g.DB().Model("sys_user_roles ur").
-		LeftJoin("sys_roles r", "ur.role_id = r.id").
-		Where("ur.user_id = ? AND ur.tenant_id = ? AND r.status = ? AND r.deleted_at IS NULL",
-			userId, tenantId, entity.RoleStatusNormal).
-		Array("ur.role_id")
-	if err != nil {
-		return nil, nil, err
-	}
-
-	for _, val := range roleIdsVal {
-		roleIds = append(roleIds, val.Int64())
-	}
-
-	if len(roleIds) == 0 {
-		return []string{}, []int64{}, nil
-	}
-
-	// 获取角色关联的菜单权限
-	var menus []*entity.Menu
-	err = g.DB().Model("sys_role_menus rm").
-		LeftJoin("sys_menus m", "rm.menu_id = m.id").
-		Where("rm.role_id IN (?) AND rm.tenant_id = ? AND m.status = ? AND m.deleted_at IS NULL",
-			roleIds, tenantId, entity.MenuStatusNormal).
-		Fields("m.*").
-		Scan(&menus)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// 提取权限标识和菜单ID
-	permissionSet := make(map[string]bool)
-	menuIdSet := make(map[int64]bool)
-
-	for _, menu := range menus {
-		if menu.Permission != "" {
-			permissionSet[menu.Permission] = true
-		}
-		menuIdSet[menu.Id] = true
-	}
-
-	// 转换为切片
-	permissions = make([]string, 0, len(permissionSet))
-	for permission := range permissionSet {
-		permissions = append(permissions, permission)
-	}
-
-	menuIds = make([]int64, 0, len(menuIdSet))
-	for menuId := range menuIdSet {
-		menuIds = append(menuIds, menuId)
-	}
-
-	return permissions, menuIds, nil
-}
-
 // GetUserByUsernameWithTenant 根据用户名获取用户（租户过滤）
 func (s *sUser) GetUserByUsernameWithTenant(ctx context.Context, username string, tenantId uint64) (user *sysout.UserModel, err error) {
 	var entity *entity.User
@@ -0,0 +1,440 @@
+package api
+
+import (
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
+	"client-app/utility/simple"
+	"client-app/utility/totp"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcache"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+const (
+	twoFactorChallengeTTL      = 5 * time.Minute // 挑战令牌有效期
+	twoFactorMaxAttempts       = 5               // 单个挑战令牌最多尝试次数
+	twoFactorSkew              = 1               // 允许前后各偏移一个时间窗口
+	twoFactorRecoveryCodeCount = 10              // 恢复码数量
+)
+
+// 二次验证方式
+const (
+	secondFactorTotp    = "totp"    // 动态验证码或恢复码
+	secondFactorPasskey = "passkey" // 通行密钥
+)
+
+// twoFactorChallenge 双因子认证挑战
+type twoFactorChallenge struct {
+	UserId   int64    `json:"userId"`
+	TenantId uint64   `json:"tenantId"`
+	Factors  []string `json:"factors"`
+	Attempts int      `json:"attempts"`
+}
+
+// allows 是否允许使用指定方式完成验证
+func (c *twoFactorChallenge) allows(factor string) bool {
+	if len(c.Factors) == 0 {
+		return factor == secondFactorTotp
+	}
+	for _, f := range c.Factors {
+		if f == factor {
+			return true
+		}
+	}
+	return false
+}
+
+// secondFactors 获取用户可用的二次验证方式，为空时无需二次验证
+// 注册了通行密钥的用户在密码登录后默认也需要验证通行密钥，可通过 webauthn.secondFactor 关闭
+func (s *sUser) secondFactors(ctx context.Context, userId int64, totpEnabled bool) []string {
+	var factors []string
+	if totpEnabled {
+		factors = append(factors, secondFactorTotp)
+	}
+	if g.Cfg().MustGet(ctx, "webauthn.secondFactor", true).Bool() {
+		count, err := g.DB().Model("sys_user_passkeys").Where("user_id = ?", userId).Count()
+		if err != nil {
+			g.Log().Warningf(ctx, "查询用户通行密钥失败: %v", err)
+		} else if count > 0 {
+			factors = append(factors, secondFactorPasskey)
+		}
+	}
+	return factors
+}
+
+// createTwoFactorChallenge 第一步验证通过后创建双因子认证挑战
+func (s *sUser) createTwoFactorChallenge(ctx context.Context, userId int64, tenantId uint64, factors []string) (*sysout.LoginTokenModel, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, gerror.Wrap(err, "生成挑战令牌失败")
+	}
+	token := hex.EncodeToString(buf)
+
+	challenge := &twoFactorChallenge{UserId: userId, TenantId: tenantId, Factors: factors}
+	if err := gcache.Set(ctx, twoFactorChallengeKey(token), challenge, twoFactorChallengeTTL); err != nil {
+		return nil, gerror.Wrap(err, "保存挑战令牌失败")
+	}
+
+	return &sysout.LoginTokenModel{
+		ExpiresIn:         int64(twoFactorChallengeTTL.Seconds()),
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		SecondFactors:     factors,
+	}, nil
+}
+
+// getTwoFactorChallenge 获取双因子认证挑战
+func (s *sUser) getTwoFactorChallenge(ctx context.Context, token string) (*twoFactorChallenge, error) {
+	val, err := gcache.Get(ctx, twoFactorChallengeKey(token))
+	if err != nil {
+		return nil, gerror.Wrap(err, "读取挑战令牌失败")
+	}
+	if val.IsNil() {
+		return nil, gerror.New("登录已过期，请重新登录")
+	}
+
+	var challenge *twoFactorChallenge
+	if err = val.Scan(&challenge); err != nil || challenge == nil {
+		return nil, gerror.New("登录已过期，请重新登录")
+	}
+	return challenge, nil
+}
+
+// failTwoFactorChallenge 记录一次验证失败，失败次数过多时作废挑战令牌
+func (s *sUser) failTwoFactorChallenge(ctx context.Context, token string, challenge *twoFactorChallenge) error {
+	challenge.Attempts++
+	if challenge.Attempts >= twoFactorMaxAttempts {
+		_, _ = gcache.Remove(ctx, twoFactorChallengeKey(token))
+		service.Middleware().LogSecurity(ctx, "2FA_CHALLENGE_EXHAUSTED", "medium", "双因子认证失败次数过多", challenge.UserId)
+		return gerror.New("验证失败次数过多，请重新登录")
+	}
+	_, _, _ = gcache.Update(ctx, twoFactorChallengeKey(token), challenge)
+	return nil
+}
+
+// completeTwoFactorLogin 二次验证通过，作废挑战令牌并签发登录令牌
+func (s *sUser) completeTwoFactorLogin(ctx context.Context, token string, user *entity.User, tenantId uint64) (*sysout.LoginTokenModel, error) {
+	// 挑战令牌只能使用一次
+	if _, err := gcache.Remove(ctx, twoFactorChallengeKey(token)); err != nil {
+		return nil, gerror.Wrap(err, "清除挑战令牌失败")
+	}
+
+	var tenant *entity.Tenant
+	err := g.DB().Model("sys_tenants").Where("id = ? AND deleted_at IS NULL", tenantId).Scan(&tenant)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询租户失败")
+	}
+	if tenant == nil {
+		return nil, gerror.New("租户不存在")
+	}
+	if !tenant.IsNormal() {
+		return nil, gerror.New("租户已被禁用或锁定")
+	}
+	if tenant.IsExpired() {
+		return nil, gerror.New("租户已过期")
+	}
+
+	return s.issueLoginToken(ctx, sysout.ConvertToUserModel(user), tenant)
+}
+
+// LoginTwoFactor 使用挑战令牌和动态验证码完成登录
+func (s *sUser) LoginTwoFactor(ctx context.Context, in *sysin.UserLoginTwoFactorInp) (res *sysout.LoginTokenModel, err error) {
+	challenge, err := s.getTwoFactorChallenge(ctx, in.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.allows(secondFactorTotp) {
+		return nil, gerror.New("未启用动态验证码，请使用通行密钥验证")
+	}
+
+	var user *entity.User
+	err = g.DB().Model("sys_users").Where("id = ? AND deleted_at IS NULL", challenge.UserId).Scan(&user)
+	if err != nil {
+		return nil, gerror.Newf("查询用户失败: %v", err)
+	}
+	if user == nil || !user.IsActive() || !user.IsTwoFactorEnabled() {
+		_, _ = gcache.Remove(ctx, twoFactorChallengeKey(in.ChallengeToken))
+		return nil, gerror.New("登录已过期，请重新登录")
+	}
+
+	if err = s.verifyTwoFactorCode(ctx, user, in.Code, true); err != nil {
+		if failErr := s.failTwoFactorChallenge(ctx, in.ChallengeToken, challenge); failErr != nil {
+			return nil, failErr
+		}
+		return nil, err
+	}
+
+	return s.completeTwoFactorLogin(ctx, in.ChallengeToken, user, challenge.TenantId)
+}
+
+// EnrollTwoFactor 生成双因子认证密钥，需调用 ConfirmTwoFactor 确认后才会启用
+func (s *sUser) EnrollTwoFactor(ctx context.Context, userId int64) (res *sysout.TwoFactorEnrollModel, err error) {
+	user, err := s.getTwoFactorUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, gerror.New("双因子认证已启用，如需更换请先关闭")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.DB().Model("sys_users").Where("id = ?", userId).Update(g.Map{
+		"two_factor_secret": secret,
+		"updated_at":        gtime.Now(),
+	})
+	if err != nil {
+		return nil, gerror.Newf("保存双因子密钥失败: %v", err)
+	}
+
+	uri := totp.URI(simple.AppName(ctx), user.Username, secret)
+	qrCode, err := totp.QRCodeImage(uri, 256)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &sysout.TwoFactorEnrollModel{
+		Secret:     secret,
+		OtpauthUrl: uri,
+		QrCode:     qrCode,
+	}
+	return res, nil
+}
+
+// ConfirmTwoFactor 校验验证器生成的动态码并启用双因子认证，返回恢复码
+func (s *sUser) ConfirmTwoFactor(ctx context.Context, userId int64, in *sysin.TwoFactorConfirmInp) (res *sysout.TwoFactorRecoveryCodesModel, err error) {
+	user, err := s.getTwoFactorUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, gerror.New("双因子认证已启用")
+	}
+	if user.TwoFactorSecret == "" {
+		return nil, gerror.New("请先获取双因子认证密钥")
+	}
+
+	if !s.verifyTotp(ctx, user, in.Code) {
+		return nil, gerror.New("动态验证码不正确")
+	}
+
+	var codes []string
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		_, err := tx.Model("sys_users").Where("id = ?", userId).Update(g.Map{
+			"two_factor_enabled": entity.TwoFactorEnabled,
+			"updated_at":         gtime.Now(),
+		})
+		if err != nil {
+			return gerror.Newf("启用双因子认证失败: %v", err)
+		}
+
+		codes, err = s.resetRecoveryCodes(ctx, tx, userId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	service.Middleware().LogSecurity(ctx, "2FA_ENABLED", "low", "启用双因子认证", userId)
+	return &sysout.TwoFactorRecoveryCodesModel{Codes: codes}, nil
+}
+
+// DisableTwoFactor 关闭双因子认证，需要同时验证密码和动态码
+func (s *sUser) DisableTwoFactor(ctx context.Context, userId int64, in *sysin.TwoFactorDisableInp) error {
+	user, err := s.getTwoFactorUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !user.IsTwoFactorEnabled() {
+		return gerror.New("双因子认证未启用")
+	}
+
+	password, err := service.Crypto().Decrypt(ctx, in.Password)
+	if err != nil {
+		return err
+	}
+	if _, err = simple.CheckPassword(ctx, password, user.Salt, user.Password); err != nil {
+		return gerror.New("密码错误")
+	}
+	if err = s.verifyTwoFactorCode(ctx, user, in.Code, true); err != nil {
+		return err
+	}
+
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		_, err := tx.Model("sys_users").Where("id = ?", userId).Update(g.Map{
+			"two_factor_enabled": entity.TwoFactorDisabled,
+			"two_factor_secret":  "",
+			"updated_at":         gtime.Now(),
+		})
+		if err != nil {
+			return gerror.Newf("关闭双因子认证失败: %v", err)
+		}
+
+		if _, err = tx.Model("sys_user_recovery_codes").Where("user_id = ?", userId).Delete(); err != nil {
+			return gerror.Newf("清除恢复码失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	service.Middleware().LogSecurity(ctx, "2FA_DISABLED", "medium", "关闭双因子认证", userId)
+	return nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧恢复码全部作废
+func (s *sUser) RegenerateRecoveryCodes(ctx context.Context, userId int64, in *sysin.TwoFactorRecoveryCodesInp) (res *sysout.TwoFactorRecoveryCodesModel, err error) {
+	user, err := s.getTwoFactorUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsTwoFactorEnabled() {
+		return nil, gerror.New("双因子认证未启用")
+	}
+	if !s.verifyTotp(ctx, user, in.Code) {
+		return nil, gerror.New("动态验证码不正确")
+	}
+
+	var codes []string
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) (err error) {
+		codes, err = s.resetRecoveryCodes(ctx, tx, userId)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	service.Middleware().LogSecurity(ctx, "2FA_RECOVERY_CODES_RESET", "low", "重新生成恢复码", userId)
+	return &sysout.TwoFactorRecoveryCodesModel{Codes: codes}, nil
+}
+
+// getTwoFactorUser 获取用户信息
+func (s *sUser) getTwoFactorUser(ctx context.Context, userId int64) (*entity.User, error) {
+	var user *entity.User
+	err := g.DB().Model("sys_users").Where("id = ? AND deleted_at IS NULL", userId).Scan(&user)
+	if err != nil {
+		return nil, gerror.Newf("查询用户信息失败: %v", err)
+	}
+	if user == nil {
+		return nil, gerror.New("用户不存在")
+	}
+	return user, nil
+}
+
+// verifyTwoFactorCode 校验动态验证码，allowRecovery 为 true 时也接受恢复码
+func (s *sUser) verifyTwoFactorCode(ctx context.Context, user *entity.User, code string, allowRecovery bool) error {
+	if len(code) == totp.Digits {
+		if s.verifyTotp(ctx, user, code) {
+			return nil
+		}
+		return gerror.New("动态验证码不正确")
+	}
+
+	if !allowRecovery {
+		return gerror.New("动态验证码不正确")
+	}
+
+	ok, err := s.useRecoveryCode(ctx, user.Id, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return gerror.New("恢复码不正确或已使用")
+	}
+	service.Middleware().LogSecurity(ctx, "2FA_RECOVERY_CODE_USED", "medium", "使用恢复码验证", user.Id)
+	return nil
+}
+
+// verifyTotp 校验动态验证码，同一时间窗口内的验证码只能使用一次
+func (s *sUser) verifyTotp(ctx context.Context, user *entity.User, code string) bool {
+	step, ok := totp.Validate(user.TwoFactorSecret, code, time.Now(), twoFactorSkew)
+	if !ok {
+		return false
+	}
+
+	cacheKey := fmt.Sprintf("2fa_last_step_%d", user.Id)
+	last, _ := gcache.Get(ctx, cacheKey)
+	if last != nil && !last.IsNil() && step <= last.Int64() {
+		return false
+	}
+
+	ttl := time.Duration(totp.Period*(2*twoFactorSkew+1)) * time.Second
+	_ = gcache.Set(ctx, cacheKey, step, ttl)
+	return true
+}
+
+// useRecoveryCode 使用恢复码，成功后该恢复码失效
+func (s *sUser) useRecoveryCode(ctx context.Context, userId int64, code string) (bool, error) {
+	result, err := g.DB().Model("sys_user_recovery_codes").
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, hashRecoveryCode(code)).
+		Update(g.Map{"used_at": gtime.Now()})
+	if err != nil {
+		return false, gerror.Newf("校验恢复码失败: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, gerror.Newf("校验恢复码失败: %v", err)
+	}
+	return affected > 0, nil
+}
+
+// resetRecoveryCodes 删除旧恢复码并生成新的恢复码，只保存哈希值
+func (s *sUser) resetRecoveryCodes(ctx context.Context, tx gdb.TX, userId int64) ([]string, error) {
+	if _, err := tx.Model("sys_user_recovery_codes").Where("user_id = ?", userId).Delete(); err != nil {
+		return nil, gerror.Newf("清除恢复码失败: %v", err)
+	}
+
+	var (
+		codes = make([]string, 0, twoFactorRecoveryCodeCount)
+		data  = make(g.List, 0, twoFactorRecoveryCodeCount)
+		now   = gtime.Now()
+	)
+	for i := 0; i < twoFactorRecoveryCodeCount; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, gerror.Wrap(err, "生成恢复码失败")
+		}
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(buf))
+		code := raw[:4] + "-" + raw[4:]
+		codes = append(codes, code)
+		data = append(data, g.Map{
+			"user_id":    userId,
+			"code_hash":  hashRecoveryCode(code),
+			"created_at": now,
+		})
+	}
+
+	if _, err := tx.Model("sys_user_recovery_codes").Data(data).Insert(); err != nil {
+		return nil, gerror.Newf("保存恢复码失败: %v", err)
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode 恢复码哈希，忽略大小写和分隔符
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func twoFactorChallengeKey(token string) string {
+	return "login_2fa_" + token
+}
@@ -0,0 +1,252 @@
+package apikey
+
+import (
+	"client-app/internal/consts"
+	"client-app/internal/model"
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
+	"client-app/utility/permission"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcache"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+type sApiKey struct{}
+
+func init() {
+	service.RegisterApiKey(NewApiKey())
+}
+
+func NewApiKey() *sApiKey {
+	return &sApiKey{}
+}
+
+// Create 为当前用户创建API密钥，权限范围必须是用户已有权限的子集
+func (s *sApiKey) Create(ctx context.Context, in *sysin.ApiKeyCreateInp) (res *sysout.ApiKeyCreateModel, err error) {
+	identity, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.ExpiresAt != nil && in.ExpiresAt.Before(gtime.Now()) {
+		return nil, gerror.New("过期时间不能早于当前时间")
+	}
+	for _, ip := range in.AllowedIps {
+		if net.ParseIP(ip) == nil {
+			if _, _, err = net.ParseCIDR(ip); err != nil {
+				return nil, gerror.Newf("无效的IP或网段: %s", ip)
+			}
+		}
+	}
+
+	permissions, err := service.Role().GetUserPermissions(ctx, identity.Id)
+	if err != nil {
+		return nil, err
+	}
+	scopes := make([]string, 0, len(in.Scopes))
+	seen := make(map[string]struct{}, len(in.Scopes))
+	for _, scope := range in.Scopes {
+		if scope == "" {
+			continue
+		}
+		if !permission.MatchAny(permissions, scope) {
+			return nil, gerror.Newf("权限[%s]不属于当前用户，无法授予API密钥", scope)
+		}
+		if _, ok := seen[scope]; !ok {
+			seen[scope] = struct{}{}
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, gerror.New("请至少选择一项权限")
+	}
+
+	maxKeys := g.Cfg().MustGet(ctx, "apiKey.maxPerUser", 20).Int()
+	count, err := g.DB().Model("sys_api_keys").Where("user_id = ? AND revoked_at IS NULL", identity.Id).Count()
+	if err != nil {
+		return nil, gerror.Wrap(err, "统计API密钥失败")
+	}
+	if maxKeys > 0 && count >= maxKeys {
+		return nil, gerror.Newf("每个用户最多创建%d个API密钥", maxKeys)
+	}
+
+	rawKey, prefix, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &entity.ApiKey{
+		TenantId:   uint64(identity.TenantId),
+		UserId:     identity.Id,
+		Name:       in.Name,
+		Prefix:     prefix,
+		Scopes:     strings.Join(scopes, ","),
+		AllowedIps: strings.Join(in.AllowedIps, ","),
+		ExpiresAt:  in.ExpiresAt,
+		CreatedAt:  gtime.Now(),
+	}
+	key.Id, err = g.DB().Model("sys_api_keys").Data(g.Map{
+		"tenant_id":   key.TenantId,
+		"user_id":     key.UserId,
+		"name":        key.Name,
+		"prefix":      key.Prefix,
+		"key_hash":    hashKey(rawKey),
+		"scopes":      key.Scopes,
+		"allowed_ips": key.AllowedIps,
+		"expires_at":  key.ExpiresAt,
+		"created_at":  key.CreatedAt,
+	}).InsertAndGetId()
+	if err != nil {
+		return nil, gerror.Wrap(err, "保存API密钥失败")
+	}
+
+	service.Middleware().LogSecurity(ctx, "API_KEY_CREATED", "medium", "创建API密钥", identity.Id, key.Id, key.Prefix)
+	return &sysout.ApiKeyCreateModel{
+		ApiKeyModel: sysout.ConvertToApiKeyModel(key),
+		Key:         rawKey,
+	}, nil
+}
+
+// List 获取当前用户的有效API密钥
+func (s *sApiKey) List(ctx context.Context) ([]*sysout.ApiKeyModel, error) {
+	identity, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*entity.ApiKey
+	err = g.DB().Model("sys_api_keys").
+		Where("user_id = ? AND revoked_at IS NULL", identity.Id).
+		Where("expires_at IS NULL OR expires_at > ?", gtime.Now()).
+		OrderDesc("id").
+		Scan(&keys)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询API密钥失败")
+	}
+
+	list := make([]*sysout.ApiKeyModel, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, sysout.ConvertToApiKeyModel(key))
+	}
+	return list, nil
+}
+
+// Revoke 撤销当前用户的API密钥
+func (s *sApiKey) Revoke(ctx context.Context, in *sysin.ApiKeyRevokeInp) error {
+	identity, err := s.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := g.DB().Model("sys_api_keys").
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", in.Id, identity.Id).
+		Update(g.Map{"revoked_at": gtime.Now()})
+	if err != nil {
+		return gerror.Wrap(err, "撤销API密钥失败")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return gerror.New("API密钥不存在或已撤销")
+	}
+
+	service.Middleware().LogSecurity(ctx, "API_KEY_REVOKED", "low", "撤销API密钥", identity.Id, in.Id)
+	return nil
+}
+
+// RevokeByUser 撤销用户的全部API密钥
+func (s *sApiKey) RevokeByUser(ctx context.Context, userId int64) error {
+	_, err := g.DB().Model("sys_api_keys").
+		Where("user_id = ? AND revoked_at IS NULL", userId).
+		Update(g.Map{"revoked_at": gtime.Now()})
+	if err != nil {
+		return gerror.Wrap(err, "撤销API密钥失败")
+	}
+	return nil
+}
+
+// RevokeByTenant 撤销租户下所有用户的API密钥，租户被禁用、锁定或删除时调用
+func (s *sApiKey) RevokeByTenant(ctx context.Context, tenantId uint64) error {
+	_, err := g.DB().Model("sys_api_keys").
+		Where("tenant_id = ? AND revoked_at IS NULL", tenantId).
+		Update(g.Map{"revoked_at": gtime.Now()})
+	if err != nil {
+		return gerror.Wrap(err, "撤销API密钥失败")
+	}
+	return nil
+}
+
+// Authenticate 验证API密钥，返回有效的密钥记录，IP限制由调用方检查
+func (s *sApiKey) Authenticate(ctx context.Context, rawKey string) (*entity.ApiKey, error) {
+	if !strings.HasPrefix(rawKey, consts.ApiKeyPrefix) {
+		return nil, gerror.New("API密钥格式错误")
+	}
+
+	var key *entity.ApiKey
+	if err := g.DB().Model("sys_api_keys").Where("key_hash", hashKey(rawKey)).Scan(&key); err != nil {
+		return nil, gerror.Wrap(err, "查询API密钥失败")
+	}
+	if key == nil || !key.IsActive() {
+		return nil, gerror.New("API密钥无效、已过期或已撤销")
+	}
+	return key, nil
+}
+
+// Touch 更新密钥最后使用时间，同一密钥在节流间隔内只写一次
+func (s *sApiKey) Touch(ctx context.Context, key *entity.ApiKey, clientIp string) error {
+	interval := g.Cfg().MustGet(ctx, "session.touchInterval", "1m").Duration()
+	if ok, _ := gcache.SetIfNotExist(ctx, fmt.Sprintf("api_key_touch_%d", key.Id), 1, interval); !ok {
+		return nil
+	}
+
+	_, err := g.DB().Model("sys_api_keys").Where("id", key.Id).Update(g.Map{
+		"last_used_at": gtime.Now(),
+		"last_used_ip": clientIp,
+	})
+	if err != nil {
+		return gerror.Wrap(err, "更新API密钥使用时间失败")
+	}
+	return nil
+}
+
+// currentUser 获取当前登录用户，API密钥不能用于管理API密钥，避免泄露的密钥自我续期或提权
+func (s *sApiKey) currentUser(ctx context.Context) (*model.Identity, error) {
+	identity := service.Middleware().GetCurrentUser(ctx)
+	if identity == nil {
+		return nil, gerror.New("用户未登录")
+	}
+	if identity.IsApiKey() {
+		return nil, gerror.New("API密钥无权管理API密钥，请登录后操作")
+	}
+	if identity.IsImpersonated() {
+		return nil, gerror.New("模拟登录状态下不允许管理API密钥")
+	}
+	return identity, nil
+}
+
+// generateKey 生成API密钥，格式为 ak_<前缀>_<随机串>，前缀用于在列表中识别密钥
+func generateKey() (rawKey, prefix string, err error) {
+	buf := make([]byte, 36)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", gerror.Wrap(err, "生成API密钥失败")
+	}
+	prefix = hex.EncodeToString(buf[:4])
+	rawKey = consts.ApiKeyPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(buf[4:])
+	return rawKey, prefix, nil
+}
+
+// hashKey API密钥哈希，数据库只保存哈希值
+func hashKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
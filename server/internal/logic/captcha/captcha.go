@@ -0,0 +1,150 @@
+package captcha
+
+import (
+	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
+	"client-app/utility/captcha"
+	"client-app/utility/simple"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcache"
+)
+
+type sCaptcha struct {
+	once  sync.Once
+	store captchaStore
+}
+
+func init() {
+	service.RegisterCaptcha(NewCaptcha())
+}
+
+func NewCaptcha() *sCaptcha {
+	return &sCaptcha{}
+}
+
+// getStore 根据配置选择存储，未配置时集群部署使用db，单机使用memory
+func (s *sCaptcha) getStore(ctx context.Context) captchaStore {
+	s.once.Do(func() {
+		driver := g.Cfg().MustGet(ctx, "captcha.store").String()
+		if driver == "" {
+			driver = "memory"
+			if simple.IsCluster(ctx) {
+				driver = "db"
+			}
+		}
+		switch driver {
+		case "db":
+			s.store = newDBStore()
+		default:
+			if simple.IsCluster(ctx) {
+				g.Log().Warning(ctx, "集群部署下验证码使用memory存储，验证码无法在节点间共享")
+			}
+			s.store = newMemoryStore()
+		}
+	})
+	return s.store
+}
+
+// Generate 生成验证码，captchaType 为空或不支持时使用默认类型
+func (s *sCaptcha) Generate(ctx context.Context, captchaType string) (res *sysout.CaptchaModel, err error) {
+	if err = s.checkIssueLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	provider, ok := captcha.Get(captchaType)
+	if !ok {
+		if provider, ok = captcha.Get(g.Cfg().MustGet(ctx, "captcha.type", captcha.TypeDigit).String()); !ok {
+			provider, _ = captcha.Get(captcha.TypeDigit)
+		}
+	}
+
+	challenge, err := provider.Generate()
+	if err != nil {
+		return nil, gerror.Newf("生成验证码失败: %v", err)
+	}
+
+	buf := make([]byte, 16)
+	if _, err = rand.Read(buf); err != nil {
+		return nil, gerror.Wrap(err, "生成验证码ID失败")
+	}
+	captchaId := hex.EncodeToString(buf)
+
+	expire := g.Cfg().MustGet(ctx, "captcha.expire", "5m").Duration()
+	err = s.getStore(ctx).save(ctx, captchaId, &captchaRecord{
+		Type:   challenge.Type,
+		Answer: challenge.Answer,
+	}, expire)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &sysout.CaptchaModel{
+		CaptchaId:    captchaId,
+		Type:         challenge.Type,
+		CaptchaImage: challenge.Image,
+		CaptchaAudio: challenge.Audio,
+	}
+	if challenge.Slider != nil {
+		res.PieceImage = challenge.Slider.Piece
+		res.PieceY = challenge.Slider.Y
+		res.PieceSize = challenge.Slider.Size
+		res.ImageWidth = challenge.Slider.Width
+	}
+	return res, nil
+}
+
+// Verify 校验验证码，无论成功与否验证码都会失效，防止穷举
+func (s *sCaptcha) Verify(ctx context.Context, captchaId, answer string) error {
+	if captchaId == "" || answer == "" {
+		return gerror.New("请输入验证码")
+	}
+
+	record, err := s.getStore(ctx).take(ctx, captchaId)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return gerror.New("验证码已过期或不存在")
+	}
+
+	provider, ok := captcha.Get(record.Type)
+	if !ok || !provider.Verify(record.Answer, answer) {
+		return gerror.New("验证码错误")
+	}
+	return nil
+}
+
+// checkIssueLimit 限制同一IP获取验证码的频率
+func (s *sCaptcha) checkIssueLimit(ctx context.Context) error {
+	limit := g.Cfg().MustGet(ctx, "captcha.ipLimit", 30).Int()
+	r := g.RequestFromCtx(ctx)
+	if limit <= 0 || r == nil {
+		return nil
+	}
+
+	key := fmt.Sprintf("captcha_issue_ip_%s", r.GetClientIp())
+	window := g.Cfg().MustGet(ctx, "captcha.ipWindow", "1m").Duration()
+	count := 1
+	if v, _ := gcache.Get(ctx, key); v != nil {
+		count = v.Int() + 1
+	}
+	if count > limit {
+		service.Middleware().LogSecurity(ctx, "CAPTCHA_RATE_LIMITED", "low", "获取验证码过于频繁", r.GetClientIp())
+		return gerror.New("获取验证码过于频繁，请稍后再试")
+	}
+
+	// 首次计数时设置窗口过期时间，窗口内只更新计数
+	if count == 1 {
+		_ = gcache.Set(ctx, key, count, window)
+	} else {
+		_, _, _ = gcache.Update(ctx, key, count)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package captcha
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcache"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// captchaRecord 服务端保存的验证码状态
+type captchaRecord struct {
+	Type   string `json:"type"`
+	Answer string `json:"answer"`
+}
+
+// captchaStore 验证码存储
+type captchaStore interface {
+	// save 保存验证码
+	save(ctx context.Context, id string, record *captchaRecord, ttl time.Duration) error
+	// take 取出并删除验证码，保证只能使用一次，不存在或已过期返回nil
+	take(ctx context.Context, id string) (*captchaRecord, error)
+}
+
+// memoryStore 进程内存储，仅适用于单机部署
+type memoryStore struct {
+	cache *gcache.Cache
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{cache: gcache.New()}
+}
+
+func (m *memoryStore) save(ctx context.Context, id string, record *captchaRecord, ttl time.Duration) error {
+	return m.cache.Set(ctx, id, record, ttl)
+}
+
+func (m *memoryStore) take(ctx context.Context, id string) (*captchaRecord, error) {
+	val, err := m.cache.Remove(ctx, id)
+	if err != nil || val.IsNil() {
+		return nil, err
+	}
+	record, _ := val.Val().(*captchaRecord)
+	return record, nil
+}
+
+// dbStore 数据库存储，集群部署时各节点共享
+type dbStore struct{}
+
+func newDBStore() *dbStore {
+	return &dbStore{}
+}
+
+func (d *dbStore) save(ctx context.Context, id string, record *captchaRecord, ttl time.Duration) error {
+	_, err := g.DB().Model("sys_captchas").Data(g.Map{
+		"id":         id,
+		"type":       record.Type,
+		"answer":     record.Answer,
+		"expires_at": gtime.Now().Add(ttl),
+		"created_at": gtime.Now(),
+	}).Insert()
+	if err != nil {
+		return gerror.Wrap(err, "保存验证码失败")
+	}
+
+	// 顺带清理已过期的记录
+	_, _ = g.DB().Model("sys_captchas").Where("expires_at < ?", gtime.Now()).Limit(100).Delete()
+	return nil
+}
+
+func (d *dbStore) take(ctx context.Context, id string) (*captchaRecord, error) {
+	var record *captchaRecord
+	err := g.DB().Model("sys_captchas").
+		Fields("type, answer").
+		Where("id = ? AND expires_at > ?", id, gtime.Now()).
+		Scan(&record)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询验证码失败")
+	}
+	if record == nil {
+		return nil, nil
+	}
+
+	// 以删除成功为准，并发校验时只有一个请求能取到验证码
+	result, err := g.DB().Model("sys_captchas").Where("id", id).Delete()
+	if err != nil {
+		return nil, gerror.Wrap(err, "删除验证码失败")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, nil
+	}
+	return record, nil
+}
@@ -0,0 +1,160 @@
+package crypto
+
+import (
+	"client-app/internal/model/output/sysout"
+	"client-app/internal/service"
+	"client-app/utility/simple"
+	"client-app/utility/transport"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcache"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+type sCrypto struct {
+	once  sync.Once
+	store keyStore
+	mu    sync.Mutex // 串行化本节点的密钥轮换
+}
+
+func init() {
+	service.RegisterCrypto(NewCrypto())
+}
+
+func NewCrypto() *sCrypto {
+	return &sCrypto{}
+}
+
+// getStore 根据配置选择存储，未配置时集群部署使用db，单机使用memory
+func (s *sCrypto) getStore(ctx context.Context) keyStore {
+	s.once.Do(func() {
+		driver := g.Cfg().MustGet(ctx, "transport.store").String()
+		if driver == "" {
+			driver = "memory"
+			if simple.IsCluster(ctx) {
+				driver = "db"
+			}
+		}
+		switch driver {
+		case "db":
+			s.store = newDBStore()
+		default:
+			if simple.IsCluster(ctx) {
+				g.Log().Warning(ctx, "集群部署下传输加密密钥使用memory存储，其他节点无法解密本节点公钥加密的数据")
+			}
+			s.store = newMemoryStore()
+		}
+	})
+	return s.store
+}
+
+// PublicKey 获取当前的传输加密公钥，到达轮换时间后生成新密钥，旧密钥在宽限期内仍可解密
+func (s *sCrypto) PublicKey(ctx context.Context) (*sysout.PublicKeyModel, error) {
+	rotate := g.Cfg().MustGet(ctx, "transport.rotate", "1h").Duration()
+	key, err := s.activeKey(ctx, rotate)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := key.PublicKey()
+	if err != nil {
+		return nil, gerror.Wrap(err, "导出传输加密公钥失败")
+	}
+	return &sysout.PublicKeyModel{
+		Kid:           key.Kid,
+		Alg:           key.Alg,
+		PublicKey:     publicKey,
+		ExpiresAt:     gtime.New(key.CreatedAt.Add(rotate)),
+		LegacyEnabled: s.LegacyEnabled(ctx),
+	}, nil
+}
+
+// activeKey 获取当前密钥，不存在或已到轮换时间时生成新密钥
+func (s *sCrypto) activeKey(ctx context.Context, rotate time.Duration) (*transport.Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	store := s.getStore(ctx)
+	key, err := store.latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if key != nil && time.Since(key.CreatedAt) < rotate {
+		return key, nil
+	}
+
+	buf := make([]byte, 8)
+	if _, err = rand.Read(buf); err != nil {
+		return nil, gerror.Wrap(err, "生成密钥ID失败")
+	}
+
+	var (
+		now   = time.Now()
+		alg   = g.Cfg().MustGet(ctx, "transport.alg", transport.AlgRSAOAEP).String()
+		grace = g.Cfg().MustGet(ctx, "transport.grace", "10m").Duration()
+	)
+	key, err = transport.GenerateKey(hex.EncodeToString(buf), alg, now, now.Add(rotate+grace))
+	if err != nil {
+		return nil, gerror.Wrapf(err, "生成传输加密密钥失败[%s]", alg)
+	}
+	if err = store.save(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Decrypt 解密客户端提交的密码
+// 优先按 {kid, ciphertext} 格式使用传输加密密钥解密，开启 transport.legacyEcb 时兼容旧版AES-ECB密文
+func (s *sCrypto) Decrypt(ctx context.Context, text string) (string, error) {
+	if envelope, ok := transport.ParseEnvelope(text); ok {
+		key, err := s.getStore(ctx).get(ctx, envelope.Kid)
+		if err != nil {
+			return "", err
+		}
+		if key == nil || key.IsExpired(time.Now()) {
+			return "", gerror.New("加密公钥已过期，请刷新页面后重试")
+		}
+		plain, err := key.Decrypt(envelope.Ciphertext)
+		if err != nil {
+			return "", gerror.New("密码解密失败")
+		}
+		return string(plain), nil
+	}
+
+	if !s.LegacyEnabled(ctx) {
+		return "", gerror.New("请使用 /crypto/public-key 获取的公钥加密密码")
+	}
+
+	plain, err := simple.DecryptText(text)
+	if err != nil {
+		return "", gerror.Newf("密码解密失败: %v", err)
+	}
+	s.warnLegacy(ctx)
+	return plain, nil
+}
+
+// LegacyEnabled 是否兼容旧版AES-ECB密文，客户端迁移完成后应关闭
+func (s *sCrypto) LegacyEnabled(ctx context.Context) bool {
+	return g.Cfg().MustGet(ctx, "transport.legacyEcb", true).Bool()
+}
+
+// warnLegacy 提示客户端旧版加密方式已废弃，同一IP每小时记录一次日志
+func (s *sCrypto) warnLegacy(ctx context.Context) {
+	r := g.RequestFromCtx(ctx)
+	if r == nil {
+		return
+	}
+	r.Response.Header().Set("Deprecation", "true")
+
+	key := fmt.Sprintf("transport_legacy_warn_%s", r.GetClientIp())
+	if ok, _ := gcache.SetIfNotExist(ctx, key, 1, time.Hour); ok {
+		service.Middleware().LogSecurity(ctx, "LEGACY_PASSWORD_TRANSPORT", "low", "客户端仍在使用已废弃的AES-ECB密码加密", r.GetClientIp(), r.URL.Path)
+	}
+}
@@ -0,0 +1,151 @@
+package crypto
+
+import (
+	"client-app/utility/transport"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// keyStore 传输加密密钥存储
+type keyStore interface {
+	// latest 获取最新创建且未过期的密钥，没有返回nil
+	latest(ctx context.Context) (*transport.Key, error)
+	// get 根据密钥ID获取密钥，不存在返回nil
+	get(ctx context.Context, kid string) (*transport.Key, error)
+	// save 保存密钥
+	save(ctx context.Context, key *transport.Key) error
+}
+
+// memoryStore 进程内存储，仅适用于单机部署
+type memoryStore struct {
+	mu   sync.RWMutex
+	keys map[string]*transport.Key
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{keys: make(map[string]*transport.Key)}
+}
+
+func (m *memoryStore) latest(ctx context.Context) (*transport.Key, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var latest *transport.Key
+	now := time.Now()
+	for _, key := range m.keys {
+		if !key.IsExpired(now) && (latest == nil || key.CreatedAt.After(latest.CreatedAt)) {
+			latest = key
+		}
+	}
+	return latest, nil
+}
+
+func (m *memoryStore) get(ctx context.Context, kid string) (*transport.Key, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.keys[kid], nil
+}
+
+func (m *memoryStore) save(ctx context.Context, key *transport.Key) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// 顺带清理已过期的密钥
+	now := time.Now()
+	for kid, item := range m.keys {
+		if item.IsExpired(now) {
+			delete(m.keys, kid)
+		}
+	}
+	m.keys[key.Kid] = key
+	return nil
+}
+
+// keyRecord 密钥数据库记录
+type keyRecord struct {
+	Kid        string      `json:"kid"`
+	Alg        string      `json:"alg"`
+	PrivateKey string      `json:"privateKey"`
+	CreatedAt  *gtime.Time `json:"createdAt"`
+	ExpiresAt  *gtime.Time `json:"expiresAt"`
+}
+
+// dbStore 数据库存储，集群部署时各节点共享密钥，解析后的密钥缓存在本节点
+type dbStore struct {
+	cache *memoryStore
+}
+
+func newDBStore() *dbStore {
+	return &dbStore{cache: newMemoryStore()}
+}
+
+func (d *dbStore) latest(ctx context.Context) (*transport.Key, error) {
+	var record *keyRecord
+	err := g.DB().Model("sys_transport_keys").
+		Where("expires_at > ?", gtime.Now()).
+		OrderDesc("created_at").
+		Limit(1).
+		Scan(&record)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询传输加密密钥失败")
+	}
+	if record == nil {
+		return nil, nil
+	}
+	return d.parse(ctx, record)
+}
+
+func (d *dbStore) get(ctx context.Context, kid string) (*transport.Key, error) {
+	if key, _ := d.cache.get(ctx, kid); key != nil {
+		return key, nil
+	}
+
+	var record *keyRecord
+	if err := g.DB().Model("sys_transport_keys").Where("kid", kid).Scan(&record); err != nil {
+		return nil, gerror.Wrap(err, "查询传输加密密钥失败")
+	}
+	if record == nil {
+		return nil, nil
+	}
+	return d.parse(ctx, record)
+}
+
+func (d *dbStore) save(ctx context.Context, key *transport.Key) error {
+	privateKey, err := key.MarshalPrivateKey()
+	if err != nil {
+		return gerror.Wrap(err, "导出传输加密密钥失败")
+	}
+
+	_, err = g.DB().Model("sys_transport_keys").Data(g.Map{
+		"kid":         key.Kid,
+		"alg":         key.Alg,
+		"private_key": privateKey,
+		"created_at":  gtime.New(key.CreatedAt),
+		"expires_at":  gtime.New(key.ExpiresAt),
+	}).Insert()
+	if err != nil {
+		return gerror.Wrap(err, "保存传输加密密钥失败")
+	}
+
+	// 顺带清理已过期的密钥
+	_, _ = g.DB().Model("sys_transport_keys").Where("expires_at < ?", gtime.Now()).Limit(100).Delete()
+	return d.cache.save(ctx, key)
+}
+
+// parse 解析数据库记录并缓存
+func (d *dbStore) parse(ctx context.Context, record *keyRecord) (*transport.Key, error) {
+	if key, _ := d.cache.get(ctx, record.Kid); key != nil {
+		return key, nil
+	}
+	key, err := transport.ParseKey(record.Kid, record.Alg, record.PrivateKey, record.CreatedAt.Time, record.ExpiresAt.Time)
+	if err != nil {
+		return nil, gerror.Wrapf(err, "解析传输加密密钥[%s]失败", record.Kid)
+	}
+	_ = d.cache.save(ctx, key)
+	return key, nil
+}
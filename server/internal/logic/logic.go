@@ -6,6 +6,12 @@ package logic
 
 import (
 	_ "client-app/internal/logic/api"
+	_ "client-app/internal/logic/apikey"
+	_ "client-app/internal/logic/captcha"
+	_ "client-app/internal/logic/crypto"
 	_ "client-app/internal/logic/hook"
 	_ "client-app/internal/logic/middleware"
+	_ "client-app/internal/logic/permcache"
+	_ "client-app/internal/logic/session"
+	_ "client-app/internal/logic/token"
 )
@@ -2,13 +2,20 @@ package middleware
 
 import (
 	"client-app/internal/consts"
+	"client-app/internal/global"
 	"client-app/internal/library/contexts"
 	"client-app/internal/library/response"
 	"client-app/internal/model"
 	"client-app/internal/model/entity"
 	"client-app/internal/service"
+	"client-app/utility/jwt"
+	"client-app/utility/permission"
 	"client-app/utility/simple"
+	"client-app/utility/validate"
 	"context"
+	"errors"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/gogf/gf/v2/errors/gerror"
@@ -31,6 +38,12 @@ func (s *sMiddleware) ApiAuth(r *ghttp.Request) {
 		return
 	}
 
+	// API密钥认证，供脚本等机器客户端使用
+	if rawKey := s.extractApiKey(r); rawKey != "" {
+		s.apiKeyAuth(r, path, rawKey)
+		return
+	}
+
 	// 从请求头获取Token
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
@@ -46,10 +59,9 @@ func (s *sMiddleware) ApiAuth(r *ghttp.Request) {
 	}
 
 	// 解析JWT Token
-	secretKey := simple.GetJWTSecretKey(ctx)
-	payload, err := simple.ParseJWTToken(token, secretKey)
+	payload, err := simple.ParseJWTToken(ctx, token)
 	if err != nil {
-		if strings.Contains(err.Error(), "过期") {
+		if errors.Is(err, jwt.ErrTokenExpired) {
 			s.authFailed(r, consts.ErrTokenExpired, consts.GetAuthErrorMessage(consts.ErrTokenExpired))
 		} else {
 			s.authFailed(r, consts.ErrTokenInvalid, consts.GetAuthErrorMessage(consts.ErrTokenInvalid))
@@ -57,12 +69,25 @@ func (s *sMiddleware) ApiAuth(r *ghttp.Request) {
 		return
 	}
 
-	// 验证用户是否存在且状态正常
-	user, err := s.getUserFromPayload(ctx, payload)
+	// 检查令牌是否已被撤销（退出登录、禁用用户、删除租户等）
+	revoked, err := service.TokenRevocation().IsRevoked(ctx, payload)
+	if err != nil {
+		g.Log().Warningf(ctx, "检查令牌撤销状态失败: %v", err)
+		s.authFailed(r, consts.ErrTokenInvalid, consts.GetAuthErrorMessage(consts.ErrTokenInvalid))
+		return
+	}
+	if revoked {
+		s.authFailed(r, consts.ErrTokenRevoked, consts.GetAuthErrorMessage(consts.ErrTokenRevoked))
+		return
+	}
+
+	// 获取用户权限快照，令牌签发后权限有变化时快照版本落后，会重新加载
+	snapshot, err := service.PermCache().Snapshot(ctx, payload.UserId, payload.PermVersion)
 	if err != nil {
 		s.authFailed(r, consts.ErrUserNotFound, err.Error())
 		return
 	}
+	user := snapshot.User
 
 	// 验证用户状态
 	if err := s.validateUserStatus(user); err != nil {
@@ -71,9 +96,27 @@ func (s *sMiddleware) ApiAuth(r *ghttp.Request) {
 	}
 
 	// 设置用户身份到上下文
-	identity := s.buildIdentity(user, payload)
+	identity := s.buildIdentity(snapshot, payload)
 	s.setUserToContext(r, identity)
 
+	// 密码已过期时只能访问修改密码等接口，密码修改后旧令牌不再受限
+	if payload.Mcp && !s.isPasswordChangedSince(user, payload.Iat) && !s.isPasswordExpiredAllowed(ctx, path) {
+		response.JsonExit(r, consts.CodePasswordExpired, "密码已过期，请修改密码后继续使用")
+		return
+	}
+
+	// 模拟登录时禁止修改密码等敏感操作
+	if identity.IsImpersonated() && s.isImpersonateDenied(ctx, path) {
+		s.LogSecurity(ctx, "IMPERSONATE_BLOCKED", "medium", "模拟登录访问受限接口", identity.Impersonator.Id, identity.Id, path)
+		response.JsonExit(r, 403, "模拟登录状态下不允许该操作")
+		return
+	}
+
+	// 更新会话最后活跃时间，内部有节流，不影响本次请求
+	if err := service.Session().Touch(ctx, payload.Sid); err != nil {
+		g.Log().Warningf(ctx, "更新会话活跃时间失败: %v", err)
+	}
+
 	// 不需要验证权限的路由地址
 	if s.IsExceptAuth(ctx, consts.AppApi, path) {
 		r.Middleware.Next()
@@ -81,7 +124,7 @@ func (s *sMiddleware) ApiAuth(r *ghttp.Request) {
 	}
 
 	// 验证API访问权限
-	if err := s.checkAPIPermission(ctx, user.Id, path, r.Method); err != nil {
+	if err := s.checkAPIPermission(snapshot, s.routePermission(r)); err != nil {
 		s.authFailed(r, consts.ErrPermissionDenied, err.Error())
 		return
 	}
@@ -90,51 +133,76 @@ func (s *sMiddleware) ApiAuth(r *ghttp.Request) {
 	r.Middleware.Next()
 }
 
-// getUserFromPayload 从JWT payload获取用户信息
-func (s *sMiddleware) getUserFromPayload(ctx context.Context, payload *simple.JWTPayload) (*entity.User, error) {
-	var user *entity.User
-
-	// 从数据库查询用户信息
-	err := g.DB().Model("sys_users").Where("id = ? AND deleted_at IS NULL", payload.UserId).Scan(&user)
-	if err != nil {
-		return nil, gerror.Newf("查询用户信息失败: %v", err)
-	}
-
-	if user == nil {
-		return nil, gerror.New("用户不存在或已被删除")
-	}
-
-	return user, nil
-}
-
 // validateUserStatus 验证用户状态
 func (s *sMiddleware) validateUserStatus(user *entity.User) error {
 	if user.Status == entity.UserStatusDisabled {
 		return gerror.New(consts.GetAuthErrorMessage(consts.ErrUserDisabled))
 	}
-	if user.Status == entity.UserStatusLocked {
+	// 临时锁定到期的账号不再拦截，下次登录时自动解锁
+	if user.IsLocked() && !user.IsLockExpired() {
 		return gerror.New(consts.GetAuthErrorMessage(consts.ErrUserLocked))
 	}
 	return nil
 }
 
-// buildIdentity 构建用户身份信息
-func (s *sMiddleware) buildIdentity(user *entity.User, payload *simple.JWTPayload) *model.Identity {
-	return &model.Identity{
-		Id:       user.Id,
-		Pid:      0, // 如果有上级关系，这里需要从数据库查询
-		DeptId:   user.DeptId,
-		DeptType: "", // 如果有部门类型，这里需要从部门表查询
-		RoleId:   payload.RoleId,
-		RoleKey:  payload.RoleKey,
-		Username: user.Username,
-		RealName: user.RealName,
-		Avatar:   user.Avatar,
-		Email:    user.Email,
-		Mobile:   user.Phone,
-		App:      payload.App,
-		LoginAt:  gtime.Now(),
+// buildIdentity 构建用户身份信息，用户和部门信息取自权限快照
+func (s *sMiddleware) buildIdentity(snapshot *model.PermSnapshot, payload *simple.JWTPayload) *model.Identity {
+	user := snapshot.User
+	identity := &model.Identity{
+		Id:         user.Id,
+		TenantId:   payload.TenantId,
+		TenantCode: payload.TenantCode,
+		Pid:        0, // 如果有上级关系，这里需要从数据库查询
+		DeptId:     user.DeptId,
+		DeptType:   snapshot.DeptType,
+		DeptTree:   snapshot.DeptTree,
+		RoleId:     payload.RoleId,
+		RoleKey:    payload.RoleKey,
+		Username:   user.Username,
+		RealName:   user.RealName,
+		Avatar:     user.Avatar,
+		Email:      user.Email,
+		Mobile:     user.Phone,
+		App:        payload.App,
+		SessionId:  payload.Sid,
+		AuthType:   model.AuthTypeJWT,
+		LoginAt:    gtime.Now(),
+	}
+	if payload.Act != nil {
+		identity.Impersonator = &model.Impersonator{
+			Id:       payload.Act.UserId,
+			TenantId: payload.Act.TenantId,
+			Username: payload.Act.Username,
+		}
+	}
+	return identity
+}
+
+// isPasswordChangedSince 令牌签发后用户是否修改过密码
+func (s *sMiddleware) isPasswordChangedSince(user *entity.User, issuedAt int64) bool {
+	return user.PasswordChangedAt != nil && user.PasswordChangedAt.Unix() >= issuedAt
+}
+
+// isPasswordExpiredAllowed 是否是密码过期时允许访问的路由地址
+func (s *sMiddleware) isPasswordExpiredAllowed(ctx context.Context, path string) bool {
+	pathList := g.Cfg().MustGet(ctx, "password.expiredAllowPaths").Strings()
+	for i := 0; i < len(pathList); i++ {
+		if validate.InSliceExistStr(pathList[i], path) {
+			return true
+		}
+	}
+	return false
+}
+
+// isImpersonateDenied 是否是模拟登录时禁止访问的路由地址
+func (s *sMiddleware) isImpersonateDenied(ctx context.Context, path string) bool {
+	pathList := g.Cfg().MustGet(ctx, "impersonate.denyPaths").Strings()
+	for i := 0; i < len(pathList); i++ {
+		if validate.InSliceExistStr(pathList[i], path) {
+			return true
+		}
 	}
+	return false
 }
 
 // setUserToContext 设置用户信息到上下文
@@ -154,37 +222,79 @@ func (s *sMiddleware) setUserToContext(r *ghttp.Request, identity *model.Identit
 	customCtx.User = identity
 }
 
-// checkAPIPermission 检查API访问权限
-func (s *sMiddleware) checkAPIPermission(ctx context.Context, userId int64, path string, method string) error {
-	// 构造权限标识，通常是 path:method 的格式
-	permission := s.buildPermissionKey(path, method)
-
-	// 检查用户是否有该权限
-	hasPermission, err := service.Role().CheckUserPermission(ctx, userId, permission)
-	if err != nil {
-		return gerror.Newf("权限检查失败: %v", err)
+// checkAPIPermission 检查API访问权限，支持 role:* 等通配授权
+func (s *sMiddleware) checkAPIPermission(snapshot *model.PermSnapshot, permission string) error {
+	if !snapshot.HasPermission(permission) {
+		return gerror.New("您没有访问该接口的权限")
 	}
+	return nil
+}
 
-	if !hasPermission {
-		return gerror.New("您没有访问该接口的权限")
+// routePermission 获取请求匹配路由的权限标识
+// 取请求结构体g.Meta中的perm标签，未声明时按路由模板生成，不会因路径参数不同而变化
+func (s *sMiddleware) routePermission(r *ghttp.Request) string {
+	if router := global.GetRequestRoute(r); router != nil && router.Permission != "" {
+		return router.Permission
 	}
 
-	return nil
+	uri := r.URL.Path
+	if r.Router != nil {
+		uri = r.Router.Uri
+	}
+	return permission.FromPath(gstr.Replace(uri, simple.RouterPrefix(r.Context(), consts.AppApi), "", 1))
 }
 
-// buildPermissionKey 构建权限标识
-func (s *sMiddleware) buildPermissionKey(path string, method string) string {
-	// 清理路径，移除开头的斜杠
-	cleanPath := strings.TrimPrefix(path, "/")
+// CheckRoutePermissions 启动时检查需要鉴权的接口是否都声明了权限标识，未声明的接口输出告警
+func (s *sMiddleware) CheckRoutePermissions(ctx context.Context, server *ghttp.Server) {
+	prefix := simple.RouterPrefix(ctx, consts.AppApi)
+	routes := global.LoadServerRoutes(server)
 
-	// 将路径中的斜杠替换为冒号，构成权限标识
-	// 例如: /role/list -> role:list
-	permission := strings.ReplaceAll(cleanPath, "/", ":")
+	keys := make([]string, 0, len(routes))
+	for key := range routes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var missing int
+	for _, key := range keys {
+		router := routes[key]
+		if router.Type != ghttp.HandlerTypeObject && router.Type != ghttp.HandlerTypeHandler {
+			continue
+		}
+		if !strings.HasPrefix(router.Route, prefix) {
+			continue
+		}
+		// 声明了权限标识却没有经过鉴权中间件，通常是控制器在 ApiAuth 之前绑定，权限标识不会生效
+		if router.Permission != "" {
+			if !s.hasApiAuth(router.Handler.Middleware) {
+				missing++
+				g.Log().Warningf(ctx, "接口声明了权限标识[%s]但未经过ApiAuth鉴权，请在ApiAuth之后绑定: %s %s", router.Permission, router.Method, router.Route)
+			}
+			continue
+		}
 
-	// 添加HTTP方法作为后缀（可选）
-	// permission = fmt.Sprintf("%s:%s", permission, strings.ToLower(method))
+		path := gstr.Replace(router.Route, prefix, "", 1)
+		if s.IsExceptLogin(ctx, consts.AppApi, path) || s.IsExceptAuth(ctx, consts.AppApi, path) {
+			continue
+		}
+		missing++
+		g.Log().Warningf(ctx, "接口未声明权限标识，请在请求结构体g.Meta中添加perm标签: %s %s", router.Method, router.Route)
+	}
+	if missing > 0 {
+		g.Log().Warningf(ctx, "共有%d个需要鉴权的接口未声明权限标识或未经过鉴权", missing)
+	}
+}
 
-	return permission
+// hasApiAuth 路由绑定的中间件中是否包含ApiAuth
+func (s *sMiddleware) hasApiAuth(middleware []ghttp.HandlerFunc) bool {
+	// 路由绑定的是 service.Middleware().ApiAuth，需按同一方式取函数地址比较
+	apiAuth := reflect.ValueOf(service.Middleware().ApiAuth).Pointer()
+	for _, handler := range middleware {
+		if reflect.ValueOf(handler).Pointer() == apiAuth {
+			return true
+		}
+	}
+	return false
 }
 
 // authFailed 认证失败处理
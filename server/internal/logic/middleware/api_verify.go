@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"client-app/internal/consts"
+	"client-app/internal/library/contexts"
+	"client-app/internal/library/response"
+	"client-app/utility/signature"
+	"client-app/utility/simple"
+	"client-app/utility/validate"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/os/gcache"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+// verifyMerchant 签名商户配置
+type verifyMerchant struct {
+	MerchantId string `json:"merchantId"`
+	Secret     string `json:"secret"`
+	Disabled   bool   `json:"disabled"`
+}
+
+// ApiVerify API请求签名验证中间件，用于服务端之间的调用
+// 签名内容见 signature.Request.Canonical，密钥按商户配置在 apiVerify.merchants 中
+func (s *sMiddleware) ApiVerify(r *ghttp.Request) {
+	var (
+		ctx  = r.Context()
+		path = gstr.Replace(r.URL.Path, simple.RouterPrefix(ctx, consts.AppApi), "", 1)
+	)
+
+	if !g.Cfg().MustGet(ctx, "apiVerify.enabled").Bool() || s.isExceptVerify(ctx, path) {
+		r.Middleware.Next()
+		return
+	}
+
+	var (
+		merchantId = r.Header.Get(consts.HeaderMerchantId)
+		timestamp  = r.Header.Get(consts.HeaderTimestamp)
+		nonce      = r.Header.Get(consts.HeaderNonce)
+		sign       = r.Header.Get(consts.HeaderSignature)
+	)
+	if merchantId == "" || timestamp == "" || nonce == "" || sign == "" {
+		s.verifyFailed(r, consts.CodeSignMissing, "缺少签名参数")
+		return
+	}
+
+	merchant := s.getVerifyMerchant(ctx, merchantId)
+	if merchant == nil {
+		s.verifyFailed(r, consts.CodeSignMerchant, "商户不存在或已停用")
+		return
+	}
+
+	skew := g.Cfg().MustGet(ctx, "apiVerify.skew", "5m").Duration()
+	req := &signature.Request{
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		Query:     r.URL.Query(),
+		Body:      r.GetBody(),
+		Timestamp: timestamp,
+		Nonce:     nonce,
+	}
+	if err := req.Verify(merchant.Secret, sign, skew, time.Now()); err != nil {
+		switch {
+		case errors.Is(err, signature.ErrTimestampInvalid), errors.Is(err, signature.ErrTimestampSkew):
+			s.verifyFailed(r, consts.CodeSignTimestamp, err.Error())
+		case errors.Is(err, signature.ErrNonceInvalid):
+			s.verifyFailed(r, consts.CodeSignNonce, err.Error())
+		default:
+			s.LogSecurity(ctx, "API_SIGNATURE_INVALID", "medium", "请求签名验证失败", merchantId, r.URL.Path)
+			s.verifyFailed(r, consts.CodeSignInvalid, err.Error())
+		}
+		return
+	}
+
+	// 签名通过后再记录nonce，避免伪造请求占用nonce；缓存时长覆盖时间偏差的前后范围
+	nonceKey := fmt.Sprintf("api_verify_nonce_%s_%s", merchantId, nonce)
+	if ok, _ := gcache.SetIfNotExist(ctx, nonceKey, 1, 2*skew); !ok {
+		s.LogSecurity(ctx, "API_SIGNATURE_REPLAY", "high", "签名请求被重放", merchantId, nonce, r.URL.Path)
+		s.verifyFailed(r, consts.CodeSignReplay, "请求已被处理，请勿重复提交")
+		return
+	}
+
+	contexts.SetDataMap(ctx, g.Map{"merchantId": merchantId})
+	r.Middleware.Next()
+}
+
+// getVerifyMerchant 获取签名商户，未配置 apiVerify.merchants 时使用 server.merchantId 和 server.ApiKey
+func (s *sMiddleware) getVerifyMerchant(ctx context.Context, merchantId string) *verifyMerchant {
+	var merchants []*verifyMerchant
+	if err := g.Cfg().MustGet(ctx, "apiVerify.merchants").Scan(&merchants); err != nil {
+		g.Log().Warningf(ctx, "读取签名商户配置失败: %v", err)
+		return nil
+	}
+	if len(merchants) == 0 {
+		merchants = append(merchants, &verifyMerchant{
+			MerchantId: g.Cfg().MustGet(ctx, "server.merchantId").String(),
+			Secret:     g.Cfg().MustGet(ctx, "server.ApiKey").String(),
+		})
+	}
+
+	for _, merchant := range merchants {
+		if merchant.MerchantId == merchantId && !merchant.Disabled && merchant.Secret != "" {
+			return merchant
+		}
+	}
+	return nil
+}
+
+// isExceptVerify 是否是不需要签名验证的路由地址
+func (s *sMiddleware) isExceptVerify(ctx context.Context, path string) bool {
+	pathList := g.Cfg().MustGet(ctx, "apiVerify.exceptPaths").Strings()
+	for i := 0; i < len(pathList); i++ {
+		if validate.InSliceExistStr(pathList[i], path) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyFailed 签名验证失败处理
+func (s *sMiddleware) verifyFailed(r *ghttp.Request, code int, message string) {
+	g.Log().Warningf(r.Context(), "API签名验证失败: %s, Path: %s, IP: %s, Merchant: %s",
+		message, r.URL.Path, r.GetClientIp(), r.Header.Get(consts.HeaderMerchantId))
+
+	response.JsonExit(r, code, message)
+}
@@ -353,6 +353,10 @@ func (s *sMiddleware) LogAudit(ctx context.Context, action string, resource stri
 	if len(details) > 0 {
 		message += fmt.Sprintf(" 详情: %v", details)
 	}
+	// 模拟登录期间的操作记录实际操作人
+	if identity := s.GetCurrentUser(ctx); identity != nil && identity.IsImpersonated() {
+		message += fmt.Sprintf(" 模拟登录: %s(%d) -> %s(%d)", identity.Impersonator.Username, identity.Impersonator.Id, identity.Username, identity.Id)
+	}
 
 	logger.Info(ctx, message)
 }
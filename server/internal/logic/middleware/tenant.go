@@ -38,7 +38,7 @@ func (s *sMiddleware) TenantFilter(r *ghttp.Request) {
 
 		// 根据域名查询租户
 		var tenant *entity.Tenant
-		err = g.DB().Model("tenants").Where("domain", host).Where("deleted_at IS NULL").Scan(&tenant)
+		err = g.DB().Model("sys_tenants").Where("domain", host).Where("deleted_at IS NULL").Scan(&tenant)
 		if err == nil && tenant != nil {
 			tenantId = tenant.Id
 		}
@@ -64,13 +64,16 @@ func (s *sMiddleware) TenantFilter(r *ghttp.Request) {
 		return
 	}
 
-	// 设置租户上下文
-	customCtx := &model.Context{
-		Data: g.Map{
-			"tenantId": tenantId,
-		},
+	// 设置租户上下文，已由 Ctx 中间件初始化时只追加数据，避免覆盖请求上下文
+	if customCtx := contexts.Get(ctx); customCtx != nil && customCtx.Data != nil {
+		contexts.SetDataMap(ctx, g.Map{"tenantId": tenantId})
+	} else {
+		contexts.Init(r, &model.Context{
+			Data: g.Map{
+				"tenantId": tenantId,
+			},
+		})
 	}
-	contexts.Init(r, customCtx)
 
 	r.Middleware.Next()
 }
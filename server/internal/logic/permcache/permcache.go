@@ -0,0 +1,264 @@
+package permcache
+
+import (
+	"client-app/internal/model"
+	"client-app/internal/model/entity"
+	"client-app/internal/service"
+	"client-app/utility/simple"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcache"
+)
+
+// snapshotStore 权限快照存储
+type snapshotStore interface {
+	// get 获取快照，不存在或已过期返回nil
+	get(ctx context.Context, userId int64) (*model.PermSnapshot, error)
+	// save 保存快照
+	save(ctx context.Context, snapshot *model.PermSnapshot, ttl time.Duration) error
+	// remove 清除快照
+	remove(ctx context.Context, userIds []int64) error
+}
+
+type sPermCache struct {
+	once     sync.Once
+	store    snapshotStore
+	versions *gcache.Cache // 用户当前权限版本，在本节点短时缓存
+}
+
+func init() {
+	service.RegisterPermCache(NewPermCache())
+}
+
+func NewPermCache() *sPermCache {
+	return &sPermCache{versions: gcache.New()}
+}
+
+// getStore 根据配置选择存储，未配置时集群部署使用db，单机使用memory
+func (s *sPermCache) getStore(ctx context.Context) snapshotStore {
+	s.once.Do(func() {
+		driver := g.Cfg().MustGet(ctx, "permission.cache.store").String()
+		if driver == "" {
+			driver = "memory"
+			if simple.IsCluster(ctx) {
+				driver = "db"
+			}
+		}
+		switch driver {
+		case "db":
+			s.store = newDBStore()
+		default:
+			if simple.IsCluster(ctx) {
+				g.Log().Warning(ctx, "集群部署下权限快照使用memory存储，其他节点修改的权限在 permission.cache.versionCheck 间隔后才会生效")
+			}
+			s.store = newMemoryStore()
+		}
+	})
+	return s.store
+}
+
+// Snapshot 获取用户权限快照，缓存的快照版本低于 minVersion 或数据库中的当前权限版本时视为过期并重新加载
+// minVersion 通常取访问令牌中的 permVersion 声明，令牌签发后权限有变化时可据此发现其他节点的过期快照
+func (s *sPermCache) Snapshot(ctx context.Context, userId int64, minVersion int64) (*model.PermSnapshot, error) {
+	store := s.getStore(ctx)
+	snapshot, err := store.get(ctx, userId)
+	if err != nil {
+		g.Log().Warningf(ctx, "读取用户[%d]权限快照失败: %v", userId, err)
+	}
+	if snapshot != nil && snapshot.Version >= minVersion && s.isCurrent(ctx, snapshot) {
+		return snapshot, nil
+	}
+
+	if snapshot, err = s.load(ctx, userId); err != nil {
+		return nil, err
+	}
+	expire := g.Cfg().MustGet(ctx, "permission.cache.expire", "5m").Duration()
+	if err = store.save(ctx, snapshot, expire); err != nil {
+		g.Log().Warningf(ctx, "保存用户[%d]权限快照失败: %v", userId, err)
+	}
+	return snapshot, nil
+}
+
+// Version 获取用户当前的权限版本
+func (s *sPermCache) Version(ctx context.Context, userId int64) (int64, error) {
+	version, err := g.DB().Model("sys_users").Ctx(ctx).Where("id = ?", userId).Value("perm_version")
+	if err != nil {
+		return 0, gerror.Wrap(err, "查询用户权限版本失败")
+	}
+	return version.Int64(), nil
+}
+
+// isCurrent 快照版本是否不低于数据库中的当前权限版本
+// 其他节点失效权限时清除不到本节点的内存快照，按当前版本比较可在版本缓存到期后发现
+func (s *sPermCache) isCurrent(ctx context.Context, snapshot *model.PermSnapshot) bool {
+	userId := snapshot.User.Id
+	interval := g.Cfg().MustGet(ctx, "permission.cache.versionCheck", "5s").Duration()
+	if interval <= 0 {
+		version, err := s.Version(ctx, userId)
+		if err != nil {
+			g.Log().Warningf(ctx, "查询用户[%d]权限版本失败: %v", userId, err)
+			return false
+		}
+		return snapshot.Version >= version
+	}
+
+	version, err := s.versions.GetOrSetFuncLock(ctx, userId, func(ctx context.Context) (interface{}, error) {
+		return s.Version(ctx, userId)
+	}, interval)
+	if err != nil {
+		g.Log().Warningf(ctx, "查询用户[%d]权限版本失败: %v", userId, err)
+		return false
+	}
+	return snapshot.Version >= version.Int64()
+}
+
+// InvalidateUsers 递增用户权限版本并清除快照，此后签发的令牌携带新版本
+// 在事务中修改用户权限时需在事务提交后调用，否则提交前的请求可能重新加载到旧权限
+func (s *sPermCache) InvalidateUsers(ctx context.Context, userIds ...int64) error {
+	userIds = uniqueIds(userIds)
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	_, err := g.DB().Model("sys_users").Ctx(ctx).Where("id IN(?)", userIds).Update(g.Map{
+		"perm_version": gdb.Raw("perm_version + 1"),
+	})
+	if err != nil {
+		return gerror.Wrap(err, "更新用户权限版本失败")
+	}
+
+	keys := make([]interface{}, 0, len(userIds))
+	for _, userId := range userIds {
+		keys = append(keys, userId)
+	}
+	if _, err = s.versions.Remove(ctx, keys...); err != nil {
+		return gerror.Wrap(err, "清除用户权限版本缓存失败")
+	}
+	if err = s.getStore(ctx).remove(ctx, userIds); err != nil {
+		return gerror.Wrap(err, "清除用户权限快照失败")
+	}
+	return nil
+}
+
+// InvalidateRoles 失效拥有这些角色或其下级角色的用户
+func (s *sPermCache) InvalidateRoles(ctx context.Context, roleIds ...int64) error {
+	roleIds = uniqueIds(roleIds)
+	if len(roleIds) == 0 {
+		return nil
+	}
+
+	// 下级角色继承这些角色的权限
+	m := g.DB().Model("sys_roles").Ctx(ctx).Fields("id").Where("id IN(?)", roleIds)
+	for _, roleId := range roleIds {
+		m = m.WhereOr("FIND_IN_SET(?, ancestors)", roleId)
+	}
+	chainIds, err := m.Array()
+	if err != nil {
+		return gerror.Wrap(err, "查询下级角色失败")
+	}
+	if len(chainIds) == 0 {
+		return nil
+	}
+
+	userIds, err := g.DB().Model("sys_user_roles").Ctx(ctx).Fields("user_id").Where("role_id IN(?)", toIds(chainIds)).Distinct().Array()
+	if err != nil {
+		return gerror.Wrap(err, "查询角色用户失败")
+	}
+	return s.InvalidateUsers(ctx, toIds(userIds)...)
+}
+
+// InvalidateTenant 失效租户下的所有用户，包括已删除的用户
+func (s *sPermCache) InvalidateTenant(ctx context.Context, tenantId uint64) error {
+	userIds, err := g.DB().Model("sys_users").Ctx(ctx).Fields("id").Where("tenant_id = ?", tenantId).Array()
+	if err != nil {
+		return gerror.Wrap(err, "查询租户用户失败")
+	}
+	return s.InvalidateUsers(ctx, toIds(userIds)...)
+}
+
+// InvalidateMenus 失效可访问这些菜单的用户，包括通过角色继承访问的用户
+func (s *sPermCache) InvalidateMenus(ctx context.Context, menuIds ...int64) error {
+	menuIds = uniqueIds(menuIds)
+	if len(menuIds) == 0 {
+		return nil
+	}
+
+	roleIds, err := g.DB().Model("sys_role_menus").Ctx(ctx).Fields("role_id").Where("menu_id IN(?)", menuIds).Distinct().Array()
+	if err != nil {
+		return gerror.Wrap(err, "查询菜单角色失败")
+	}
+	return s.InvalidateRoles(ctx, toIds(roleIds)...)
+}
+
+// load 从数据库加载用户权限快照
+func (s *sPermCache) load(ctx context.Context, userId int64) (*model.PermSnapshot, error) {
+	record, err := g.DB().Model("sys_users").Where("id = ? AND deleted_at IS NULL", userId).One()
+	if err != nil {
+		return nil, gerror.Newf("查询用户信息失败: %v", err)
+	}
+	if record.IsEmpty() {
+		return nil, gerror.New("用户不存在或已被删除")
+	}
+	var user *entity.User
+	if err = record.Struct(&user); err != nil {
+		return nil, gerror.Newf("解析用户信息失败: %v", err)
+	}
+
+	permissions, err := service.Role().GetUserPermissions(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &model.PermSnapshot{
+		Version:     user.PermVersion,
+		User:        user,
+		Permissions: permissions,
+	}
+
+	// 部门类型和上级部门ID链用于数据权限，随快照缓存，部门调整时失效
+	if user.DeptId > 0 {
+		dept, err := service.Dept().GetDept(ctx, record["tenant_id"].Int64(), user.DeptId)
+		if err != nil {
+			return nil, err
+		}
+		if dept != nil {
+			snapshot.DeptType = dept.Type
+			snapshot.DeptTree = dept.AncestorIds()
+		}
+	}
+
+	// 快照可能存入共享存储，不保留密码等敏感字段
+	user.Password = ""
+	user.Salt = ""
+	user.TwoFactorSecret = ""
+	user.PasswordResetToken = ""
+	user.EmailVerifyToken = ""
+	return snapshot, nil
+}
+
+// uniqueIds 去除无效和重复的ID
+func uniqueIds(ids []int64) []int64 {
+	seen := make(map[int64]struct{}, len(ids))
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; id > 0 && !ok {
+			seen[id] = struct{}{}
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// toIds 将查询结果转换为ID列表
+func toIds(values []*g.Var) []int64 {
+	ids := make([]int64, 0, len(values))
+	for _, v := range values {
+		ids = append(ids, v.Int64())
+	}
+	return ids
+}
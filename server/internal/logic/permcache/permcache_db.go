@@ -0,0 +1,68 @@
+package permcache
+
+import (
+	"client-app/internal/model"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// dbStore 数据库存储，集群节点共享快照，任一节点失效后所有节点立即生效
+// 每次鉴权只需按主键读取一行，代替查询用户和关联查询权限
+type dbStore struct{}
+
+func newDBStore() *dbStore {
+	return &dbStore{}
+}
+
+func (d *dbStore) get(ctx context.Context, userId int64) (*model.PermSnapshot, error) {
+	val, err := g.DB().Model("sys_perm_snapshots").
+		Where("user_id = ? AND expires_at > ?", userId, gtime.Now()).
+		Value("data")
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询权限快照失败")
+	}
+	if val.IsEmpty() {
+		return nil, nil
+	}
+
+	var snapshot *model.PermSnapshot
+	if err = json.Unmarshal(val.Bytes(), &snapshot); err != nil {
+		return nil, gerror.Wrap(err, "解析权限快照失败")
+	}
+	if snapshot == nil || snapshot.User == nil {
+		return nil, nil
+	}
+	return snapshot, nil
+}
+
+func (d *dbStore) save(ctx context.Context, snapshot *model.PermSnapshot, ttl time.Duration) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return gerror.Wrap(err, "序列化权限快照失败")
+	}
+
+	_, err = g.DB().Model("sys_perm_snapshots").Data(g.Map{
+		"user_id":    snapshot.User.Id,
+		"version":    snapshot.Version,
+		"data":       string(data),
+		"expires_at": gtime.Now().Add(ttl),
+		"updated_at": gtime.Now(),
+	}).OnConflict("user_id").Save()
+	if err != nil {
+		return gerror.Wrap(err, "保存权限快照失败")
+	}
+
+	// 顺带清理已过期的记录
+	_, _ = g.DB().Model("sys_perm_snapshots").Where("expires_at < ?", gtime.Now()).Limit(100).Delete()
+	return nil
+}
+
+func (d *dbStore) remove(ctx context.Context, userIds []int64) error {
+	_, err := g.DB().Model("sys_perm_snapshots").Where("user_id IN(?)", userIds).Delete()
+	return err
+}
@@ -0,0 +1,45 @@
+package permcache
+
+import (
+	"client-app/internal/model"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gogf/gf/v2/os/gcache"
+)
+
+// memoryStore 进程内存储，仅适用于单机部署
+type memoryStore struct {
+	cache *gcache.Cache
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{cache: gcache.New()}
+}
+
+func (m *memoryStore) get(ctx context.Context, userId int64) (*model.PermSnapshot, error) {
+	val, err := m.cache.Get(ctx, m.key(userId))
+	if err != nil || val.IsNil() {
+		return nil, err
+	}
+	snapshot, _ := val.Val().(*model.PermSnapshot)
+	return snapshot, nil
+}
+
+func (m *memoryStore) save(ctx context.Context, snapshot *model.PermSnapshot, ttl time.Duration) error {
+	return m.cache.Set(ctx, m.key(snapshot.User.Id), snapshot, ttl)
+}
+
+func (m *memoryStore) remove(ctx context.Context, userIds []int64) error {
+	keys := make([]interface{}, 0, len(userIds))
+	for _, userId := range userIds {
+		keys = append(keys, m.key(userId))
+	}
+	_, err := m.cache.Remove(ctx, keys...)
+	return err
+}
+
+func (m *memoryStore) key(userId int64) string {
+	return strconv.FormatInt(userId, 10)
+}
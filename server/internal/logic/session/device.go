@@ -0,0 +1,60 @@
+package session
+
+import (
+	"strings"
+)
+
+// 按顺序匹配，先匹配的优先，如 Edge 的用户代理同时包含 Chrome
+var (
+	uaBrowsers = [][2]string{
+		{"MicroMessenger", "微信"},
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"okhttp", "Android App"},
+		{"curl/", "curl"},
+		{"PostmanRuntime", "Postman"},
+	}
+	uaPlatforms = [][2]string{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// parseDevice 从用户代理解析设备描述，如 "Chrome / Windows"
+func parseDevice(userAgent string) string {
+	if userAgent == "" {
+		return "未知设备"
+	}
+
+	var browser, platform string
+	for _, item := range uaBrowsers {
+		if strings.Contains(userAgent, item[0]) {
+			browser = item[1]
+			break
+		}
+	}
+	for _, item := range uaPlatforms {
+		if strings.Contains(userAgent, item[0]) {
+			platform = item[1]
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " / " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	default:
+		return "未知设备"
+	}
+}
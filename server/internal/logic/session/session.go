@@ -0,0 +1,182 @@
+package session
+
+import (
+	"client-app/internal/model/entity"
+	"client-app/internal/service"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcache"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+type sSession struct{}
+
+func init() {
+	service.RegisterSession(NewSession())
+}
+
+func NewSession() *sSession {
+	return &sSession{}
+}
+
+// Create 创建登录会话，记录当前请求的设备、IP和用户代理，返回会话ID
+func (s *sSession) Create(ctx context.Context, userId int64, tenantId uint64) (sessionId string, err error) {
+	buf := make([]byte, 16)
+	if _, err = rand.Read(buf); err != nil {
+		return "", gerror.Wrap(err, "生成会话ID失败")
+	}
+	sessionId = hex.EncodeToString(buf)
+
+	var clientIp, userAgent, device string
+	if r := g.RequestFromCtx(ctx); r != nil {
+		clientIp = r.GetClientIp()
+		userAgent = truncate(r.Header.Get("User-Agent"), 512)
+		// 客户端可通过 X-Device-Name 自报设备名称，否则从用户代理解析
+		device = strings.TrimSpace(r.Header.Get("X-Device-Name"))
+		if device == "" {
+			device = parseDevice(userAgent)
+		}
+		device = truncate(device, 64)
+	}
+
+	now := gtime.Now()
+	_, err = g.DB().Model("sys_user_sessions").Data(g.Map{
+		"session_id":   sessionId,
+		"user_id":      userId,
+		"tenant_id":    tenantId,
+		"device":       device,
+		"ip":           clientIp,
+		"user_agent":   userAgent,
+		"last_seen_at": now,
+		"last_seen_ip": clientIp,
+		"expires_at":   now.Add(g.Cfg().MustGet(ctx, "jwt.refreshExpire", "168h").Duration()),
+		"created_at":   now,
+	}).Insert()
+	if err != nil {
+		return "", gerror.Wrap(err, "保存登录会话失败")
+	}
+	return sessionId, nil
+}
+
+// Renew 刷新令牌轮换时延长会话有效期
+func (s *sSession) Renew(ctx context.Context, sessionId string) error {
+	data := g.Map{
+		"last_seen_at": gtime.Now(),
+		"expires_at":   gtime.Now().Add(g.Cfg().MustGet(ctx, "jwt.refreshExpire", "168h").Duration()),
+	}
+	if r := g.RequestFromCtx(ctx); r != nil {
+		data["last_seen_ip"] = r.GetClientIp()
+	}
+
+	_, err := g.DB().Model("sys_user_sessions").
+		Where("session_id = ? AND revoked_at IS NULL", sessionId).
+		Update(data)
+	if err != nil {
+		return gerror.Wrap(err, "更新登录会话失败")
+	}
+	return nil
+}
+
+// Touch 更新会话最后活跃时间，同一会话在节流间隔内只写一次
+func (s *sSession) Touch(ctx context.Context, sessionId string) error {
+	if sessionId == "" {
+		return nil
+	}
+
+	interval := g.Cfg().MustGet(ctx, "session.touchInterval", "1m").Duration()
+	if ok, _ := gcache.SetIfNotExist(ctx, fmt.Sprintf("session_touch_%s", sessionId), 1, interval); !ok {
+		return nil
+	}
+
+	data := g.Map{"last_seen_at": gtime.Now()}
+	if r := g.RequestFromCtx(ctx); r != nil {
+		data["last_seen_ip"] = r.GetClientIp()
+	}
+
+	_, err := g.DB().Model("sys_user_sessions").
+		Where("session_id = ? AND revoked_at IS NULL", sessionId).
+		Update(data)
+	if err != nil {
+		return gerror.Wrap(err, "更新会话活跃时间失败")
+	}
+	return nil
+}
+
+// Get 获取会话
+func (s *sSession) Get(ctx context.Context, sessionId string) (*entity.UserSession, error) {
+	var session *entity.UserSession
+	if err := g.DB().Model("sys_user_sessions").Where("session_id", sessionId).Scan(&session); err != nil {
+		return nil, gerror.Wrap(err, "查询登录会话失败")
+	}
+	return session, nil
+}
+
+// ListByUser 获取用户的有效会话，按最后活跃时间倒序
+func (s *sSession) ListByUser(ctx context.Context, userId int64) ([]*entity.UserSession, error) {
+	var sessions []*entity.UserSession
+	err := g.DB().Model("sys_user_sessions").
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userId, gtime.Now()).
+		OrderDesc("last_seen_at").
+		Scan(&sessions)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询登录会话失败")
+	}
+	return sessions, nil
+}
+
+// Revoke 撤销会话
+func (s *sSession) Revoke(ctx context.Context, sessionId, reason string) error {
+	_, err := g.DB().Model("sys_user_sessions").
+		Where("session_id = ? AND revoked_at IS NULL", sessionId).
+		Update(g.Map{
+			"revoked_at":    gtime.Now(),
+			"revoke_reason": reason,
+		})
+	if err != nil {
+		return gerror.Wrap(err, "撤销登录会话失败")
+	}
+	return nil
+}
+
+// RevokeByUser 撤销用户的全部会话
+func (s *sSession) RevokeByUser(ctx context.Context, userId int64, reason string) error {
+	_, err := g.DB().Model("sys_user_sessions").
+		Where("user_id = ? AND revoked_at IS NULL", userId).
+		Update(g.Map{
+			"revoked_at":    gtime.Now(),
+			"revoke_reason": reason,
+		})
+	if err != nil {
+		return gerror.Wrap(err, "撤销登录会话失败")
+	}
+	return nil
+}
+
+// RevokeByTenant 撤销租户下所有用户的会话
+func (s *sSession) RevokeByTenant(ctx context.Context, tenantId uint64, reason string) error {
+	_, err := g.DB().Model("sys_user_sessions").
+		Where("tenant_id = ? AND revoked_at IS NULL", tenantId).
+		Update(g.Map{
+			"revoked_at":    gtime.Now(),
+			"revoke_reason": reason,
+		})
+	if err != nil {
+		return gerror.Wrap(err, "撤销登录会话失败")
+	}
+	return nil
+}
+
+// truncate 按字符截断字符串
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,141 @@
+package token
+
+import (
+	"client-app/internal/service"
+	"client-app/utility/simple"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// 撤销记录类型
+const (
+	revokeTypeJti     = "jti"
+	revokeTypeSession = "session"
+	revokeTypeUser    = "user"
+	revokeTypeTenant  = "tenant"
+)
+
+// revocationStore 撤销记录存储
+type revocationStore interface {
+	// save 保存撤销记录，同类型同主体重复保存时覆盖撤销时间
+	save(ctx context.Context, kind, subject string, revokedAt, expireAt time.Time) error
+	// revokedAt 查询撤销时间，未撤销返回零值
+	revokedAt(ctx context.Context, kind, subject string) (time.Time, error)
+}
+
+type sTokenRevocation struct {
+	once  sync.Once
+	store revocationStore
+}
+
+func init() {
+	service.RegisterTokenRevocation(NewTokenRevocation())
+}
+
+func NewTokenRevocation() *sTokenRevocation {
+	return &sTokenRevocation{}
+}
+
+// getStore 根据配置选择存储，集群部署必须使用db
+func (s *sTokenRevocation) getStore(ctx context.Context) revocationStore {
+	s.once.Do(func() {
+		driver := g.Cfg().MustGet(ctx, "jwt.revocation.driver", "db").String()
+		switch driver {
+		case "memory":
+			if simple.IsCluster(ctx) {
+				g.Log().Warning(ctx, "集群部署下令牌撤销使用memory存储，撤销记录无法在节点间共享")
+			}
+			s.store = newMemoryStore()
+		default:
+			s.store = newDBStore()
+		}
+	})
+	return s.store
+}
+
+// accessTokenTTL 访问令牌最长有效期，用于确定用户/租户撤销记录的保留时间
+func accessTokenTTL(ctx context.Context) time.Duration {
+	return g.Cfg().MustGet(ctx, "jwt.expire", "24h").Duration() + g.Cfg().MustGet(ctx, "jwt.leeway", "30s").Duration()
+}
+
+// RevokeToken 撤销单个访问令牌
+func (s *sTokenRevocation) RevokeToken(ctx context.Context, jti string, exp int64) error {
+	if jti == "" {
+		return gerror.New("令牌缺少jti，无法撤销")
+	}
+	expireAt := time.Unix(exp, 0)
+	if exp <= 0 {
+		expireAt = time.Now().Add(accessTokenTTL(ctx))
+	}
+	return s.getStore(ctx).save(ctx, revokeTypeJti, jti, time.Now(), expireAt)
+}
+
+// RevokeSession 撤销会话在此之前签发的全部访问令牌
+func (s *sTokenRevocation) RevokeSession(ctx context.Context, sessionId string) error {
+	if sessionId == "" {
+		return nil
+	}
+	now := time.Now()
+	return s.getStore(ctx).save(ctx, revokeTypeSession, sessionId, now, now.Add(accessTokenTTL(ctx)))
+}
+
+// RevokeUser 撤销用户在此之前签发的全部访问令牌
+func (s *sTokenRevocation) RevokeUser(ctx context.Context, userId int64) error {
+	now := time.Now()
+	return s.getStore(ctx).save(ctx, revokeTypeUser, g.NewVar(userId).String(), now, now.Add(accessTokenTTL(ctx)))
+}
+
+// RevokeTenant 撤销租户下所有用户在此之前签发的全部访问令牌
+func (s *sTokenRevocation) RevokeTenant(ctx context.Context, tenantId int64) error {
+	now := time.Now()
+	return s.getStore(ctx).save(ctx, revokeTypeTenant, g.NewVar(tenantId).String(), now, now.Add(accessTokenTTL(ctx)))
+}
+
+// IsRevoked 判断访问令牌是否已被撤销
+func (s *sTokenRevocation) IsRevoked(ctx context.Context, payload *simple.JWTPayload) (bool, error) {
+	store := s.getStore(ctx)
+
+	if payload.Jti != "" {
+		at, err := store.revokedAt(ctx, revokeTypeJti, payload.Jti)
+		if err != nil {
+			return false, err
+		}
+		if !at.IsZero() {
+			return true, nil
+		}
+	}
+
+	// 会话、用户或租户批量撤销：撤销时间之前签发的令牌全部失效
+	// iat 只精确到秒，使用毫秒签发时间比较；旧令牌没有毫秒签发时间，同一秒内签发的按已撤销处理
+	issuedAt := time.Unix(payload.Iat, 0)
+	if payload.IatMs > 0 {
+		issuedAt = time.UnixMilli(payload.IatMs)
+	}
+	checks := [][2]string{
+		{revokeTypeUser, g.NewVar(payload.UserId).String()},
+	}
+	// 模拟登录令牌在操作人被强制下线时一并失效
+	if payload.Act != nil {
+		checks = append(checks, [2]string{revokeTypeUser, g.NewVar(payload.Act.UserId).String()})
+	}
+	if payload.Sid != "" {
+		checks = append(checks, [2]string{revokeTypeSession, payload.Sid})
+	}
+	if payload.TenantId > 0 {
+		checks = append(checks, [2]string{revokeTypeTenant, g.NewVar(payload.TenantId).String()})
+	}
+	for _, check := range checks {
+		at, err := store.revokedAt(ctx, check[0], check[1])
+		if err != nil {
+			return false, err
+		}
+		if !at.IsZero() && !issuedAt.After(at) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
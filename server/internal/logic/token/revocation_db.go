@@ -0,0 +1,63 @@
+package token
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// dbStore 数据库存储，集群节点共享撤销记录
+// 每次鉴权都会查询，查询结果在本地缓存一小段时间以降低数据库压力
+type dbStore struct {
+	cacheDuration time.Duration
+}
+
+func newDBStore() *dbStore {
+	return &dbStore{cacheDuration: 5 * time.Second}
+}
+
+func (d *dbStore) save(ctx context.Context, kind, subject string, revokedAt, expireAt time.Time) error {
+	// Duration 为负数时写入后清除本节点同名查询缓存，其他节点在缓存过期后生效
+	_, err := g.DB().Model("sys_token_revocations").Cache(gdb.CacheOption{
+		Duration: -1,
+		Name:     d.cacheName(kind, subject),
+	}).Data(g.Map{
+		"type":       kind,
+		"subject":    subject,
+		"revoked_at": gtime.New(revokedAt).Format("Y-m-d H:i:s.u"), // 保留毫秒，和令牌的毫秒签发时间比较
+		"expires_at": gtime.New(expireAt),
+		"created_at": gtime.Now(),
+	}).OnConflict("type", "subject").Save()
+	if err != nil {
+		return gerror.Wrap(err, "保存令牌撤销记录失败")
+	}
+
+	// 顺带清理已过期的记录
+	_, _ = g.DB().Model("sys_token_revocations").Where("expires_at < ?", gtime.Now()).Limit(100).Delete()
+	return nil
+}
+
+func (d *dbStore) revokedAt(ctx context.Context, kind, subject string) (time.Time, error) {
+	val, err := g.DB().Model("sys_token_revocations").
+		Cache(gdb.CacheOption{
+			Duration: d.cacheDuration,
+			Name:     d.cacheName(kind, subject),
+		}).
+		Where("type = ? AND subject = ? AND expires_at > ?", kind, subject, gtime.Now()).
+		Value("revoked_at")
+	if err != nil {
+		return time.Time{}, gerror.Wrap(err, "查询令牌撤销记录失败")
+	}
+	if val.IsEmpty() {
+		return time.Time{}, nil
+	}
+	return val.GTime().Time, nil
+}
+
+func (d *dbStore) cacheName(kind, subject string) string {
+	return "token_revocation:" + kind + ":" + subject
+}
@@ -0,0 +1,33 @@
+package token
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogf/gf/v2/os/gcache"
+)
+
+// memoryStore 进程内存储，仅适用于单机部署，重启后撤销记录丢失
+type memoryStore struct {
+	cache *gcache.Cache
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{cache: gcache.New()}
+}
+
+func (m *memoryStore) save(ctx context.Context, kind, subject string, revokedAt, expireAt time.Time) error {
+	ttl := time.Until(expireAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return m.cache.Set(ctx, kind+":"+subject, revokedAt.UnixMilli(), ttl)
+}
+
+func (m *memoryStore) revokedAt(ctx context.Context, kind, subject string) (time.Time, error) {
+	val, err := m.cache.Get(ctx, kind+":"+subject)
+	if err != nil || val.IsNil() {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(val.Int64()), nil
+}
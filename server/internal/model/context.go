@@ -1,6 +1,8 @@
 package model
 
 import (
+	"client-app/utility/permission"
+
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gtime"
 )
@@ -22,6 +24,7 @@ type Identity struct {
 	Pid        int64       `json:"pid"             description:"上级ID"`
 	DeptId     int64       `json:"deptId"          description:"部门ID"`
 	DeptType   string      `json:"deptType"        description:"部门类型"`
+	DeptTree   []int64     `json:"deptTree"        description:"上级部门ID链，从顶级部门到直属上级"`
 	RoleId     int64       `json:"roleId"          description:"角色ID"`
 	RoleKey    string      `json:"roleKey"         description:"角色唯一标识符"`
 	Username   string      `json:"username"        description:"用户名"`
@@ -30,7 +33,41 @@ type Identity struct {
 	Email      string      `json:"email"           description:"邮箱"`
 	Mobile     string      `json:"mobile"          description:"手机号码"`
 	App        string      `json:"app"             description:"登录应用"`
+	SessionId  string      `json:"sessionId"       description:"登录会话ID"`
+	AuthType   string      `json:"authType"        description:"认证方式"`
+	ApiKeyId   int64       `json:"apiKeyId"        description:"API密钥ID"`
+	Scopes     []string    `json:"scopes"          description:"API密钥权限范围"`
 	LoginAt    *gtime.Time `json:"loginAt"         description:"登录时间"`
+
+	Impersonator *Impersonator `json:"impersonator,omitempty" description:"模拟登录的实际操作人"`
+}
+
+// Impersonator 模拟登录的实际操作人，系统管理员以租户用户身份查看系统时记录
+type Impersonator struct {
+	Id       int64  `json:"id"       description:"操作人用户ID"`
+	TenantId int64  `json:"tenantId" description:"操作人租户ID"`
+	Username string `json:"username" description:"操作人用户名"`
+}
+
+// 认证方式
+const (
+	AuthTypeJWT    = "jwt"     // 登录令牌
+	AuthTypeApiKey = "api_key" // API密钥
+)
+
+// IsApiKey 判断是否通过API密钥认证
+func (i *Identity) IsApiKey() bool {
+	return i.AuthType == AuthTypeApiKey
+}
+
+// HasScope 判断API密钥是否包含指定权限，支持 role:* 等通配权限
+func (i *Identity) HasScope(required string) bool {
+	return permission.MatchAny(i.Scopes, required)
+}
+
+// IsImpersonated 判断是否为模拟登录
+func (i *Identity) IsImpersonated() bool {
+	return i.Impersonator != nil
 }
 
 // IsTenantAdmin 判断是否为租户管理员
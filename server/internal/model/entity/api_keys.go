@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// ApiKey 个人访问令牌，供脚本等机器客户端代替用户登录
+// 只保存密钥的SHA256哈希，权限范围是创建者权限的子集
+type ApiKey struct {
+	Id         int64       `json:"id"         description:"主键ID"`
+	TenantId   uint64      `json:"tenantId"   description:"租户ID"`
+	UserId     int64       `json:"userId"     description:"所属用户ID"`
+	Name       string      `json:"name"       description:"密钥名称"`
+	Prefix     string      `json:"prefix"     description:"密钥前缀，用于识别密钥"`
+	KeyHash    string      `json:"-"          description:"密钥哈希"`
+	Scopes     string      `json:"scopes"     description:"权限范围，多个以逗号分隔"`
+	AllowedIps string      `json:"allowedIps" description:"允许访问的IP或网段，多个以逗号分隔，为空不限制"`
+	ExpiresAt  *gtime.Time `json:"expiresAt"  description:"过期时间，为空表示不过期"`
+	LastUsedAt *gtime.Time `json:"lastUsedAt" description:"最后使用时间"`
+	LastUsedIp string      `json:"lastUsedIp" description:"最后使用IP"`
+	RevokedAt  *gtime.Time `json:"revokedAt"  description:"撤销时间"`
+	CreatedAt  *gtime.Time `json:"createdAt"  description:"创建时间"`
+}
+
+// IsActive 判断密钥是否有效
+func (k *ApiKey) IsActive() bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || k.ExpiresAt.After(gtime.Now())
+}
+
+// ScopeList 获取权限范围列表
+func (k *ApiKey) ScopeList() []string {
+	return splitList(k.Scopes)
+}
+
+// AllowedIpList 获取允许访问的IP列表
+func (k *ApiKey) AllowedIpList() []string {
+	return splitList(k.AllowedIps)
+}
+
+// AllowIp 判断是否允许从指定IP访问，支持单个IP和CIDR网段
+func (k *ApiKey) AllowIp(ip string) bool {
+	allowed := k.AllowedIpList()
+	if len(allowed) == 0 {
+		return true
+	}
+
+	clientIp := net.ParseIP(ip)
+	for _, item := range allowed {
+		if strings.Contains(item, "/") {
+			if _, network, err := net.ParseCIDR(item); err == nil && clientIp != nil && network.Contains(clientIp) {
+				return true
+			}
+			continue
+		}
+		if allowIp := net.ParseIP(item); allowIp != nil && allowIp.Equal(clientIp) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList 拆分逗号分隔的列表，忽略空项
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// Dept 部门实体
+// Ancestors 保存从顶级部门到直属上级的ID链，用于查询下级部门和数据权限
+type Dept struct {
+	Id        int64       `json:"id"        description:"主键ID"`
+	TenantId  int64       `json:"tenantId"  description:"租户ID"`
+	ParentId  int64       `json:"parentId"  description:"上级部门ID，0表示顶级部门"`
+	Ancestors string      `json:"ancestors" description:"上级部门ID链，逗号分隔"`
+	Name      string      `json:"name"      description:"部门名称"`
+	Code      string      `json:"code"      description:"部门编码"`
+	Type      string      `json:"type"      description:"部门类型"`
+	LeaderId  int64       `json:"leaderId"  description:"负责人用户ID"`
+	Phone     string      `json:"phone"     description:"联系电话"`
+	Email     string      `json:"email"     description:"联系邮箱"`
+	Sort      int         `json:"sort"      description:"排序号，数字越小越靠前"`
+	Status    int         `json:"status"    description:"状态：1=启用 0=禁用"`
+	Remark    string      `json:"remark"    description:"备注说明"`
+	CreatedBy int64       `json:"createdBy" description:"创建人ID"`
+	UpdatedBy int64       `json:"updatedBy" description:"修改人ID"`
+	CreatedAt *gtime.Time `json:"createdAt" description:"创建时间"`
+	UpdatedAt *gtime.Time `json:"updatedAt" description:"更新时间"`
+}
+
+// DeptType 部门类型常量
+const (
+	DeptTypeCompany = "company" // 公司
+	DeptTypeDept    = "dept"    // 部门
+	DeptTypeTeam    = "team"    // 小组
+)
+
+// DeptStatus 部门状态常量
+const (
+	DeptStatusDisabled = 0 // 禁用
+	DeptStatusEnabled  = 1 // 启用
+)
+
+// DeptMaxDepth 部门最大层级
+const DeptMaxDepth = 20
+
+// IsEnabled 判断部门是否启用
+func (d *Dept) IsEnabled() bool {
+	return d.Status == DeptStatusEnabled
+}
+
+// AncestorIds 获取上级部门ID链，从顶级部门到直属上级
+func (d *Dept) AncestorIds() []int64 {
+	return ParseDeptAncestors(d.Ancestors)
+}
+
+// Path 获取从顶级部门到当前部门的ID链
+func (d *Dept) Path() []int64 {
+	return append(d.AncestorIds(), d.Id)
+}
+
+// ChildAncestors 获取下级部门的上级部门ID链
+func (d *Dept) ChildAncestors() string {
+	return FormatDeptAncestors(d.Path())
+}
+
+// ParseDeptAncestors 解析逗号分隔的部门ID链
+func ParseDeptAncestors(ancestors string) []int64 {
+	var ids []int64
+	for _, item := range strings.Split(ancestors, ",") {
+		if id, err := strconv.ParseInt(strings.TrimSpace(item), 10, 64); err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// FormatDeptAncestors 将部门ID链格式化为逗号分隔的字符串
+func FormatDeptAncestors(ids []int64) string {
+	items := make([]string, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(items, ",")
+}
+
+// GetDeptTypeName 获取部门类型名称
+func GetDeptTypeName(deptType string) string {
+	switch deptType {
+	case DeptTypeCompany:
+		return "公司"
+	case DeptTypeDept:
+		return "部门"
+	case DeptTypeTeam:
+		return "小组"
+	default:
+		return "未知"
+	}
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "github.com/gogf/gf/v2/os/gtime"
+
+// 模拟登录结束原因
+const (
+	ImpersonationEndStop   = "stop"   // 操作人主动结束
+	ImpersonationEndLogout = "logout" // 退出登录
+)
+
+// Impersonation 模拟登录记录，系统管理员以租户用户身份登录时的审计记录
+type Impersonation struct {
+	Id                   int64       `json:"id"                   description:"主键ID"`
+	Jti                  string      `json:"jti"                  description:"模拟登录令牌ID"`
+	ImpersonatorId       int64       `json:"impersonatorId"       description:"操作人用户ID"`
+	ImpersonatorTenantId uint64      `json:"impersonatorTenantId" description:"操作人租户ID"`
+	UserId               int64       `json:"userId"               description:"目标用户ID"`
+	TenantId             uint64      `json:"tenantId"             description:"目标用户租户ID"`
+	Reason               string      `json:"reason"               description:"模拟登录原因"`
+	Ip                   string      `json:"ip"                   description:"操作IP"`
+	StartedAt            *gtime.Time `json:"startedAt"            description:"开始时间"`
+	ExpiresAt            *gtime.Time `json:"expiresAt"            description:"令牌过期时间"`
+	EndedAt              *gtime.Time `json:"endedAt"              description:"结束时间"`
+	EndReason            string      `json:"endReason"            description:"结束原因"`
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// 刷新令牌撤销原因
+const (
+	RefreshTokenRevokeReuse         = "reuse"          // 检测到已使用的令牌被重放
+	RefreshTokenRevokeLogout        = "logout"         // 用户退出
+	RefreshTokenRevokePasswordReset = "password_reset" // 重置密码
+	RefreshTokenRevokeAdmin         = "admin"          // 管理员强制下线
+	RefreshTokenRevokeUserDisabled  = "user_disabled"  // 用户被禁用、锁定或删除
+	RefreshTokenRevokeTenant        = "tenant"         // 租户被禁用、锁定或删除
+)
+
+// RefreshToken 刷新令牌实体
+// 同一次登录产生的令牌属于同一个令牌族(FamilyId)，每次刷新轮换出一个新令牌
+type RefreshToken struct {
+	Id           int64       `json:"id"           description:"主键ID"`
+	UserId       int64       `json:"userId"       description:"用户ID"`
+	TenantId     uint64      `json:"tenantId"     description:"租户ID"`
+	FamilyId     string      `json:"familyId"     description:"令牌族ID"`
+	ParentId     int64       `json:"parentId"     description:"轮换前的令牌ID"`
+	TokenHash    string      `json:"-"            description:"令牌哈希"`
+	ExpiresAt    *gtime.Time `json:"expiresAt"    description:"过期时间"`
+	UsedAt       *gtime.Time `json:"usedAt"       description:"轮换时间"`
+	RevokedAt    *gtime.Time `json:"revokedAt"    description:"撤销时间"`
+	RevokeReason string      `json:"revokeReason" description:"撤销原因"`
+	CreatedIp    string      `json:"createdIp"    description:"签发IP"`
+	CreatedAt    *gtime.Time `json:"createdAt"    description:"创建时间"`
+}
+
+// IsExpired 判断刷新令牌是否过期
+func (t *RefreshToken) IsExpired() bool {
+	return t.ExpiresAt == nil || t.ExpiresAt.Before(gtime.Now())
+}
+
+// IsUsed 判断刷新令牌是否已被轮换
+func (t *RefreshToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+// IsRevoked 判断刷新令牌是否已被撤销
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
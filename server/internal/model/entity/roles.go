@@ -5,8 +5,11 @@ import (
 )
 
 // Role 角色实体
+// 下级角色继承上级角色的菜单权限，Ancestors 保存从顶级角色到直属上级的ID链
 type Role struct {
 	Id          int64       `json:"id"          description:"主键ID"`
+	ParentId    int64       `json:"parentId"    description:"上级角色ID，0表示不继承"`
+	Ancestors   string      `json:"ancestors"   description:"上级角色ID链，逗号分隔"`
 	Name        string      `json:"name"        description:"角色名称"`
 	Code        string      `json:"code"        description:"角色编码"`
 	Description string      `json:"description" description:"角色描述"`
@@ -28,6 +31,15 @@ type RoleMenu struct {
 	CreatedAt *gtime.Time `json:"createdAt" description:"创建时间"`
 }
 
+// RoleDept 角色部门关联实体，用于自定义数据权限
+type RoleDept struct {
+	Id        int64       `json:"id"        description:"主键ID"`
+	TenantId  int64       `json:"tenantId"  description:"租户ID"`
+	RoleId    int64       `json:"roleId"    description:"角色ID"`
+	DeptId    int64       `json:"deptId"    description:"部门ID"`
+	CreatedAt *gtime.Time `json:"createdAt" description:"创建时间"`
+}
+
 // RoleStatus 角色状态常量
 const (
 	RoleStatusDisabled = 0 // 禁用
@@ -44,6 +56,9 @@ const (
 	DataScopeCustom     = 5 // 自定义数据权限
 )
 
+// RoleMaxDepth 角色继承最大层级
+const RoleMaxDepth = 5
+
 // RoleCode 角色编码常量
 const (
 	RoleCodeFinanceAdmin    = "finance_admin"    // 财务管理员
@@ -142,6 +157,16 @@ func (r *Role) CanOnlyAccessSelfData() bool {
 	return r.DataScope == DataScopeSelf
 }
 
+// AncestorIds 获取上级角色ID链，从顶级角色到直属上级，格式与部门ID链相同
+func (r *Role) AncestorIds() []int64 {
+	return ParseDeptAncestors(r.Ancestors)
+}
+
+// Path 获取从顶级角色到当前角色的ID链
+func (r *Role) Path() []int64 {
+	return append(r.AncestorIds(), r.Id)
+}
+
 // RoleWithMenus 带菜单权限的角色
 type RoleWithMenus struct {
 	Role
@@ -168,6 +193,27 @@ func ValidateDataScope(scope int) bool {
 	return scope >= DataScopeAll && scope <= DataScopeCustom
 }
 
+// dataScopeRanks 数据权限范围从小到大的顺序
+var dataScopeRanks = map[int]int{
+	DataScopeSelf:       1,
+	DataScopeCustom:     2,
+	DataScopeDept:       3,
+	DataScopeDeptAndSub: 4,
+	DataScopeAll:        5,
+}
+
+// WidestDataScope 获取范围最大的数据权限，用于计算角色继承链的数据权限
+// 自定义数据权限的部门不固定，视为小于部门数据
+func WidestDataScope(scopes ...int) int {
+	widest := DataScopeSelf
+	for _, scope := range scopes {
+		if dataScopeRanks[scope] > dataScopeRanks[widest] {
+			widest = scope
+		}
+	}
+	return widest
+}
+
 // GetAllDataScopes 获取所有数据权限范围选项
 func GetAllDataScopes() map[int]string {
 	return map[int]string{
@@ -5,7 +5,10 @@
 package entity
 
 import (
+	"encoding/json"
+
 	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
 )
 
 // Tenant is the golang structure for table tenants.
@@ -35,6 +38,92 @@ type TenantConfig struct {
 	Settings    map[string]any  `json:"settings"`    // 自定义设置
 }
 
+// 自助注册模式
+const (
+	RegisterModeDisabled = "disabled" // 关闭注册
+	RegisterModeOpen     = "open"     // 开放注册
+	RegisterModeInvite   = "invite"   // 仅限邀请码注册
+)
+
+// RegistrationPolicy 自助注册策略，保存在租户配置 settings.registration 中
+type RegistrationPolicy struct {
+	Mode              string `json:"mode"`              // 注册模式，未配置时关闭注册
+	DefaultRole       string `json:"defaultRole"`       // 新用户默认角色编码
+	EmailVerification bool   `json:"emailVerification"` // 注册后是否发送邮箱验证链接
+}
+
+// RegistrationPolicy 获取自助注册策略
+func (c *TenantConfig) RegistrationPolicy() *RegistrationPolicy {
+	policy := &RegistrationPolicy{}
+	if c != nil && c.Settings != nil {
+		_ = gconv.Struct(c.Settings["registration"], policy)
+	}
+	if policy.Mode == "" {
+		policy.Mode = RegisterModeDisabled
+	}
+	if policy.DefaultRole == "" {
+		policy.DefaultRole = RoleCodeNormalUser
+	}
+	return policy
+}
+
+// CaptchaType 获取租户使用的验证码类型，保存在租户配置 settings.captcha.type 中，未配置返回空
+func (c *TenantConfig) CaptchaType() string {
+	if c == nil || c.Settings == nil {
+		return ""
+	}
+	settings, ok := c.Settings["captcha"].(map[string]any)
+	if !ok {
+		return ""
+	}
+	return gconv.String(settings["type"])
+}
+
+// PasswordPolicy 获取租户密码策略，保存在租户配置 settings.passwordPolicy 中
+// 返回原始配置，由调用方覆盖到系统默认策略上，未配置返回nil
+func (c *TenantConfig) PasswordPolicy() map[string]any {
+	if c == nil || c.Settings == nil {
+		return nil
+	}
+	policy, _ := c.Settings["passwordPolicy"].(map[string]any)
+	return policy
+}
+
+// OidcProvider 单点登录身份提供方配置，保存在租户配置 settings.oidc 中
+type OidcProvider struct {
+	Enabled       bool     `json:"enabled"`       // 是否启用
+	Issuer        string   `json:"issuer"`        // 身份提供方地址
+	ClientId      string   `json:"clientId"`      // 客户端ID
+	ClientSecret  string   `json:"clientSecret"`  // 客户端密钥
+	RedirectUrl   string   `json:"redirectUrl"`   // 回调地址
+	Scopes        []string `json:"scopes"`        // 申请的权限范围
+	JitProvision  bool     `json:"jitProvision"`  // 首次登录时是否自动创建用户
+	DefaultRole   string   `json:"defaultRole"`   // 自动创建用户的默认角色编码
+	UsernameClaim string   `json:"usernameClaim"` // 作为用户名的声明，默认 preferred_username
+	LinkByEmail   bool     `json:"linkByEmail"`   // 是否按已验证邮箱关联已有用户
+}
+
+// OidcProvider 获取单点登录配置，未启用时返回nil
+func (c *TenantConfig) OidcProvider() *OidcProvider {
+	if c == nil || c.Settings == nil || c.Settings["oidc"] == nil {
+		return nil
+	}
+	provider := &OidcProvider{}
+	if err := gconv.Struct(c.Settings["oidc"], provider); err != nil || !provider.Enabled {
+		return nil
+	}
+	if len(provider.Scopes) == 0 {
+		provider.Scopes = []string{"openid", "profile", "email"}
+	}
+	if provider.DefaultRole == "" {
+		provider.DefaultRole = RoleCodeNormalUser
+	}
+	if provider.UsernameClaim == "" {
+		provider.UsernameClaim = "preferred_username"
+	}
+	return provider
+}
+
 // TenantStats 租户统计信息
 type TenantStats struct {
 	UserCount      int `json:"userCount"`      // 用户数量
@@ -74,6 +163,18 @@ func (t *Tenant) IsExpired() bool {
 	return gtime.Now().After(t.ExpireAt)
 }
 
+// ParseConfig 解析租户配置
+func (t *Tenant) ParseConfig() (*TenantConfig, error) {
+	config := &TenantConfig{}
+	if t.Config == "" {
+		return config, nil
+	}
+	if err := json.Unmarshal([]byte(t.Config), config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // IsSystemTenant 判断是否为系统租户
 func (t *Tenant) IsSystemTenant() bool {
 	return t.Code == "system"
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// UserPasskey 用户通行密钥（WebAuthn凭证），一个用户可注册多个
+// 只保存凭证公钥，签名计数器用于发现被复制的认证器
+type UserPasskey struct {
+	Id           int64       `json:"id"           description:"主键ID"`
+	TenantId     uint64      `json:"tenantId"     description:"租户ID"`
+	UserId       int64       `json:"userId"       description:"所属用户ID"`
+	Name         string      `json:"name"         description:"通行密钥名称"`
+	CredentialId string      `json:"credentialId" description:"凭证ID（base64url）"`
+	PublicKey    string      `json:"-"            description:"COSE格式公钥（base64url）"`
+	Alg          int64       `json:"alg"          description:"签名算法"`
+	SignCount    uint32      `json:"signCount"    description:"签名计数器"`
+	Aaguid       string      `json:"aaguid"       description:"认证器型号标识"`
+	Transports   string      `json:"transports"   description:"传输方式，多个以逗号分隔"`
+	BackupState  int         `json:"backupState"  description:"是否已同步备份"`
+	LastUsedAt   *gtime.Time `json:"lastUsedAt"   description:"最后使用时间"`
+	LastUsedIp   string      `json:"lastUsedIp"   description:"最后使用IP"`
+	CreatedAt    *gtime.Time `json:"createdAt"    description:"创建时间"`
+}
+
+// TransportList 获取传输方式列表
+func (p *UserPasskey) TransportList() []string {
+	return splitList(p.Transports)
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// UserSession 用户登录会话
+// 每次登录创建一个会话，会话ID即刷新令牌族ID，访问令牌通过 sid 声明关联会话
+type UserSession struct {
+	Id           int64       `json:"id"           description:"主键ID"`
+	SessionId    string      `json:"sessionId"    description:"会话ID"`
+	UserId       int64       `json:"userId"       description:"用户ID"`
+	TenantId     uint64      `json:"tenantId"     description:"租户ID"`
+	Device       string      `json:"device"       description:"设备"`
+	Ip           string      `json:"ip"           description:"登录IP"`
+	UserAgent    string      `json:"userAgent"    description:"用户代理"`
+	LastSeenAt   *gtime.Time `json:"lastSeenAt"   description:"最后活跃时间"`
+	LastSeenIp   string      `json:"lastSeenIp"   description:"最后活跃IP"`
+	ExpiresAt    *gtime.Time `json:"expiresAt"    description:"过期时间"`
+	RevokedAt    *gtime.Time `json:"revokedAt"    description:"撤销时间"`
+	RevokeReason string      `json:"revokeReason" description:"撤销原因"`
+	CreatedAt    *gtime.Time `json:"createdAt"    description:"创建时间"`
+}
+
+// IsActive 判断会话是否有效
+func (s *UserSession) IsActive() bool {
+	return s.RevokedAt == nil && s.ExpiresAt != nil && s.ExpiresAt.After(gtime.Now())
+}
@@ -47,12 +47,17 @@ type User struct {
 	DeptId               int64       `json:"deptId"                description:"部门ID"`
 	Position             string      `json:"position"              description:"职位"`
 	Status               int         `json:"status"                description:"状态"`
+	PermVersion          int64       `json:"permVersion"           description:"权限版本"`
 	LoginIp              string      `json:"loginIp"               description:"最后登录IP"`
 	LoginAt              *gtime.Time `json:"loginAt"               description:"最后登录时间"`
 	LoginCount           int         `json:"loginCount"            description:"登录次数"`
+	LockedUntil          *gtime.Time `json:"lockedUntil"           description:"自动解锁时间"`
 	PasswordResetToken   string      `json:"-"                     description:"密码重置令牌"`
 	PasswordResetExpires *gtime.Time `json:"-"                     description:"密码重置过期时间"`
+	PasswordChangedAt    *gtime.Time `json:"passwordChangedAt"     description:"密码修改时间"`
 	EmailVerifiedAt      *gtime.Time `json:"emailVerifiedAt"       description:"邮箱验证时间"`
+	EmailVerifyToken     string      `json:"-"                     description:"邮箱验证令牌"`
+	EmailVerifyExpires   *gtime.Time `json:"-"                     description:"邮箱验证过期时间"`
 	PhoneVerifiedAt      *gtime.Time `json:"phoneVerifiedAt"       description:"手机验证时间"`
 	TwoFactorEnabled     int         `json:"twoFactorEnabled"      description:"是否启用双因子认证"`
 	TwoFactorSecret      string      `json:"-"                     description:"双因子认证密钥"`
@@ -82,6 +87,56 @@ const (
 	TwoFactorEnabled  = 1 // 启用
 )
 
+// InviteCode 注册邀请码实体
+type InviteCode struct {
+	Id        int64       `json:"id"        description:"主键ID"`
+	TenantId  uint64      `json:"tenantId"  description:"租户ID"`
+	Code      string      `json:"code"      description:"邀请码"`
+	MaxUses   int         `json:"maxUses"   description:"最大使用次数"`
+	UsedCount int         `json:"usedCount" description:"已使用次数"`
+	ExpiresAt *gtime.Time `json:"expiresAt" description:"过期时间"`
+	Remark    string      `json:"remark"    description:"备注"`
+	CreatedBy int64       `json:"createdBy" description:"创建人ID"`
+	CreatedAt *gtime.Time `json:"createdAt" description:"创建时间"`
+}
+
+// IsAvailable 判断邀请码是否可用
+func (c *InviteCode) IsAvailable() bool {
+	if c.ExpiresAt != nil && c.ExpiresAt.Before(gtime.Now()) {
+		return false
+	}
+	return c.UsedCount < c.MaxUses
+}
+
+// PasswordHistory 历史密码实体，用于限制重复使用最近的密码
+type PasswordHistory struct {
+	Id        int64       `json:"id"        description:"主键ID"`
+	UserId    int64       `json:"userId"    description:"用户ID"`
+	Password  string      `json:"-"         description:"密码哈希"`
+	CreatedAt *gtime.Time `json:"createdAt" description:"创建时间"`
+}
+
+// UserIdentity 外部身份关联实体，将身份提供方的主体映射到本地用户
+type UserIdentity struct {
+	Id          int64       `json:"id"          description:"主键ID"`
+	TenantId    uint64      `json:"tenantId"    description:"租户ID"`
+	UserId      int64       `json:"userId"      description:"用户ID"`
+	Issuer      string      `json:"issuer"      description:"身份提供方"`
+	Subject     string      `json:"subject"     description:"外部用户标识"`
+	Email       string      `json:"email"       description:"外部邮箱"`
+	LastLoginAt *gtime.Time `json:"lastLoginAt" description:"最后登录时间"`
+	CreatedAt   *gtime.Time `json:"createdAt"   description:"创建时间"`
+}
+
+// UserRecoveryCode 双因子认证恢复码实体
+type UserRecoveryCode struct {
+	Id        int64       `json:"id"        description:"主键ID"`
+	UserId    int64       `json:"userId"    description:"用户ID"`
+	CodeHash  string      `json:"-"         description:"恢复码哈希"`
+	UsedAt    *gtime.Time `json:"usedAt"    description:"使用时间"`
+	CreatedAt *gtime.Time `json:"createdAt" description:"创建时间"`
+}
+
 // IsActive 判断用户是否活跃
 func (u *User) IsActive() bool {
 	return u.Status == UserStatusNormal && u.DeletedAt == nil
@@ -92,6 +147,11 @@ func (u *User) IsLocked() bool {
 	return u.Status == UserStatusLocked
 }
 
+// IsLockExpired 判断临时锁定是否已到期，管理员锁定没有解锁时间，不会自动到期
+func (u *User) IsLockExpired() bool {
+	return u.Status == UserStatusLocked && u.LockedUntil != nil && !u.LockedUntil.After(gtime.Now())
+}
+
 // IsDisabled 判断用户是否被禁用
 func (u *User) IsDisabled() bool {
 	return u.Status == UserStatusDisabled
@@ -0,0 +1,39 @@
+package sysin
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// ApiKeyCreateInp 创建API密钥参数
+type ApiKeyCreateInp struct {
+	Name       string      `json:"name"       v:"required|length:1,64"   description:"密钥名称"`
+	Scopes     []string    `json:"scopes"     v:"required|length:1,200"  description:"权限范围，必须是当前用户拥有的权限"`
+	AllowedIps []string    `json:"allowedIps" v:"length:0,20"            description:"允许访问的IP或CIDR网段，为空不限制"`
+	ExpiresAt  *gtime.Time `json:"expiresAt"                             description:"过期时间，为空表示不过期"`
+}
+
+// Filter 参数过滤和验证
+func (inp *ApiKeyCreateInp) Filter(ctx context.Context) error {
+	inp.Name = strings.TrimSpace(inp.Name)
+	for i := range inp.Scopes {
+		inp.Scopes[i] = strings.TrimSpace(inp.Scopes[i])
+	}
+	for i := range inp.AllowedIps {
+		inp.AllowedIps[i] = strings.TrimSpace(inp.AllowedIps[i])
+	}
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// ApiKeyRevokeInp 撤销API密钥参数
+type ApiKeyRevokeInp struct {
+	Id int64 `json:"id" v:"required|min:1" description:"密钥ID"`
+}
+
+// Filter 参数过滤和验证
+func (inp *ApiKeyRevokeInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
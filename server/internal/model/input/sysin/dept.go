@@ -0,0 +1,135 @@
+package sysin
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// DeptTreeInp 部门树查询参数
+type DeptTreeInp struct {
+	Name   string `json:"name"   v:"length:0,50"  description:"部门名称（模糊查询），命中的部门会连同上级部门一起返回"`
+	Status int    `json:"status" v:"in:-1,0,1" d:"-1" description:"状态：-1=全部 1=启用 0=禁用"`
+}
+
+// Filter 参数过滤和验证
+func (inp *DeptTreeInp) Filter(ctx context.Context) error {
+	inp.Name = strings.TrimSpace(inp.Name)
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// DeptDetailInp 部门详情查询参数
+type DeptDetailInp struct {
+	Id int64 `json:"id" v:"required|min:1" description:"部门ID"`
+}
+
+// Filter 参数过滤和验证
+func (inp *DeptDetailInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// CreateDeptInp 创建部门参数
+type CreateDeptInp struct {
+	ParentId int64  `json:"parentId" v:"min:0"                               description:"上级部门ID，0表示顶级部门"`
+	Name     string `json:"name"     v:"required|length:1,50"                description:"部门名称"`
+	Code     string `json:"code"     v:"required|length:1,50|regex:^[a-zA-Z0-9_-]+$" description:"部门编码，租户内唯一"`
+	Type     string `json:"type"     v:"in:company,dept,team" d:"dept"       description:"部门类型：company=公司 dept=部门 team=小组"`
+	LeaderId int64  `json:"leaderId" v:"min:0"                               description:"负责人用户ID"`
+	Phone    string `json:"phone"    v:"phone|length:0,20"                   description:"联系电话"`
+	Email    string `json:"email"    v:"email|length:0,100"                  description:"联系邮箱"`
+	Sort     int    `json:"sort"     v:"min:0"                               description:"排序号"`
+	Status   int    `json:"status"   v:"in:0,1" d:"1"                        description:"状态：1=启用 0=禁用"`
+	Remark   string `json:"remark"   v:"length:0,500"                        description:"备注说明"`
+}
+
+// Filter 参数过滤和验证
+func (inp *CreateDeptInp) Filter(ctx context.Context) error {
+	inp.Name = strings.TrimSpace(inp.Name)
+	inp.Code = strings.TrimSpace(inp.Code)
+	inp.Phone = strings.TrimSpace(inp.Phone)
+	inp.Email = strings.TrimSpace(inp.Email)
+	inp.Remark = strings.TrimSpace(inp.Remark)
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// UpdateDeptInp 更新部门参数，调整上级部门请使用移动部门
+type UpdateDeptInp struct {
+	Id       int64  `json:"id"       v:"required|min:1"                      description:"部门ID"`
+	Name     string `json:"name"     v:"required|length:1,50"                description:"部门名称"`
+	Code     string `json:"code"     v:"required|length:1,50|regex:^[a-zA-Z0-9_-]+$" description:"部门编码，租户内唯一"`
+	Type     string `json:"type"     v:"required|in:company,dept,team"       description:"部门类型：company=公司 dept=部门 team=小组"`
+	LeaderId int64  `json:"leaderId" v:"min:0"                               description:"负责人用户ID，0表示不设置"`
+	Phone    string `json:"phone"    v:"phone|length:0,20"                   description:"联系电话"`
+	Email    string `json:"email"    v:"email|length:0,100"                  description:"联系邮箱"`
+	Sort     int    `json:"sort"     v:"min:0"                               description:"排序号"`
+	Remark   string `json:"remark"   v:"length:0,500"                        description:"备注说明"`
+}
+
+// Filter 参数过滤和验证
+func (inp *UpdateDeptInp) Filter(ctx context.Context) error {
+	inp.Name = strings.TrimSpace(inp.Name)
+	inp.Code = strings.TrimSpace(inp.Code)
+	inp.Phone = strings.TrimSpace(inp.Phone)
+	inp.Email = strings.TrimSpace(inp.Email)
+	inp.Remark = strings.TrimSpace(inp.Remark)
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// MoveDeptInp 移动部门参数，下级部门随之移动
+type MoveDeptInp struct {
+	Id       int64 `json:"id"       v:"required|min:1" description:"部门ID"`
+	ParentId int64 `json:"parentId" v:"min:0"          description:"新的上级部门ID，0表示移动为顶级部门"`
+	Sort     *int  `json:"sort"     v:"min:0"          description:"新的排序号，不传则保持不变"`
+}
+
+// Filter 参数过滤和验证
+func (inp *MoveDeptInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// UpdateDeptStatusInp 修改部门状态参数
+type UpdateDeptStatusInp struct {
+	Id     int64 `json:"id"     v:"required|min:1"    description:"部门ID"`
+	Status int   `json:"status" v:"required|in:0,1"   description:"状态：1=启用 0=禁用，禁用时下级部门一并禁用"`
+}
+
+// Filter 参数过滤和验证
+func (inp *UpdateDeptStatusInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// DeleteDeptInp 删除部门参数
+type DeleteDeptInp struct {
+	Id int64 `json:"id" v:"required|min:1" description:"部门ID"`
+}
+
+// Filter 参数过滤和验证
+func (inp *DeleteDeptInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// MoveDeptUsersInp 调整用户所属部门参数
+type MoveDeptUsersInp struct {
+	UserIds []int64 `json:"userIds" v:"required|min-length:1" description:"用户ID列表"`
+	DeptId  int64   `json:"deptId"  v:"min:0"                 description:"目标部门ID，0表示移出部门"`
+}
+
+// Filter 参数过滤和验证
+func (inp *MoveDeptUsersInp) Filter(ctx context.Context) error {
+	if err := g.Validator().Data(inp).Run(ctx); err != nil {
+		return err
+	}
+
+	// 去重并过滤无效ID
+	var validIds []int64
+	idMap := make(map[int64]bool)
+	for _, id := range inp.UserIds {
+		if id > 0 && !idMap[id] {
+			validIds = append(validIds, id)
+			idMap[id] = true
+		}
+	}
+	inp.UserIds = validIds
+	return nil
+}
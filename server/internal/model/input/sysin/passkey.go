@@ -0,0 +1,100 @@
+package sysin
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// PasskeyAttestation 浏览器注册通行密钥返回的凭证，与 PublicKeyCredential.toJSON() 格式一致
+type PasskeyAttestation struct {
+	Id       string `json:"id"   v:"required|length:1,1400" description:"凭证ID（base64url）"`
+	Type     string `json:"type" v:"required|in:public-key"  description:"凭证类型"`
+	Response struct {
+		ClientDataJSON    string   `json:"clientDataJSON"    v:"required" description:"客户端数据（base64url）"`
+		AttestationObject string   `json:"attestationObject" v:"required" description:"证明对象（base64url）"`
+		Transports        []string `json:"transports"                     description:"传输方式"`
+	} `json:"response"`
+}
+
+// PasskeyAssertion 浏览器使用通行密钥签名返回的凭证，与 PublicKeyCredential.toJSON() 格式一致
+type PasskeyAssertion struct {
+	Id       string `json:"id"   v:"required|length:1,1400" description:"凭证ID（base64url）"`
+	Type     string `json:"type" v:"required|in:public-key"  description:"凭证类型"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"    v:"required" description:"客户端数据（base64url）"`
+		AuthenticatorData string `json:"authenticatorData" v:"required" description:"认证器数据（base64url）"`
+		Signature         string `json:"signature"         v:"required" description:"签名（base64url）"`
+		UserHandle        string `json:"userHandle"                     description:"用户句柄（base64url）"`
+	} `json:"response"`
+}
+
+// PasskeyRegisterFinishInp 完成通行密钥注册参数
+type PasskeyRegisterFinishInp struct {
+	ChallengeId string             `json:"challengeId" v:"required|length:1,64" description:"注册选项返回的挑战ID"`
+	Name        string             `json:"name"        v:"required|length:1,64" description:"通行密钥名称，便于区分设备"`
+	Credential  PasskeyAttestation `json:"credential"                           description:"浏览器返回的凭证"`
+}
+
+// Filter 参数过滤和验证
+func (inp *PasskeyRegisterFinishInp) Filter(ctx context.Context) error {
+	inp.Name = strings.TrimSpace(inp.Name)
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// PasskeyDeleteInp 删除通行密钥参数
+type PasskeyDeleteInp struct {
+	Id int64 `json:"id" v:"required|min:1" description:"通行密钥ID"`
+}
+
+// Filter 参数过滤和验证
+func (inp *PasskeyDeleteInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// PasskeyLoginBeginInp 发起通行密钥登录参数
+type PasskeyLoginBeginInp struct {
+	TenantCode string `json:"tenantCode" v:"required|length:1,50" description:"租户编码"`
+	Username   string `json:"username"   v:"length:0,50"          description:"用户名，为空时由浏览器列出可用的通行密钥"`
+}
+
+// Filter 参数过滤和验证
+func (inp *PasskeyLoginBeginInp) Filter(ctx context.Context) error {
+	inp.TenantCode = strings.TrimSpace(inp.TenantCode)
+	inp.Username = strings.TrimSpace(inp.Username)
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// PasskeyLoginFinishInp 完成通行密钥登录参数
+type PasskeyLoginFinishInp struct {
+	ChallengeId string           `json:"challengeId" v:"required|length:1,64" description:"登录选项返回的挑战ID"`
+	Credential  PasskeyAssertion `json:"credential"                           description:"浏览器返回的凭证"`
+}
+
+// Filter 参数过滤和验证
+func (inp *PasskeyLoginFinishInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// PasskeyTwoFactorBeginInp 发起通行密钥二次验证参数
+type PasskeyTwoFactorBeginInp struct {
+	ChallengeToken string `json:"challengeToken" v:"required" description:"登录返回的挑战令牌"`
+}
+
+// Filter 参数过滤和验证
+func (inp *PasskeyTwoFactorBeginInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// PasskeyTwoFactorFinishInp 完成通行密钥二次验证参数
+type PasskeyTwoFactorFinishInp struct {
+	ChallengeToken string           `json:"challengeToken" v:"required"             description:"登录返回的挑战令牌"`
+	ChallengeId    string           `json:"challengeId"    v:"required|length:1,64" description:"验证选项返回的挑战ID"`
+	Credential     PasskeyAssertion `json:"credential"                              description:"浏览器返回的凭证"`
+}
+
+// Filter 参数过滤和验证
+func (inp *PasskeyTwoFactorFinishInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
@@ -1,8 +1,11 @@
 package sysin
 
 import (
+	"client-app/internal/model/entity"
 	"context"
 	"strings"
+
+	"github.com/gogf/gf/v2/errors/gerror"
 )
 
 // RoleListInp 角色列表查询参数
@@ -60,7 +63,8 @@ type CreateRoleInp struct {
 	Sort        int     `json:"sort" v:"min:0#排序号不能小于0"`
 	DataScope   int     `json:"dataScope" v:"required|in:1,2,3,4,5#数据权限范围不能为空|数据权限范围必须是1-5之间的数字"`
 	Remark      string  `json:"remark" v:"length:0,500#备注说明长度不能超过500个字符"`
-	MenuIds     []int64 `json:"menuIds" v:""` // 菜单权限ID列表
+	ParentId    int64   `json:"parentId" v:"min:0#上级角色ID不能小于0"` // 上级角色ID，继承其菜单权限，0表示不继承
+	MenuIds     []int64 `json:"menuIds" v:""`                   // 菜单权限ID列表
 }
 
 // Filter 过滤输入参数
@@ -108,7 +112,8 @@ type UpdateRoleInp struct {
 	Sort        int     `json:"sort" v:"min:0#排序号不能小于0"`
 	DataScope   int     `json:"dataScope" v:"required|in:1,2,3,4,5#数据权限范围不能为空|数据权限范围必须是1-5之间的数字"`
 	Remark      string  `json:"remark" v:"length:0,500#备注说明长度不能超过500个字符"`
-	MenuIds     []int64 `json:"menuIds" v:""` // 菜单权限ID列表
+	ParentId    int64   `json:"parentId" v:"min:0#上级角色ID不能小于0"` // 上级角色ID，继承其菜单权限，0表示不继承
+	MenuIds     []int64 `json:"menuIds" v:""`                   // 菜单权限ID列表
 }
 
 // Filter 过滤输入参数
@@ -203,6 +208,48 @@ func (in *UpdateRoleMenuInp) Filter(ctx context.Context) (err error) {
 	return nil
 }
 
+// RoleDataScopeInp 角色数据权限查询参数
+type RoleDataScopeInp struct {
+	RoleId int64 `json:"roleId" p:"id" v:"required|min:1#角色ID不能为空|角色ID必须大于0"`
+}
+
+// Filter 过滤输入参数
+func (in *RoleDataScopeInp) Filter(ctx context.Context) (err error) {
+	return nil
+}
+
+// UpdateRoleDataScopeInp 更新角色数据权限参数
+type UpdateRoleDataScopeInp struct {
+	RoleId    int64   `json:"roleId" p:"id" v:"required|min:1#角色ID不能为空|角色ID必须大于0"`
+	DataScope int     `json:"dataScope" v:"required|in:1,2,3,4,5#数据权限范围不能为空|数据权限范围必须是1-5之间的数字"`
+	DeptIds   []int64 `json:"deptIds" v:""` // 自定义数据权限可访问的部门ID列表
+}
+
+// Filter 过滤输入参数
+func (in *UpdateRoleDataScopeInp) Filter(ctx context.Context) (err error) {
+	// 非自定义数据权限不保留部门
+	if in.DataScope != entity.DataScopeCustom {
+		in.DeptIds = nil
+		return nil
+	}
+
+	// 去重部门ID
+	deptIdMap := make(map[int64]bool)
+	uniqueDeptIds := make([]int64, 0, len(in.DeptIds))
+	for _, deptId := range in.DeptIds {
+		if deptId > 0 && !deptIdMap[deptId] {
+			deptIdMap[deptId] = true
+			uniqueDeptIds = append(uniqueDeptIds, deptId)
+		}
+	}
+	in.DeptIds = uniqueDeptIds
+
+	if len(in.DeptIds) == 0 {
+		return gerror.New("自定义数据权限至少选择一个部门")
+	}
+	return nil
+}
+
 // RoleOptionInp 角色选项查询参数（用于下拉框等）
 type RoleOptionInp struct {
 	Status int `json:"status" v:""` // 状态过滤：1=启用 0=禁用，-1=全部
@@ -26,7 +26,7 @@ type CreateTenantInp struct {
 	ExpireAt     *gtime.Time `json:"expireAt"     description:"过期时间"`
 	AdminName    string      `json:"adminName"    v:"required|length:1,50#管理员用户名不能为空|用户名长度不能超过50字符"`
 	AdminEmail   string      `json:"adminEmail"   v:"required|email#管理员邮箱不能为空|邮箱格式不正确"`
-	AdminPassword string     `json:"adminPassword" v:"required|length:6,32#管理员密码不能为空|密码长度必须在6-32位之间"`
+	AdminPassword string     `json:"adminPassword" v:"required#管理员密码不能为空" description:"管理员密码，按系统密码策略校验"`
 	Remark       string      `json:"remark"       v:"length:0,500#备注长度不能超过500字符"`
 }
 
@@ -102,3 +102,28 @@ type TenantConfigInp struct {
 func (in *TenantConfigInp) Filter(ctx context.Context) error {
 	return g.Validator().Data(in).Run(ctx)
 }
+
+// InviteCodeCreateInp 创建注册邀请码参数
+type InviteCodeCreateInp struct {
+	TenantId  uint64      `json:"tenantId"  v:"min:0"                  description:"租户ID，系统管理员可指定，其他用户为当前租户"`
+	MaxUses   int         `json:"maxUses"   v:"min:1|max:10000" d:"1"  description:"最大使用次数"`
+	ExpiresAt *gtime.Time `json:"expiresAt"                            description:"过期时间，为空表示不过期"`
+	Remark    string      `json:"remark"    v:"length:0,255"           description:"备注"`
+}
+
+// 参数过滤和验证方法
+func (in *InviteCodeCreateInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(in).Run(ctx)
+}
+
+// InviteCodeListInp 注册邀请码列表参数
+type InviteCodeListInp struct {
+	TenantId uint64 `json:"tenantId" v:"min:0"                description:"租户ID，系统管理员可指定，其他用户为当前租户"`
+	Page     int    `json:"page"     v:"min:1"         d:"1"  description:"页码"`
+	PageSize int    `json:"pageSize" v:"min:1|max:100" d:"20" description:"每页数量"`
+}
+
+// 参数过滤和验证方法
+func (in *InviteCodeListInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(in).Run(ctx)
+}
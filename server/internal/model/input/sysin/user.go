@@ -3,6 +3,7 @@ package sysin
 import (
 	"client-app/internal/model/entity"
 	"context"
+	"strings"
 
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
@@ -66,8 +67,8 @@ type CreateUserInp struct {
 	Username         string      `json:"username"         v:"required|length:3,50"              description:"用户名"`
 	Email            string      `json:"email"            v:"email|length:0,100"                description:"邮箱地址"`
 	Phone            string      `json:"phone"            v:"phone|length:0,20"                 description:"手机号码"`
-	Password         string      `json:"password"         v:"required|length:6,32"              description:"密码"`
-	ConfirmPassword  string      `json:"confirmPassword"  v:"required|same:password"            description:"确认密码"`
+	Password         string      `json:"password"         v:"required"                          description:"密码，按租户密码策略校验"`
+	ConfirmPassword  string      `json:"confirmPassword"  v:"required"                          description:"确认密码"`
 	RealName         string      `json:"realName"         v:"required|length:2,50"              description:"真实姓名"`
 	Nickname         string      `json:"nickname"         v:"length:0,50"                       description:"昵称"`
 	Avatar           string      `json:"avatar"           v:"url|length:0,255"                  description:"头像URL"`
@@ -83,13 +84,16 @@ type CreateUserInp struct {
 
 // Filter 参数过滤和验证
 func (inp *CreateUserInp) Filter(ctx context.Context) error {
+	inp.Username = strings.TrimSpace(inp.Username)
+	inp.Email = strings.TrimSpace(inp.Email)
+
 	// 自定义验证规则
 	rules := map[string]string{
 		"username":         "required|length:3,50",
 		"email":            "email|length:0,100",
 		"phone":            "phone|length:0,20",
-		"password":         "required|length:6,32",
-		"confirmPassword":  "required|same:password",
+		"password":         "required",
+		"confirmPassword":  "required",
 		"realName":         "required|length:2,50",
 		"nickname":         "length:0,50",
 		"avatar":           "url|length:0,255",
@@ -127,6 +131,8 @@ type UpdateUserInp struct {
 
 // Filter 参数过滤和验证
 func (inp *UpdateUserInp) Filter(ctx context.Context) error {
+	inp.Username = strings.TrimSpace(inp.Username)
+	inp.Email = strings.TrimSpace(inp.Email)
 	return g.Validator().Data(inp).Run(ctx)
 }
 
@@ -170,8 +176,8 @@ func (inp *UpdateUserStatusInp) Filter(ctx context.Context) error {
 // ResetPasswordInp 重置密码参数
 type ResetPasswordInp struct {
 	Id              int64  `json:"id"              v:"required|min:1"      description:"用户ID"`
-	NewPassword     string `json:"newPassword"     v:"required|length:6,32" description:"新密码"`
-	ConfirmPassword string `json:"confirmPassword" v:"required|same:newPassword" description:"确认密码"`
+	NewPassword     string `json:"newPassword"     v:"required"             description:"新密码，按租户密码策略校验"`
+	ConfirmPassword string `json:"confirmPassword" v:"required"             description:"确认密码"`
 }
 
 // Filter 参数过滤和验证
@@ -327,9 +333,9 @@ func (inp *BatchCheckPermissionInp) Filter(ctx context.Context) error {
 type UserLoginInp struct {
 	TenantCode string `json:"tenantCode" v:"required|length:1,50"  description:"租户编码"`
 	Username   string `json:"username"   v:"required|length:3,50"  description:"用户名"`
-	Password   string `json:"password"   v:"required|length:6,32"  description:"密码"`
-	Captcha    string `json:"captcha"    v:"required|length:4,6"   description:"验证码"`
-	CaptchaId  string `json:"captchaId"  v:"required"              description:"验证码ID"`
+	Password   string `json:"password"   v:"required|length:1,2048" description:"密码，使用传输加密公钥加密"`
+	Captcha    string `json:"captcha"    v:"length:1,8"            description:"验证码，登录失败次数过多时必填"`
+	CaptchaId  string `json:"captchaId"                            description:"验证码ID"`
 	RememberMe bool   `json:"rememberMe" d:"false"                 description:"记住我"`
 }
 
@@ -358,6 +364,171 @@ func (inp *RefreshTokenInp) Filter(ctx context.Context) error {
 	return g.Validator().Data(inp).Run(ctx)
 }
 
+// UserLoginTwoFactorInp 双因子认证登录参数
+type UserLoginTwoFactorInp struct {
+	ChallengeToken string `json:"challengeToken" v:"required"              description:"登录返回的挑战令牌"`
+	Code           string `json:"code"           v:"required|length:6,16"  description:"动态验证码或恢复码"`
+}
+
+// Filter 参数过滤和验证
+func (inp *UserLoginTwoFactorInp) Filter(ctx context.Context) error {
+	inp.Code = strings.TrimSpace(inp.Code)
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// TwoFactorConfirmInp 确认启用双因子认证参数
+type TwoFactorConfirmInp struct {
+	Code string `json:"code" v:"required|length:6,6" description:"动态验证码"`
+}
+
+// Filter 参数过滤和验证
+func (inp *TwoFactorConfirmInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// TwoFactorDisableInp 关闭双因子认证参数
+type TwoFactorDisableInp struct {
+	Password string `json:"password" v:"required"              description:"登录密码"`
+	Code     string `json:"code"     v:"required|length:6,16"  description:"动态验证码或恢复码"`
+}
+
+// Filter 参数过滤和验证
+func (inp *TwoFactorDisableInp) Filter(ctx context.Context) error {
+	inp.Code = strings.TrimSpace(inp.Code)
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// TwoFactorRecoveryCodesInp 重新生成恢复码参数
+type TwoFactorRecoveryCodesInp struct {
+	Code string `json:"code" v:"required|length:6,6" description:"动态验证码"`
+}
+
+// Filter 参数过滤和验证
+func (inp *TwoFactorRecoveryCodesInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// CaptchaInp 获取验证码参数
+type CaptchaInp struct {
+	TenantCode string `json:"tenantCode" v:"length:0,50" description:"租户编码，按租户配置选择验证码类型"`
+	Audio      bool   `json:"audio"                      description:"是否获取语音验证码，供视障用户使用"`
+}
+
+// Filter 参数过滤和验证
+func (inp *CaptchaInp) Filter(ctx context.Context) error {
+	inp.TenantCode = strings.TrimSpace(inp.TenantCode)
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// ForgotPasswordInp 找回密码参数
+type ForgotPasswordInp struct {
+	TenantCode string `json:"tenantCode" v:"required|length:1,50"  description:"租户编码"`
+	Account    string `json:"account"    v:"required|length:3,100" description:"用户名或邮箱"`
+	Captcha    string `json:"captcha"    v:"required|length:1,8"   description:"验证码"`
+	CaptchaId  string `json:"captchaId"  v:"required"              description:"验证码ID"`
+}
+
+// Filter 参数过滤和验证
+func (inp *ForgotPasswordInp) Filter(ctx context.Context) error {
+	inp.Account = strings.TrimSpace(inp.Account)
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// ResetPasswordByTokenInp 通过重置令牌设置新密码参数
+type ResetPasswordByTokenInp struct {
+	Token           string `json:"token"           v:"required|length:64,64"     description:"重置令牌"`
+	NewPassword     string `json:"newPassword"     v:"required"                  description:"新密码"`
+	ConfirmPassword string `json:"confirmPassword" v:"required"                  description:"确认密码"`
+}
+
+// Filter 参数过滤和验证
+func (inp *ResetPasswordByTokenInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// UserRegisterInp 用户注册参数，租户由请求域名或 X-Tenant-Id 确定
+type UserRegisterInp struct {
+	Username        string `json:"username"        v:"required|passport|length:3,50" description:"用户名"`
+	Password        string `json:"password"        v:"required"                      description:"密码"`
+	ConfirmPassword string `json:"confirmPassword" v:"required"                      description:"确认密码"`
+	Email           string `json:"email"           v:"required|email|length:5,100"   description:"邮箱地址"`
+	RealName        string `json:"realName"        v:"length:0,50"                   description:"真实姓名"`
+	InviteCode      string `json:"inviteCode"      v:"length:0,32"                   description:"邀请码，仅限邀请注册时必填"`
+	Captcha         string `json:"captcha"         v:"required|length:1,8"           description:"验证码"`
+	CaptchaId       string `json:"captchaId"       v:"required"                      description:"验证码ID"`
+}
+
+// Filter 参数过滤和验证
+func (inp *UserRegisterInp) Filter(ctx context.Context) error {
+	inp.Username = strings.TrimSpace(inp.Username)
+	inp.Email = strings.TrimSpace(inp.Email)
+	inp.InviteCode = strings.TrimSpace(inp.InviteCode)
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// VerifyEmailInp 邮箱验证参数
+type VerifyEmailInp struct {
+	Token string `json:"token" v:"required|length:64,64" description:"验证令牌"`
+}
+
+// Filter 参数过滤和验证
+func (inp *VerifyEmailInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// SessionRevokeInp 撤销本人登录会话参数
+type SessionRevokeInp struct {
+	SessionId string `json:"sessionId" v:"required|length:32,32" description:"会话ID"`
+}
+
+// Filter 参数过滤和验证
+func (inp *SessionRevokeInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// UserSessionListInp 查询用户登录会话参数
+type UserSessionListInp struct {
+	UserId int64 `json:"userId" v:"required|min:1" description:"用户ID"`
+}
+
+// Filter 参数过滤和验证
+func (inp *UserSessionListInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// UserSessionRevokeInp 撤销用户登录会话参数
+type UserSessionRevokeInp struct {
+	UserId    int64  `json:"userId"    v:"required|min:1"        description:"用户ID"`
+	SessionId string `json:"sessionId" v:"required|length:32,32" description:"会话ID"`
+}
+
+// Filter 参数过滤和验证
+func (inp *UserSessionRevokeInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// OidcAuthorizeInp 发起单点登录参数
+type OidcAuthorizeInp struct {
+	TenantCode string `json:"tenantCode" v:"required|length:1,50" description:"租户编码"`
+}
+
+// Filter 参数过滤和验证
+func (inp *OidcAuthorizeInp) Filter(ctx context.Context) error {
+	inp.TenantCode = strings.TrimSpace(inp.TenantCode)
+	return g.Validator().Data(inp).Run(ctx)
+}
+
+// OidcCallbackInp 单点登录回调参数
+type OidcCallbackInp struct {
+	State string `json:"state" v:"required|length:1,128"  description:"授权时返回的state"`
+	Code  string `json:"code"  v:"required|length:1,2048" description:"身份提供方返回的授权码"`
+}
+
+// Filter 参数过滤和验证
+func (inp *OidcCallbackInp) Filter(ctx context.Context) error {
+	return g.Validator().Data(inp).Run(ctx)
+}
+
 // ValidateUserStatus 验证用户状态
 func ValidateUserStatus(status int) error {
 	if !entity.ValidateUserStatus(status) {
@@ -388,28 +559,14 @@ func ValidateUsername(ctx context.Context, username string) error {
 	return nil
 }
 
-// ValidatePassword 验证密码强度
-func ValidatePassword(ctx context.Context, password string) error {
-	if err := g.Validator().Rules("required|length:6,32").Messages("密码|密码是必填项|密码长度必须在6-32字符之间").Data(password).Run(ctx); err != nil {
-		return err
-	}
-
-	// 检查密码复杂度（至少包含数字和字母）
-	hasNumber := false
-	hasLetter := false
-
-	for _, char := range password {
-		if char >= '0' && char <= '9' {
-			hasNumber = true
-		}
-		if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') {
-			hasLetter = true
-		}
-	}
-
-	if !hasNumber || !hasLetter {
-		return gerror.New("密码必须包含字母和数字")
-	}
+// ImpersonateInp 模拟登录参数
+type ImpersonateInp struct {
+	UserId int64  `json:"userId" v:"required|min:1"          description:"目标用户ID"`
+	Reason string `json:"reason" v:"required|length:4,255"   description:"模拟登录原因，记入审计日志"`
+}
 
-	return nil
+// Filter 参数过滤和验证
+func (inp *ImpersonateInp) Filter(ctx context.Context) error {
+	inp.Reason = strings.TrimSpace(inp.Reason)
+	return g.Validator().Data(inp).Run(ctx)
 }
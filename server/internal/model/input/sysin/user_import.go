@@ -0,0 +1,58 @@
+package sysin
+
+import (
+	"client-app/utility/sheet"
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+)
+
+// 用户导入模式
+const (
+	UserImportModeDryRun = "dryRun" // 仅校验，不写入
+	UserImportModeCommit = "commit" // 校验通过后写入
+)
+
+// UserImportMaxFileSize 导入文件大小上限
+const UserImportMaxFileSize = 5 << 20
+
+// ImportUserInp 批量导入用户参数
+type ImportUserInp struct {
+	File     *ghttp.UploadFile `json:"file"     type:"file" v:"required"     description:"导入文件，支持csv、xlsx，列为username、email、phone、department（部门编码）、roles（角色编码）"`
+	Mode     string            `json:"mode"     v:"in:dryRun,commit" d:"dryRun" description:"导入模式：dryRun=仅校验 commit=写入"`
+	Password string            `json:"password"                              description:"初始密码（传输加密），按租户密码策略校验；为空时设置随机密码，用户需通过找回密码设置"`
+	Status   int               `json:"status"   v:"in:1,3"           d:"1"     description:"导入后的用户状态：1=正常 3=禁用"`
+}
+
+// Filter 参数过滤和验证
+func (inp *ImportUserInp) Filter(ctx context.Context) error {
+	if err := g.Validator().Data(inp).Run(ctx); err != nil {
+		return err
+	}
+	if inp.File == nil {
+		return gerror.New("请上传导入文件")
+	}
+	if sheet.FormatOf(inp.File.Filename) == "" {
+		return sheet.ErrFormat
+	}
+	if inp.File.Size > UserImportMaxFileSize {
+		return gerror.Newf("导入文件不能超过%dMB", UserImportMaxFileSize>>20)
+	}
+	return nil
+}
+
+// ExportUserInp 导出用户参数，筛选条件与用户列表一致
+type ExportUserInp struct {
+	UserListInp
+	Format string `json:"format" v:"in:csv,xlsx" d:"xlsx" description:"导出格式：csv、xlsx"`
+}
+
+// Filter 参数过滤和验证
+func (inp *ExportUserInp) Filter(ctx context.Context) error {
+	if err := inp.UserListInp.Filter(ctx); err != nil {
+		return err
+	}
+	return g.Validator().Rules("in:csv,xlsx").Messages("导出格式只支持csv、xlsx").Data(inp.Format).Run(ctx)
+}
@@ -0,0 +1,41 @@
+package sysout
+
+import (
+	"client-app/internal/model/entity"
+
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// ApiKeyModel API密钥模型，不包含密钥本身
+type ApiKeyModel struct {
+	Id         int64       `json:"id"         description:"密钥ID"`
+	Name       string      `json:"name"       description:"密钥名称"`
+	Prefix     string      `json:"prefix"     description:"密钥前缀"`
+	Scopes     []string    `json:"scopes"     description:"权限范围"`
+	AllowedIps []string    `json:"allowedIps" description:"允许访问的IP或网段"`
+	ExpiresAt  *gtime.Time `json:"expiresAt"  description:"过期时间"`
+	LastUsedAt *gtime.Time `json:"lastUsedAt" description:"最后使用时间"`
+	LastUsedIp string      `json:"lastUsedIp" description:"最后使用IP"`
+	CreatedAt  *gtime.Time `json:"createdAt"  description:"创建时间"`
+}
+
+// ApiKeyCreateModel 创建API密钥结果，密钥只在创建时返回一次
+type ApiKeyCreateModel struct {
+	*ApiKeyModel
+	Key string `json:"key" description:"API密钥，请妥善保存"`
+}
+
+// ConvertToApiKeyModel 将API密钥实体转换为模型
+func ConvertToApiKeyModel(key *entity.ApiKey) *ApiKeyModel {
+	return &ApiKeyModel{
+		Id:         key.Id,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.ScopeList(),
+		AllowedIps: key.AllowedIpList(),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		LastUsedIp: key.LastUsedIp,
+		CreatedAt:  key.CreatedAt,
+	}
+}
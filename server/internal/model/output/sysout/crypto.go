@@ -0,0 +1,13 @@
+package sysout
+
+import "github.com/gogf/gf/v2/os/gtime"
+
+// PublicKeyModel 传输加密公钥
+// 客户端用公钥加密密码后按 {"kid": "...", "ciphertext": "..."} 提交
+type PublicKeyModel struct {
+	Kid           string      `json:"kid"           description:"密钥ID"`
+	Alg           string      `json:"alg"           description:"加密算法：RSA-OAEP-256 X25519"`
+	PublicKey     string      `json:"publicKey"     description:"公钥（base64编码的SPKI）"`
+	ExpiresAt     *gtime.Time `json:"expiresAt"     description:"公钥轮换时间，之后应重新获取"`
+	LegacyEnabled bool        `json:"legacyEnabled" description:"是否仍兼容旧版AES-ECB加密"`
+}
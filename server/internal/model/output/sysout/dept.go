@@ -0,0 +1,70 @@
+package sysout
+
+import (
+	"client-app/internal/model/entity"
+
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// DeptModel 部门基础信息模型
+type DeptModel struct {
+	Id         int64       `json:"id"         description:"主键ID"`
+	ParentId   int64       `json:"parentId"   description:"上级部门ID"`
+	Ancestors  []int64     `json:"ancestors"  description:"上级部门ID链，从顶级部门到直属上级"`
+	Name       string      `json:"name"       description:"部门名称"`
+	Code       string      `json:"code"       description:"部门编码"`
+	Type       string      `json:"type"       description:"部门类型"`
+	TypeName   string      `json:"typeName"   description:"部门类型名称"`
+	LeaderId   int64       `json:"leaderId"   description:"负责人用户ID"`
+	LeaderName string      `json:"leaderName" description:"负责人姓名"`
+	Phone      string      `json:"phone"      description:"联系电话"`
+	Email      string      `json:"email"      description:"联系邮箱"`
+	Sort       int         `json:"sort"       description:"排序号"`
+	Status     int         `json:"status"     description:"状态：1=启用 0=禁用"`
+	Remark     string      `json:"remark"     description:"备注说明"`
+	CreatedAt  *gtime.Time `json:"createdAt"  description:"创建时间"`
+	UpdatedAt  *gtime.Time `json:"updatedAt"  description:"更新时间"`
+}
+
+// DeptTreeModel 部门树模型
+type DeptTreeModel struct {
+	*DeptModel
+	UserCount int              `json:"userCount"          description:"部门直属用户数"`
+	Children  []*DeptTreeModel `json:"children,omitempty" description:"下级部门"`
+}
+
+// DeptDetailModel 部门详情模型
+type DeptDetailModel struct {
+	*DeptModel
+	Parents   []*DeptModel `json:"parents"   description:"上级部门，从顶级部门到直属上级"`
+	UserCount int          `json:"userCount" description:"部门直属用户数"`
+}
+
+// ConvertToDeptModel 将entity.Dept转换为DeptModel
+func ConvertToDeptModel(dept *entity.Dept) *DeptModel {
+	if dept == nil {
+		return nil
+	}
+
+	ancestors := dept.AncestorIds()
+	if ancestors == nil {
+		ancestors = []int64{}
+	}
+	return &DeptModel{
+		Id:        dept.Id,
+		ParentId:  dept.ParentId,
+		Ancestors: ancestors,
+		Name:      dept.Name,
+		Code:      dept.Code,
+		Type:      dept.Type,
+		TypeName:  entity.GetDeptTypeName(dept.Type),
+		LeaderId:  dept.LeaderId,
+		Phone:     dept.Phone,
+		Email:     dept.Email,
+		Sort:      dept.Sort,
+		Status:    dept.Status,
+		Remark:    dept.Remark,
+		CreatedAt: dept.CreatedAt,
+		UpdatedAt: dept.UpdatedAt,
+	}
+}
@@ -0,0 +1,46 @@
+package sysout
+
+import (
+	"client-app/internal/model/entity"
+	"client-app/utility/webauthn"
+
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// PasskeyModel 通行密钥模型，不包含公钥
+type PasskeyModel struct {
+	Id          int64       `json:"id"          description:"通行密钥ID"`
+	Name        string      `json:"name"        description:"通行密钥名称"`
+	Aaguid      string      `json:"aaguid"      description:"认证器型号标识"`
+	Transports  []string    `json:"transports"  description:"传输方式"`
+	BackupState bool        `json:"backupState" description:"是否已同步备份"`
+	LastUsedAt  *gtime.Time `json:"lastUsedAt"  description:"最后使用时间"`
+	LastUsedIp  string      `json:"lastUsedIp"  description:"最后使用IP"`
+	CreatedAt   *gtime.Time `json:"createdAt"   description:"创建时间"`
+}
+
+// PasskeyCreationModel 通行密钥注册选项，publicKey 传给 navigator.credentials.create()
+type PasskeyCreationModel struct {
+	ChallengeId string                    `json:"challengeId" description:"挑战ID，完成注册时回传"`
+	PublicKey   *webauthn.CreationOptions `json:"publicKey"   description:"注册选项"`
+}
+
+// PasskeyRequestModel 通行密钥认证选项，publicKey 传给 navigator.credentials.get()
+type PasskeyRequestModel struct {
+	ChallengeId string                   `json:"challengeId" description:"挑战ID，完成认证时回传"`
+	PublicKey   *webauthn.RequestOptions `json:"publicKey"   description:"认证选项"`
+}
+
+// ConvertToPasskeyModel 将通行密钥实体转换为模型
+func ConvertToPasskeyModel(passkey *entity.UserPasskey) *PasskeyModel {
+	return &PasskeyModel{
+		Id:          passkey.Id,
+		Name:        passkey.Name,
+		Aaguid:      passkey.Aaguid,
+		Transports:  passkey.TransportList(),
+		BackupState: passkey.BackupState == 1,
+		LastUsedAt:  passkey.LastUsedAt,
+		LastUsedIp:  passkey.LastUsedIp,
+		CreatedAt:   passkey.CreatedAt,
+	}
+}
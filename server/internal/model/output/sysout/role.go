@@ -17,6 +17,7 @@ type RoleListModel struct {
 // RoleModel 角色基础响应模型
 type RoleModel struct {
 	Id            int64       `json:"id" description:"主键ID"`
+	ParentId      int64       `json:"parentId" description:"上级角色ID"`
 	Name          string      `json:"name" description:"角色名称"`
 	Code          string      `json:"code" description:"角色编码"`
 	Description   string      `json:"description" description:"角色描述"`
@@ -61,15 +62,29 @@ type RoleOptionModel struct {
 	IsBuiltIn bool   `json:"isBuiltIn" description:"是否内置角色"`
 }
 
-// RolePermissionModel 角色权限详情模型
+// RolePermissionModel 角色权限详情模型，直接分配和从上级角色继承的权限分开返回
 type RolePermissionModel struct {
-	RoleId      int64    `json:"roleId" description:"角色ID"`
-	RoleName    string   `json:"roleName" description:"角色名称"`
-	RoleCode    string   `json:"roleCode" description:"角色编码"`
-	DataScope   int      `json:"dataScope" description:"数据权限范围"`
-	MenuIds     []int64  `json:"menuIds" description:"菜单ID列表"`
-	Permissions []string `json:"permissions" description:"权限标识列表"`
-	Features    []string `json:"features" description:"功能特性列表"`
+	RoleId               int64              `json:"roleId" description:"角色ID"`
+	RoleName             string             `json:"roleName" description:"角色名称"`
+	RoleCode             string             `json:"roleCode" description:"角色编码"`
+	DataScope            int                `json:"dataScope" description:"数据权限范围"`
+	EffectiveDataScope   int                `json:"effectiveDataScope" description:"生效的数据权限范围，取继承链中范围最大的"`
+	MenuIds              []int64            `json:"menuIds" description:"直接分配的菜单ID列表"`
+	Permissions          []string           `json:"permissions" description:"直接分配的权限标识列表"`
+	InheritedMenuIds     []int64            `json:"inheritedMenuIds" description:"从上级角色继承的菜单ID列表"`
+	InheritedPermissions []string           `json:"inheritedPermissions" description:"从上级角色继承的权限标识列表"`
+	Ancestors            []*RoleOptionModel `json:"ancestors" description:"上级角色链，从顶级角色到直属上级"`
+	Features             []string           `json:"features" description:"功能特性列表"`
+}
+
+// RoleDataScopeModel 角色数据权限响应模型
+type RoleDataScopeModel struct {
+	RoleId        int64   `json:"roleId" description:"角色ID"`
+	RoleName      string  `json:"roleName" description:"角色名称"`
+	RoleCode      string  `json:"roleCode" description:"角色编码"`
+	DataScope     int     `json:"dataScope" description:"数据权限范围"`
+	DataScopeName string  `json:"dataScopeName" description:"数据权限范围名称"`
+	DeptIds       []int64 `json:"deptIds" description:"自定义数据权限的部门ID列表"`
 }
 
 // DataScopeModel 数据权限范围选项模型
@@ -95,6 +110,7 @@ func ConvertToRoleModel(role *entity.Role) *RoleModel {
 
 	return &RoleModel{
 		Id:            role.Id,
+		ParentId:      role.ParentId,
 		Name:          role.Name,
 		Code:          role.Code,
 		Description:   role.Description,
@@ -151,13 +167,17 @@ func ConvertToRolePermissionModel(role *entity.Role, menuIds []int64, permission
 	features := generateRoleFeatures(role)
 
 	return &RolePermissionModel{
-		RoleId:      role.Id,
-		RoleName:    role.Name,
-		RoleCode:    role.Code,
-		DataScope:   role.DataScope,
-		MenuIds:     menuIds,
-		Permissions: permissions,
-		Features:    features,
+		RoleId:               role.Id,
+		RoleName:             role.Name,
+		RoleCode:             role.Code,
+		DataScope:            role.DataScope,
+		EffectiveDataScope:   role.DataScope,
+		MenuIds:              menuIds,
+		Permissions:          permissions,
+		InheritedMenuIds:     []int64{},
+		InheritedPermissions: []string{},
+		Ancestors:            []*RoleOptionModel{},
+		Features:             features,
 	}
 }
 
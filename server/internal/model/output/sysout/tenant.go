@@ -87,3 +87,23 @@ func FormatStorageSize(bytes int64) string {
 	}
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
+
+// InviteCodeModel 注册邀请码模型
+type InviteCodeModel struct {
+	Id        int64       `json:"id"`        // 主键ID
+	TenantId  uint64      `json:"tenantId"`  // 租户ID
+	Code      string      `json:"code"`      // 邀请码
+	MaxUses   int         `json:"maxUses"`   // 最大使用次数
+	UsedCount int         `json:"usedCount"` // 已使用次数
+	ExpiresAt *gtime.Time `json:"expiresAt"` // 过期时间
+	Remark    string      `json:"remark"`    // 备注
+	CreatedAt *gtime.Time `json:"createdAt"` // 创建时间
+}
+
+// InviteCodeListModel 注册邀请码列表模型
+type InviteCodeListModel struct {
+	List     []*InviteCodeModel `json:"list"`     // 邀请码列表
+	Total    int64              `json:"total"`    // 总数
+	Page     int                `json:"page"`     // 当前页码
+	PageSize int                `json:"pageSize"` // 每页数量
+}
@@ -83,6 +83,92 @@ type LoginTokenModel struct {
 	UserInfo     *UserModel `json:"userInfo"     description:"用户信息"`
 	Permissions  []string   `json:"permissions"  description:"权限列表"`
 	MenuIds      []int64    `json:"menuIds"      description:"菜单ID列表"`
+
+	// 密码已超过租户策略的最长使用天数，修改密码前只能访问修改密码等有限接口
+	MustChangePassword bool `json:"mustChangePassword,omitempty" description:"是否必须修改密码"`
+
+	// 需要二次验证时仅返回以下字段，需调用 /login/2fa 或 /login/2fa/passkey 换取访问令牌
+	TwoFactorRequired bool     `json:"twoFactorRequired,omitempty" description:"是否需要双因子认证"`
+	ChallengeToken    string   `json:"challengeToken,omitempty"    description:"双因子认证挑战令牌"`
+	SecondFactors     []string `json:"secondFactors,omitempty"     description:"可用的二次验证方式：totp、passkey"`
+}
+
+// TwoFactorEnrollModel 双因子认证绑定信息
+type TwoFactorEnrollModel struct {
+	Secret     string `json:"secret"     description:"密钥（base32，可手动输入验证器）"`
+	OtpauthUrl string `json:"otpauthUrl" description:"otpauth://地址"`
+	QrCode     string `json:"qrCode"     description:"二维码图片（base64）"`
+}
+
+// TwoFactorRecoveryCodesModel 双因子认证恢复码，仅在生成时返回一次
+type TwoFactorRecoveryCodesModel struct {
+	Codes []string `json:"codes" description:"恢复码列表"`
+}
+
+// ImpersonateModel 模拟登录结果，模拟登录令牌不能刷新，过期后需重新发起
+type ImpersonateModel struct {
+	AccessToken string      `json:"accessToken" description:"访问令牌"`
+	TokenType   string      `json:"tokenType"   description:"令牌类型"`
+	ExpiresIn   int64       `json:"expiresIn"   description:"过期时间（秒）"`
+	ExpiresAt   *gtime.Time `json:"expiresAt"   description:"过期时间"`
+	UserInfo    *UserModel  `json:"userInfo"    description:"目标用户信息"`
+	Permissions []string    `json:"permissions" description:"权限列表"`
+	MenuIds     []int64     `json:"menuIds"     description:"菜单ID列表"`
+}
+
+// CaptchaModel 验证码
+type CaptchaModel struct {
+	CaptchaId    string `json:"captchaId"              description:"验证码ID"`
+	Type         string `json:"type"                   description:"验证码类型：digit=数字 arithmetic=算术 slider=滑块 audio=语音"`
+	CaptchaImage string `json:"captchaImage,omitempty" description:"验证码图片（base64），滑块验证码为背景图"`
+	CaptchaAudio string `json:"captchaAudio,omitempty" description:"语音验证码（base64 WAV）"`
+	PieceImage   string `json:"pieceImage,omitempty"   description:"滑块拼图块图片（base64）"`
+	PieceY       int    `json:"pieceY,omitempty"       description:"滑块拼图块纵向位置"`
+	PieceSize    int    `json:"pieceSize,omitempty"    description:"滑块拼图块边长"`
+	ImageWidth   int    `json:"imageWidth,omitempty"   description:"滑块背景图宽度"`
+}
+
+// UserRegisterModel 用户注册结果
+type UserRegisterModel struct {
+	UserId                int64  `json:"userId"                description:"用户ID"`
+	Username              string `json:"username"              description:"用户名"`
+	EmailVerificationSent bool   `json:"emailVerificationSent" description:"是否已发送邮箱验证链接"`
+}
+
+// OidcAuthorizeModel 单点登录授权地址
+type OidcAuthorizeModel struct {
+	AuthUrl string `json:"authUrl" description:"身份提供方授权地址"`
+	State   string `json:"state"   description:"授权状态，回调时原样提交"`
+}
+
+// SessionModel 登录会话模型
+type SessionModel struct {
+	SessionId  string      `json:"sessionId"  description:"会话ID"`
+	TenantId   uint64      `json:"tenantId"   description:"租户ID"`
+	Device     string      `json:"device"     description:"设备"`
+	Ip         string      `json:"ip"         description:"登录IP"`
+	UserAgent  string      `json:"userAgent"  description:"用户代理"`
+	LastSeenAt *gtime.Time `json:"lastSeenAt" description:"最后活跃时间"`
+	LastSeenIp string      `json:"lastSeenIp" description:"最后活跃IP"`
+	ExpiresAt  *gtime.Time `json:"expiresAt"  description:"过期时间"`
+	CreatedAt  *gtime.Time `json:"createdAt"  description:"登录时间"`
+	Current    bool        `json:"current"    description:"是否为当前会话"`
+}
+
+// ConvertToSessionModel 将会话实体转换为会话模型
+func ConvertToSessionModel(session *entity.UserSession, currentSessionId string) *SessionModel {
+	return &SessionModel{
+		SessionId:  session.SessionId,
+		TenantId:   session.TenantId,
+		Device:     session.Device,
+		Ip:         session.Ip,
+		UserAgent:  session.UserAgent,
+		LastSeenAt: session.LastSeenAt,
+		LastSeenIp: session.LastSeenIp,
+		ExpiresAt:  session.ExpiresAt,
+		CreatedAt:  session.CreatedAt,
+		Current:    currentSessionId != "" && session.SessionId == currentSessionId,
+	}
 }
 
 // ConvertToUserModel 将用户实体转换为用户模型
@@ -0,0 +1,25 @@
+package sysout
+
+// UserImportModel 批量导入用户结果
+type UserImportModel struct {
+	Mode    string                  `json:"mode"    description:"导入模式"`
+	Total   int                     `json:"total"   description:"数据行数（不含表头和空行）"`
+	Valid   int                     `json:"valid"   description:"校验通过行数"`
+	Invalid int                     `json:"invalid" description:"校验失败行数"`
+	Created int                     `json:"created" description:"实际创建用户数，存在失败行时不创建任何用户"`
+	Errors  []*UserImportErrorModel `json:"errors"  description:"校验失败的行"`
+}
+
+// UserImportErrorModel 导入失败的行
+type UserImportErrorModel struct {
+	Row      int      `json:"row"      description:"表格中的行号，从1开始"`
+	Username string   `json:"username" description:"用户名"`
+	Messages []string `json:"messages" description:"错误信息"`
+}
+
+// UserExportModel 用户导出文件
+type UserExportModel struct {
+	Filename    string `json:"filename"    description:"文件名"`
+	ContentType string `json:"contentType" description:"MIME类型"`
+	Content     []byte `json:"-"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"client-app/internal/model/entity"
+	"client-app/utility/permission"
+)
+
+// PermSnapshot 用户权限快照，鉴权时代替每次请求查询用户和权限
+// 角色、菜单、部门或用户状态变化时失效，Version 与用户的权限版本一致
+type PermSnapshot struct {
+	Version     int64        `json:"version"     description:"权限版本"`
+	User        *entity.User `json:"user"        description:"用户信息，不含密码等敏感字段"`
+	Permissions []string     `json:"permissions" description:"权限标识列表"`
+	DeptType    string       `json:"deptType"    description:"所属部门类型"`
+	DeptTree    []int64      `json:"deptTree"    description:"所属部门的上级部门ID链，从顶级部门到直属上级"`
+}
+
+// HasPermission 判断快照是否包含指定权限，支持 role:* 等通配权限
+func (p *PermSnapshot) HasPermission(required string) bool {
+	return permission.MatchAny(p.Permissions, required)
+}
@@ -17,22 +17,39 @@ import (
 
 // Api 前台路由
 func Api(ctx context.Context, group *ghttp.RouterGroup) {
+	// 标准发现接口，不带路由前缀，供网关和其他服务获取JWT公钥
+	group.Bind(
+		api.WellKnown,
+	)
+
 	group.Group(simple.RouterPrefix(ctx, consts.AppApi), func(group *ghttp.RouterGroup) {
 		// 不需要认证的公开接口
 		group.Bind(
-			api.User, // 用户认证接口
+			api.User,   // 用户认证接口
+			api.Crypto, // 传输加密公钥
 		)
 
-		// API 签名验证
-		//group.Middleware(service.Middleware().ApiVerify)
-		group.Bind()
+		// 用户自助注册，根据域名或 X-Tenant-Id 确定目标租户
+		group.Group("/", func(group *ghttp.RouterGroup) {
+			group.Middleware(service.Middleware().TenantFilter)
+			group.Bind(
+				api.Register,
+			)
+		})
+
+		// API 签名验证，apiVerify.enabled 开启后对服务端调用生效
+		group.Middleware(service.Middleware().ApiVerify)
 
 		// 需要认证的受保护接口
 		group.Middleware(service.Middleware().ApiAuth)
 		group.Bind(
-			api.Role, // 角色管理接口
+			api.Account,    // 当前用户账号自助接口
+			api.UserManage, // 用户管理接口
+			api.Role,       // 角色管理接口
 			api.Menu,
 			api.NewTenant(),
+			api.Dept,   // 部门管理接口
+			api.ApiKey, // 个人API密钥
 		)
 	})
 }
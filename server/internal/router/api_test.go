@@ -0,0 +1,91 @@
+// Package router_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package router_test
+
+import (
+	"client-app/internal/global"
+	_ "client-app/internal/logic"
+	"client-app/internal/router"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/test/gtest"
+	"github.com/gogf/gf/v2/util/guid"
+)
+
+func startApiServer(t *testing.T) *ghttp.Server {
+	s := g.Server(guid.S())
+	s.SetPort(0)
+	s.SetDumpRouterMap(false)
+	s.Group("/", func(group *ghttp.RouterGroup) {
+		router.Api(context.Background(), group)
+	})
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	return s
+}
+
+// 用户管理接口在 ApiAuth 之后绑定，未登录时直接拒绝
+func TestApi_UserManageRequiresAuth(t *testing.T) {
+	s := startApiServer(t)
+	defer s.Shutdown()
+
+	gtest.C(t, func(t *gtest.T) {
+		client := g.Client()
+		client.SetPrefix(fmt.Sprintf("http://127.0.0.1:%d", s.GetListenedPort()))
+
+		for _, path := range []string{
+			"/api/user/list",
+			"/api/user/export",
+			"/api/user/sessions?userId=1",
+			"/api/passkeys",
+			"/api/sessions",
+		} {
+			content := client.GetContent(context.Background(), path)
+			t.Assert(strings.Contains(content, `"code":401`), true)
+		}
+		for _, path := range []string{
+			"/api/user/reset-password",
+			"/api/user/revoke-sessions",
+			"/api/user/impersonate",
+			"/api/2fa/enroll",
+			"/api/change-password",
+		} {
+			content := client.PostContent(context.Background(), path, g.Map{})
+			t.Assert(strings.Contains(content, `"code":401`), true)
+		}
+	})
+}
+
+// 声明了权限标识的接口都要经过 ApiAuth，否则权限标识不会生效
+func TestApi_PermissionRoutesBehindApiAuth(t *testing.T) {
+	s := startApiServer(t)
+	defer s.Shutdown()
+
+	gtest.C(t, func(t *gtest.T) {
+		routes := global.LoadServerRoutes(s)
+
+		userList, ok := routes["GET /api/user/list"]
+		t.Assert(ok, true)
+		t.Assert(userList.Permission, "user:list")
+
+		for key, route := range routes {
+			if route.Permission == "" {
+				continue
+			}
+			if !strings.Contains(route.Middleware, "ApiAuth") {
+				t.Errorf("接口声明了权限标识但未经过ApiAuth: %s", key)
+			}
+		}
+	})
+}
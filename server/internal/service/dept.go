@@ -0,0 +1,53 @@
+package service
+
+import (
+	"client-app/internal/model/entity"
+	"client-app/internal/model/input/sysin"
+	"client-app/internal/model/output/sysout"
+	"context"
+)
+
+// IDept 部门服务接口
+type IDept interface {
+	// GetDeptTree 获取当前租户的部门树
+	GetDeptTree(ctx context.Context, in *sysin.DeptTreeInp) ([]*sysout.DeptTreeModel, error)
+
+	// GetDeptDetail 获取部门详情，包含上级部门
+	GetDeptDetail(ctx context.Context, in *sysin.DeptDetailInp) (*sysout.DeptDetailModel, error)
+
+	// CreateDept 创建部门
+	CreateDept(ctx context.Context, in *sysin.CreateDeptInp) (*sysout.DeptModel, error)
+
+	// UpdateDept 更新部门
+	UpdateDept(ctx context.Context, in *sysin.UpdateDeptInp) (*sysout.DeptModel, error)
+
+	// MoveDept 移动部门到新的上级部门，下级部门随之移动
+	MoveDept(ctx context.Context, in *sysin.MoveDeptInp) error
+
+	// UpdateDeptStatus 启用或禁用部门
+	UpdateDeptStatus(ctx context.Context, in *sysin.UpdateDeptStatusInp) error
+
+	// DeleteDept 删除部门，存在下级部门或用户时不允许删除
+	DeleteDept(ctx context.Context, in *sysin.DeleteDeptInp) error
+
+	// MoveDeptUsers 调整用户所属部门
+	MoveDeptUsers(ctx context.Context, in *sysin.MoveDeptUsersInp) error
+
+	// GetDept 获取租户内的部门，不存在时返回nil
+	GetDept(ctx context.Context, tenantId, deptId int64) (*entity.Dept, error)
+}
+
+var localDept IDept
+
+// Dept 获取部门服务实例
+func Dept() IDept {
+	if localDept == nil {
+		panic("implement not found for interface IDept, forgot register?")
+	}
+	return localDept
+}
+
+// RegisterDept 注册部门服务实现
+func RegisterDept(i IDept) {
+	localDept = i
+}
@@ -17,6 +17,9 @@ type (
 		// ApiAuth API鉴权中间件
 		ApiAuth(r *ghttp.Request)
 
+		// ApiVerify API请求签名验证中间件
+		ApiVerify(r *ghttp.Request)
+
 		// TenantFilter 租户过滤中间件
 		TenantFilter(r *ghttp.Request)
 		
@@ -30,6 +33,9 @@ type (
 		// DemoLimit 演示系统操作限制
 		//DemoLimit(r *ghttp.Request)
 
+		// CheckRoutePermissions 启动时检查需要鉴权的接口是否都声明了权限标识
+		CheckRoutePermissions(ctx context.Context, server *ghttp.Server)
+
 		// IsExceptAuth 是否是不需要验证权限的路由地址
 		IsExceptAuth(ctx context.Context, appName string, path string) bool
 		// IsExceptLogin 是否是不需要登录的路由地址
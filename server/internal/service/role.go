@@ -21,6 +21,8 @@ type IRole interface {
 	GetRoleMenus(ctx context.Context, in *sysin.RoleMenuInp) (res *sysout.RoleMenuModel, err error)
 	UpdateRoleMenus(ctx context.Context, in *sysin.UpdateRoleMenuInp) (err error)
 	GetRolePermissions(ctx context.Context, in *sysin.RolePermissionInp) (res *sysout.RolePermissionModel, err error)
+	GetRoleDataScope(ctx context.Context, in *sysin.RoleDataScopeInp) (res *sysout.RoleDataScopeModel, err error)
+	UpdateRoleDataScope(ctx context.Context, in *sysin.UpdateRoleDataScopeInp) (err error)
 
 	// 角色选项和统计
 	GetRoleOptions(ctx context.Context, in *sysin.RoleOptionInp) (res []*sysout.RoleOptionModel, err error)
@@ -29,6 +31,7 @@ type IRole interface {
 
 	// 用户角色关联操作
 	AssignUserRoles(ctx context.Context, userId int64, roleIds []int64, assignedBy int64) (err error)
+	ReplaceUserRoles(ctx context.Context, userId int64, roleIds []int64, assignedBy int64) (err error)
 	RemoveUserRoles(ctx context.Context, userId int64, roleIds []int64) (err error)
 	GetUserRoles(ctx context.Context, userId int64) (res []*sysout.RoleModel, err error)
 	SetUserPrimaryRole(ctx context.Context, userId int64, roleId int64) (err error)
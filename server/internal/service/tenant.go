@@ -40,6 +40,12 @@ type ITenant interface {
 	
 	// ValidateTenantAccess 验证租户访问权限
 	ValidateTenantAccess(ctx context.Context, tenantId uint64) error
+	
+	// CreateInviteCode 创建注册邀请码
+	CreateInviteCode(ctx context.Context, in *sysin.InviteCodeCreateInp) (*sysout.InviteCodeModel, error)
+	
+	// GetInviteCodeList 获取注册邀请码列表
+	GetInviteCodeList(ctx context.Context, in *sysin.InviteCodeListInp) (*sysout.InviteCodeListModel, error)
 }
 
 var localTenant ITenant
@@ -26,10 +26,10 @@ type (
 		RefreshToken(ctx context.Context, refreshToken string) (res *TokenInfo, err error)
 		
 		// ChangePassword 修改密码
-		ChangePassword(ctx context.Context, userId int64, oldPassword, newPassword string) error
+		ChangePassword(ctx context.Context, userId int64, oldPassword, newPassword, confirmPassword string) error
 		
-		// GenerateCaptcha 生成验证码
-		GenerateCaptcha(ctx context.Context) (captchaId, captchaImage string, err error)
+		// GenerateCaptcha 生成验证码，验证码类型按租户配置选择，请求语音验证码时优先使用语音
+		GenerateCaptcha(ctx context.Context, in *sysin.CaptchaInp) (res *sysout.CaptchaModel, err error)
 		
 		// VerifyCaptcha 验证验证码
 		VerifyCaptcha(ctx context.Context, captchaId, captcha string) error
@@ -39,6 +39,114 @@ type (
 		
 		// ValidateUser 验证用户密码
 		ValidateUser(ctx context.Context, username, password string) (user *sysout.UserModel, err error)
+		
+		// LoginTwoFactor 使用挑战令牌和动态验证码完成登录
+		LoginTwoFactor(ctx context.Context, in *sysin.UserLoginTwoFactorInp) (res *sysout.LoginTokenModel, err error)
+		
+		// EnrollTwoFactor 生成双因子认证密钥
+		EnrollTwoFactor(ctx context.Context, userId int64) (res *sysout.TwoFactorEnrollModel, err error)
+		
+		// ConfirmTwoFactor 确认启用双因子认证
+		ConfirmTwoFactor(ctx context.Context, userId int64, in *sysin.TwoFactorConfirmInp) (res *sysout.TwoFactorRecoveryCodesModel, err error)
+		
+		// DisableTwoFactor 关闭双因子认证
+		DisableTwoFactor(ctx context.Context, userId int64, in *sysin.TwoFactorDisableInp) error
+		
+		// RegenerateRecoveryCodes 重新生成恢复码
+		RegenerateRecoveryCodes(ctx context.Context, userId int64, in *sysin.TwoFactorRecoveryCodesInp) (res *sysout.TwoFactorRecoveryCodesModel, err error)
+		
+		// ForgotPassword 申请找回密码，发送重置链接
+		ForgotPassword(ctx context.Context, in *sysin.ForgotPasswordInp) error
+		
+		// ResetPasswordByToken 通过重置令牌设置新密码
+		ResetPasswordByToken(ctx context.Context, in *sysin.ResetPasswordByTokenInp) error
+		
+		// RevokeUserSessions 管理员强制用户下线
+		RevokeUserSessions(ctx context.Context, userId int64) error
+		// RevokeTenantSessions 租户被禁用、锁定或删除时，撤销租户下所有用户的刷新令牌、登录会话和访问令牌
+		RevokeTenantSessions(ctx context.Context, tenantId uint64) error
+		// Impersonate 系统管理员模拟登录租户用户，签发不可刷新的短期令牌，令牌同时携带操作人和目标用户
+		Impersonate(ctx context.Context, in *sysin.ImpersonateInp) (res *sysout.ImpersonateModel, err error)
+		// StopImpersonate 结束模拟登录，撤销当前模拟登录令牌
+		StopImpersonate(ctx context.Context) error
+		
+		// Register 用户自助注册
+		Register(ctx context.Context, in *sysin.UserRegisterInp) (res *sysout.UserRegisterModel, err error)
+		
+		// VerifyEmail 确认邮箱验证链接
+		VerifyEmail(ctx context.Context, in *sysin.VerifyEmailInp) error
+		
+		// OidcAuthorize 发起单点登录，返回身份提供方授权地址
+		OidcAuthorize(ctx context.Context, in *sysin.OidcAuthorizeInp) (res *sysout.OidcAuthorizeModel, err error)
+		
+		// OidcCallback 单点登录回调，验证通过后签发登录令牌
+		OidcCallback(ctx context.Context, in *sysin.OidcCallbackInp) (res *sysout.LoginTokenModel, err error)
+		
+		// ListMySessions 获取本人的登录会话
+		ListMySessions(ctx context.Context) ([]*sysout.SessionModel, error)
+		
+		// RevokeMySession 撤销本人的某个登录会话
+		RevokeMySession(ctx context.Context, in *sysin.SessionRevokeInp) error
+		
+		// ListUserSessions 管理员获取用户的登录会话
+		ListUserSessions(ctx context.Context, in *sysin.UserSessionListInp) ([]*sysout.SessionModel, error)
+		
+		// RevokeUserSession 管理员撤销用户的某个登录会话
+		RevokeUserSession(ctx context.Context, in *sysin.UserSessionRevokeInp) error
+		
+		// PasskeyRegisterBegin 获取通行密钥注册选项
+		PasskeyRegisterBegin(ctx context.Context) (res *sysout.PasskeyCreationModel, err error)
+		
+		// PasskeyRegisterFinish 校验浏览器返回的凭证并保存通行密钥
+		PasskeyRegisterFinish(ctx context.Context, in *sysin.PasskeyRegisterFinishInp) (res *sysout.PasskeyModel, err error)
+		
+		// ListPasskeys 获取本人的通行密钥
+		ListPasskeys(ctx context.Context) ([]*sysout.PasskeyModel, error)
+		
+		// DeletePasskey 删除本人的通行密钥
+		DeletePasskey(ctx context.Context, in *sysin.PasskeyDeleteInp) error
+		
+		// PasskeyLoginBegin 获取通行密钥登录选项
+		PasskeyLoginBegin(ctx context.Context, in *sysin.PasskeyLoginBeginInp) (res *sysout.PasskeyRequestModel, err error)
+		
+		// PasskeyLoginFinish 校验通行密钥签名并签发登录令牌
+		PasskeyLoginFinish(ctx context.Context, in *sysin.PasskeyLoginFinishInp) (res *sysout.LoginTokenModel, err error)
+		
+		// PasskeyTwoFactorBegin 获取通行密钥二次验证选项
+		PasskeyTwoFactorBegin(ctx context.Context, in *sysin.PasskeyTwoFactorBeginInp) (res *sysout.PasskeyRequestModel, err error)
+		
+		// PasskeyTwoFactorFinish 使用通行密钥完成二次验证并签发登录令牌
+		PasskeyTwoFactorFinish(ctx context.Context, in *sysin.PasskeyTwoFactorFinishInp) (res *sysout.LoginTokenModel, err error)
+		
+		// GetUserList 获取当前租户的用户列表
+		GetUserList(ctx context.Context, in *sysin.UserListInp) (res *sysout.UserListModel, err error)
+		
+		// GetUserDetail 获取当前租户的用户详情，包含角色和权限
+		GetUserDetail(ctx context.Context, in *sysin.UserDetailInp) (res *sysout.UserDetailModel, err error)
+		
+		// CreateUser 在当前租户下创建用户，受租户最大用户数限制
+		CreateUser(ctx context.Context, in *sysin.CreateUserInp) (res *sysout.UserModel, err error)
+		
+		// UpdateUser 更新当前租户的用户资料和角色
+		UpdateUser(ctx context.Context, in *sysin.UpdateUserInp) (res *sysout.UserModel, err error)
+		
+		// UpdateUserStatus 修改当前租户的用户状态，锁定或禁用后立即撤销其登录凭证
+		UpdateUserStatus(ctx context.Context, in *sysin.UpdateUserStatusInp) error
+		
+		// ResetUserPassword 管理员重置当前租户用户的密码
+		ResetUserPassword(ctx context.Context, in *sysin.ResetPasswordInp) error
+		
+		// DeleteUser 软删除当前租户的用户
+		DeleteUser(ctx context.Context, in *sysin.DeleteUserInp) error
+		
+		// AssignUserRoles 重新分配当前租户用户的角色
+		AssignUserRoles(ctx context.Context, in *sysin.AssignUserRoleInp) error
+		
+		// ImportUsers 从CSV或XLSX批量导入当前租户的用户，返回逐行校验报告
+		ImportUsers(ctx context.Context, in *sysin.ImportUserInp) (res *sysout.UserImportModel, err error)
+		
+		// ExportUsers 按列表筛选条件导出当前租户的用户
+		ExportUsers(ctx context.Context, in *sysin.ExportUserInp) (res *sysout.UserExportModel, err error)
 	}
 )
 
@@ -0,0 +1,20 @@
+-- 个人访问令牌表
+CREATE TABLE IF NOT EXISTS `sys_api_keys` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `tenant_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '租户ID',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '所属用户ID',
+  `name` varchar(64) NOT NULL COMMENT '密钥名称',
+  `prefix` varchar(16) NOT NULL COMMENT '密钥前缀，用于识别密钥',
+  `key_hash` char(64) NOT NULL COMMENT '密钥SHA256哈希',
+  `scopes` text NOT NULL COMMENT '权限范围，多个以逗号分隔',
+  `allowed_ips` varchar(1024) DEFAULT NULL COMMENT '允许访问的IP或网段，多个以逗号分隔，为空不限制',
+  `expires_at` datetime DEFAULT NULL COMMENT '过期时间，为空表示不过期',
+  `last_used_at` datetime DEFAULT NULL COMMENT '最后使用时间',
+  `last_used_ip` varchar(45) DEFAULT NULL COMMENT '最后使用IP',
+  `revoked_at` datetime DEFAULT NULL COMMENT '撤销时间',
+  `created_at` datetime NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_key_hash` (`key_hash`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_tenant_id` (`tenant_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='个人访问令牌表';
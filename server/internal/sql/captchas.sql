@@ -0,0 +1,10 @@
+-- 验证码表，集群部署时各节点共享验证码状态
+CREATE TABLE IF NOT EXISTS `sys_captchas` (
+  `id` char(32) NOT NULL COMMENT '验证码ID',
+  `type` varchar(16) NOT NULL COMMENT '验证码类型：digit=数字 arithmetic=算术 slider=滑块 audio=语音',
+  `answer` varchar(32) NOT NULL COMMENT '正确答案',
+  `expires_at` datetime NOT NULL COMMENT '过期时间',
+  `created_at` datetime NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='验证码表';
@@ -0,0 +1,24 @@
+-- 部门表
+CREATE TABLE IF NOT EXISTS `sys_depts` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `tenant_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '租户ID',
+  `parent_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '上级部门ID，0表示顶级部门',
+  `ancestors` varchar(1000) NOT NULL DEFAULT '' COMMENT '上级部门ID链，从顶级部门到直属上级，逗号分隔',
+  `name` varchar(50) NOT NULL COMMENT '部门名称',
+  `code` varchar(50) NOT NULL COMMENT '部门编码，租户内唯一',
+  `type` varchar(20) NOT NULL DEFAULT 'dept' COMMENT '部门类型：company=公司 dept=部门 team=小组',
+  `leader_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '负责人用户ID',
+  `phone` varchar(20) DEFAULT NULL COMMENT '联系电话',
+  `email` varchar(100) DEFAULT NULL COMMENT '联系邮箱',
+  `sort` int(11) NOT NULL DEFAULT '0' COMMENT '排序号，数字越小越靠前',
+  `status` tinyint(4) NOT NULL DEFAULT '1' COMMENT '状态：1=启用 0=禁用',
+  `remark` varchar(500) DEFAULT NULL COMMENT '备注说明',
+  `created_by` bigint(20) unsigned DEFAULT NULL COMMENT '创建人ID',
+  `updated_by` bigint(20) unsigned DEFAULT NULL COMMENT '修改人ID',
+  `created_at` datetime NOT NULL COMMENT '创建时间',
+  `updated_at` datetime NOT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_tenant_code` (`tenant_id`, `code`),
+  KEY `idx_tenant_parent` (`tenant_id`, `parent_id`),
+  KEY `idx_leader_id` (`leader_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='部门表';
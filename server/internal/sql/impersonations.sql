@@ -0,0 +1,19 @@
+-- 模拟登录记录表，系统管理员以租户用户身份登录的审计记录
+CREATE TABLE IF NOT EXISTS `sys_impersonations` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `jti` varchar(64) NOT NULL COMMENT '模拟登录令牌ID',
+  `impersonator_id` bigint(20) unsigned NOT NULL COMMENT '操作人用户ID',
+  `impersonator_tenant_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '操作人租户ID',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '目标用户ID',
+  `tenant_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '目标用户租户ID',
+  `reason` varchar(255) NOT NULL COMMENT '模拟登录原因',
+  `ip` varchar(45) DEFAULT NULL COMMENT '操作IP',
+  `started_at` datetime NOT NULL COMMENT '开始时间',
+  `expires_at` datetime NOT NULL COMMENT '令牌过期时间',
+  `ended_at` datetime DEFAULT NULL COMMENT '结束时间',
+  `end_reason` varchar(32) DEFAULT NULL COMMENT '结束原因：stop=主动结束 logout=退出登录',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_jti` (`jti`),
+  KEY `idx_impersonator_id` (`impersonator_id`),
+  KEY `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='模拟登录记录表';
@@ -0,0 +1,8 @@
+-- 登录失败计数表，集群部署时各节点共享失败次数和临时锁定状态
+CREATE TABLE IF NOT EXISTS `sys_login_counters` (
+  `counter_key` varchar(191) NOT NULL COMMENT '计数键：账号/IP失败次数或账号锁定标记',
+  `count` int(11) unsigned NOT NULL DEFAULT '0' COMMENT '窗口内的失败次数',
+  `expires_at` datetime NOT NULL COMMENT '过期时间，窗口内有新的失败时顺延',
+  PRIMARY KEY (`counter_key`),
+  KEY `idx_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='登录失败计数表';
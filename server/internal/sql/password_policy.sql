@@ -0,0 +1,17 @@
+-- 密码策略：密码最长使用天数和历史密码
+
+-- 密码修改时间，为空时不判断过期
+ALTER TABLE `sys_users` ADD COLUMN `password_changed_at` datetime DEFAULT NULL COMMENT '密码修改时间' AFTER `password_reset_expires`;
+-- 已有账号从创建时间开始计算，单点登录创建的账号不使用本地密码，不计算过期
+UPDATE `sys_users` SET `password_changed_at` = `created_at`
+WHERE `password_changed_at` IS NULL AND `id` NOT IN (SELECT `user_id` FROM `sys_user_identities`);
+
+-- 历史密码表
+CREATE TABLE IF NOT EXISTS `sys_password_history` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '用户ID',
+  `password` varchar(255) NOT NULL COMMENT '密码哈希',
+  `created_at` datetime NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='历史密码表';
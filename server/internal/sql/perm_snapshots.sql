@@ -0,0 +1,13 @@
+-- 用户权限版本，角色、菜单权限或用户状态变化时递增，签发令牌时写入 permVersion 声明
+ALTER TABLE `sys_users` ADD COLUMN `perm_version` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '权限版本' AFTER `status`;
+
+-- 用户权限快照表，permission.cache.store=db 时使用，集群节点共享
+CREATE TABLE IF NOT EXISTS `sys_perm_snapshots` (
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '用户ID',
+  `version` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '权限版本',
+  `data` mediumtext NOT NULL COMMENT '快照内容，JSON格式',
+  `expires_at` datetime NOT NULL COMMENT '过期时间',
+  `updated_at` datetime NOT NULL COMMENT '更新时间',
+  PRIMARY KEY (`user_id`),
+  KEY `idx_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户权限快照表';
@@ -0,0 +1,20 @@
+-- 刷新令牌表
+CREATE TABLE IF NOT EXISTS `sys_refresh_tokens` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '用户ID',
+  `tenant_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '租户ID',
+  `family_id` char(32) NOT NULL COMMENT '令牌族ID，同一次登录轮换出的令牌共用',
+  `parent_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '轮换前的令牌ID',
+  `token_hash` char(64) NOT NULL COMMENT '令牌SHA256哈希',
+  `expires_at` datetime NOT NULL COMMENT '过期时间',
+  `used_at` datetime DEFAULT NULL COMMENT '轮换时间',
+  `revoked_at` datetime DEFAULT NULL COMMENT '撤销时间',
+  `revoke_reason` varchar(32) DEFAULT NULL COMMENT '撤销原因：reuse=重放 logout=退出 password_reset=重置密码 admin=管理员下线',
+  `created_ip` varchar(45) DEFAULT NULL COMMENT '签发IP',
+  `created_at` datetime NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_token_hash` (`token_hash`),
+  KEY `idx_family_id` (`family_id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='刷新令牌表';
@@ -0,0 +1,12 @@
+-- 角色部门关联表，自定义数据权限（data_scope=5）的角色可访问的部门
+CREATE TABLE IF NOT EXISTS `sys_role_depts` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `tenant_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '租户ID',
+  `role_id` bigint(20) unsigned NOT NULL COMMENT '角色ID',
+  `dept_id` bigint(20) unsigned NOT NULL COMMENT '部门ID',
+  `created_at` datetime NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_role_dept` (`role_id`, `dept_id`),
+  KEY `idx_tenant_id` (`tenant_id`),
+  KEY `idx_dept_id` (`dept_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='角色部门关联表';
@@ -0,0 +1,4 @@
+-- 角色继承：下级角色继承上级角色的菜单权限，数据权限取继承链中范围最大的
+ALTER TABLE `sys_roles` ADD COLUMN `parent_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '上级角色ID，0表示不继承' AFTER `tenant_id`;
+ALTER TABLE `sys_roles` ADD COLUMN `ancestors` varchar(500) NOT NULL DEFAULT '' COMMENT '上级角色ID链，从顶级角色到直属上级，逗号分隔' AFTER `parent_id`;
+ALTER TABLE `sys_roles` ADD INDEX `idx_tenant_parent` (`tenant_id`, `parent_id`);
@@ -0,0 +1,15 @@
+-- 访问令牌撤销表
+CREATE TABLE IF NOT EXISTS `sys_token_revocations` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `type` varchar(16) NOT NULL COMMENT '撤销类型：jti=单个令牌 session=会话 user=用户 tenant=租户',
+  `subject` varchar(64) NOT NULL COMMENT '撤销对象：令牌jti/会话ID/用户ID/租户ID',
+  `revoked_at` datetime(3) NOT NULL COMMENT '撤销时间（毫秒），此前签发的令牌均失效',
+  `expires_at` datetime NOT NULL COMMENT '记录过期时间，过期后可清理',
+  `created_at` datetime NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_type_subject` (`type`,`subject`),
+  KEY `idx_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='访问令牌撤销表';
+
+-- 已建表的环境升级：撤销时间保留毫秒
+ALTER TABLE `sys_token_revocations` MODIFY COLUMN `revoked_at` datetime(3) NOT NULL COMMENT '撤销时间（毫秒），此前签发的令牌均失效';
@@ -0,0 +1,11 @@
+-- 传输加密密钥表，集群部署时各节点共享密钥，用于解密客户端提交的密码
+-- 密钥定期轮换，过期记录会被自动清理
+CREATE TABLE IF NOT EXISTS `sys_transport_keys` (
+  `kid` char(16) NOT NULL COMMENT '密钥ID',
+  `alg` varchar(16) NOT NULL COMMENT '加密算法：RSA-OAEP-256 X25519',
+  `private_key` text NOT NULL COMMENT '私钥（base64编码的PKCS#8）',
+  `created_at` datetime NOT NULL COMMENT '创建时间',
+  `expires_at` datetime NOT NULL COMMENT '过期时间，过期后不能再解密',
+  PRIMARY KEY (`kid`),
+  KEY `idx_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='传输加密密钥表';
@@ -0,0 +1,14 @@
+-- 外部身份关联表，用于OIDC单点登录
+CREATE TABLE IF NOT EXISTS `sys_user_identities` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `tenant_id` bigint(20) unsigned NOT NULL COMMENT '租户ID',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '用户ID',
+  `issuer` varchar(255) NOT NULL COMMENT '身份提供方',
+  `subject` varchar(255) NOT NULL COMMENT '外部用户标识',
+  `email` varchar(100) DEFAULT NULL COMMENT '外部邮箱',
+  `last_login_at` datetime DEFAULT NULL COMMENT '最后登录时间',
+  `created_at` datetime NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_tenant_issuer_subject` (`tenant_id`,`issuer`(191),`subject`(191)),
+  KEY `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='外部身份关联表';
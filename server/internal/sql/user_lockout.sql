@@ -0,0 +1,2 @@
+-- 登录失败锁定：临时锁定的账号到期后在下次登录时自动解锁
+ALTER TABLE `sys_users` ADD COLUMN `locked_until` datetime DEFAULT NULL COMMENT '自动解锁时间，管理员锁定时为空' AFTER `login_count`;
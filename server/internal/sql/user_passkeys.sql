@@ -0,0 +1,21 @@
+-- 用户通行密钥表
+CREATE TABLE IF NOT EXISTS `sys_user_passkeys` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `tenant_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '租户ID',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '所属用户ID',
+  `name` varchar(64) NOT NULL COMMENT '通行密钥名称',
+  `credential_id` varchar(1400) CHARACTER SET ascii COLLATE ascii_bin NOT NULL COMMENT '凭证ID（base64url）',
+  `public_key` text NOT NULL COMMENT 'COSE格式公钥（base64url）',
+  `alg` int(11) NOT NULL COMMENT '签名算法',
+  `sign_count` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '签名计数器',
+  `aaguid` char(36) NOT NULL DEFAULT '' COMMENT '认证器型号标识',
+  `transports` varchar(255) NOT NULL DEFAULT '' COMMENT '传输方式，多个以逗号分隔',
+  `backup_state` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否已同步备份',
+  `last_used_at` datetime DEFAULT NULL COMMENT '最后使用时间',
+  `last_used_ip` varchar(45) DEFAULT NULL COMMENT '最后使用IP',
+  `created_at` datetime NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_credential_id` (`credential_id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_tenant_id` (`tenant_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户通行密钥表';
@@ -0,0 +1,11 @@
+-- 双因子认证恢复码表
+CREATE TABLE IF NOT EXISTS `sys_user_recovery_codes` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '用户ID',
+  `code_hash` char(64) NOT NULL COMMENT '恢复码SHA256哈希',
+  `used_at` datetime DEFAULT NULL COMMENT '使用时间',
+  `created_at` datetime NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_user_code` (`user_id`, `code_hash`),
+  KEY `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='双因子认证恢复码表';
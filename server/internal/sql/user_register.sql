@@ -0,0 +1,21 @@
+-- 用户自助注册
+
+-- 邮箱验证令牌，只保存SHA256哈希
+ALTER TABLE `sys_users` ADD COLUMN `email_verify_token` char(64) DEFAULT NULL COMMENT '邮箱验证令牌哈希' AFTER `email_verified_at`;
+ALTER TABLE `sys_users` ADD COLUMN `email_verify_expires` datetime DEFAULT NULL COMMENT '邮箱验证过期时间' AFTER `email_verify_token`;
+ALTER TABLE `sys_users` ADD INDEX `idx_email_verify_token` (`email_verify_token`);
+
+-- 注册邀请码表
+CREATE TABLE IF NOT EXISTS `sys_invite_codes` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `tenant_id` bigint(20) unsigned NOT NULL COMMENT '租户ID',
+  `code` varchar(32) NOT NULL COMMENT '邀请码',
+  `max_uses` int(11) NOT NULL DEFAULT '1' COMMENT '最大使用次数',
+  `used_count` int(11) NOT NULL DEFAULT '0' COMMENT '已使用次数',
+  `expires_at` datetime DEFAULT NULL COMMENT '过期时间，为空表示不过期',
+  `remark` varchar(255) DEFAULT NULL COMMENT '备注',
+  `created_by` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '创建人ID',
+  `created_at` datetime NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_tenant_code` (`tenant_id`,`code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='注册邀请码表';
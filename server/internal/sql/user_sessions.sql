@@ -0,0 +1,20 @@
+-- 用户登录会话表
+CREATE TABLE IF NOT EXISTS `sys_user_sessions` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `session_id` char(32) NOT NULL COMMENT '会话ID，与刷新令牌族ID一致',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '用户ID',
+  `tenant_id` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '租户ID',
+  `device` varchar(64) DEFAULT NULL COMMENT '设备',
+  `ip` varchar(45) DEFAULT NULL COMMENT '登录IP',
+  `user_agent` varchar(512) DEFAULT NULL COMMENT '用户代理',
+  `last_seen_at` datetime DEFAULT NULL COMMENT '最后活跃时间',
+  `last_seen_ip` varchar(45) DEFAULT NULL COMMENT '最后活跃IP',
+  `expires_at` datetime NOT NULL COMMENT '过期时间，随刷新令牌轮换延长',
+  `revoked_at` datetime DEFAULT NULL COMMENT '撤销时间',
+  `revoke_reason` varchar(32) DEFAULT NULL COMMENT '撤销原因：reuse=重放 logout=退出 password_reset=重置密码 admin=管理员下线',
+  `created_at` datetime NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_session_id` (`session_id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_tenant_id` (`tenant_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户登录会话表';
@@ -0,0 +1,118 @@
+package captcha
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"strings"
+)
+
+const audioSampleRate = 8000
+
+// audioProvider 语音验证码，生成WAV音频
+// 不依赖语音素材，每位数字用提示音次数表示：数字几就响几声，0 为一声长音
+type audioProvider struct {
+	length int
+}
+
+func (p *audioProvider) Type() string {
+	return TypeAudio
+}
+
+func (p *audioProvider) Generate() (*Challenge, error) {
+	code := GenerateCode(p.length)
+	wav := GenerateAudio(code)
+	return &Challenge{
+		Type:   TypeAudio,
+		Answer: code,
+		Audio:  "data:audio/wav;base64," + base64.StdEncoding.EncodeToString(wav),
+	}, nil
+}
+
+func (p *audioProvider) Verify(answer, input string) bool {
+	return answer != "" && answer == strings.TrimSpace(input)
+}
+
+// GenerateAudio 生成数字验证码的WAV音频（8kHz 8位单声道）
+func GenerateAudio(code string) []byte {
+	var samples []byte
+
+	// 开头留出静音，避免播放器起播时截掉第一声
+	samples = appendSilence(samples, 0.5)
+	for _, char := range code {
+		digit := int(char - '0')
+		freq := float64(randInt(600, 900))
+		if digit == 0 {
+			samples = appendTone(samples, freq, 0.6)
+		} else {
+			for i := 0; i < digit; i++ {
+				samples = appendTone(samples, freq, 0.12)
+				samples = appendSilence(samples, 0.12)
+			}
+		}
+		samples = appendSilence(samples, 0.8)
+	}
+
+	// 叠加少量噪声，增加机器识别难度
+	for i := range samples {
+		noise := randInt(-6, 7)
+		v := int(samples[i]) + noise
+		if v < 0 {
+			v = 0
+		} else if v > 255 {
+			v = 255
+		}
+		samples[i] = byte(v)
+	}
+
+	return encodeWAV(samples)
+}
+
+// appendTone 追加正弦提示音，首尾做淡入淡出避免爆音
+func appendTone(samples []byte, freq, seconds float64) []byte {
+	n := int(seconds * audioSampleRate)
+	fade := n / 10
+	for i := 0; i < n; i++ {
+		amp := 90.0
+		if i < fade {
+			amp *= float64(i) / float64(fade)
+		} else if i > n-fade {
+			amp *= float64(n-i) / float64(fade)
+		}
+		v := 128 + amp*math.Sin(2*math.Pi*freq*float64(i)/audioSampleRate)
+		samples = append(samples, byte(v))
+	}
+	return samples
+}
+
+// appendSilence 追加静音
+func appendSilence(samples []byte, seconds float64) []byte {
+	n := int(seconds * audioSampleRate)
+	for i := 0; i < n; i++ {
+		samples = append(samples, 128)
+	}
+	return samples
+}
+
+// encodeWAV 按RIFF格式封装PCM数据
+func encodeWAV(samples []byte) []byte {
+	var buf bytes.Buffer
+	size := uint32(len(samples))
+
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, 36+size)
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(16))              // fmt块大小
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(1))               // PCM
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(1))               // 单声道
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(audioSampleRate)) // 采样率
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(audioSampleRate)) // 字节率
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(1))               // 块对齐
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(8))               // 位深
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, size)
+	buf.Write(samples)
+	return buf.Bytes()
+}
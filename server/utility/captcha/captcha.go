@@ -26,7 +26,11 @@ func GenerateCode(length int) string {
 
 // GenerateImage 生成验证码图片
 func GenerateImage(code string) (string, error) {
+	// 算术表达式较长，按字符数加宽图片
 	width, height := 120, 40
+	if w := len(code)*20 + 20; w > width {
+		width = w
+	}
 	img := image.NewRGBA(image.Rect(0, 0, width, height))
 
 	// 设置背景色为白色
@@ -227,6 +231,61 @@ func drawChar(img *image.RGBA, char string, x, y int) {
 			{1, 0, 0, 0, 1},
 			{0, 1, 1, 1, 0},
 		},
+		"+": {
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+			{0, 0, 1, 0, 0},
+			{0, 0, 1, 0, 0},
+			{1, 1, 1, 1, 1},
+			{0, 0, 1, 0, 0},
+			{0, 0, 1, 0, 0},
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+		},
+		"-": {
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+			{1, 1, 1, 1, 1},
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+		},
+		"x": {
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+			{1, 0, 0, 0, 1},
+			{0, 1, 0, 1, 0},
+			{0, 0, 1, 0, 0},
+			{0, 1, 0, 1, 0},
+			{1, 0, 0, 0, 1},
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+		},
+		"=": {
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+			{1, 1, 1, 1, 1},
+			{0, 0, 0, 0, 0},
+			{1, 1, 1, 1, 1},
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+			{0, 0, 0, 0, 0},
+		},
+		"?": {
+			{0, 1, 1, 1, 0},
+			{1, 0, 0, 0, 1},
+			{0, 0, 0, 0, 1},
+			{0, 0, 0, 1, 0},
+			{0, 0, 1, 0, 0},
+			{0, 0, 1, 0, 0},
+			{0, 0, 0, 0, 0},
+			{0, 0, 1, 0, 0},
+			{0, 0, 1, 0, 0},
+		},
 	}
 
 	pattern, exists := patterns[char]
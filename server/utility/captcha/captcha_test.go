@@ -0,0 +1,60 @@
+// Package captcha_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package captcha_test
+
+import (
+	"client-app/utility/captcha"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+func TestProviders(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		for _, captchaType := range []string{captcha.TypeDigit, captcha.TypeArithmetic, captcha.TypeSlider, captcha.TypeAudio} {
+			p, ok := captcha.Get(captchaType)
+			t.Assert(ok, true)
+
+			challenge, err := p.Generate()
+			t.AssertNil(err)
+			t.Assert(challenge.Type, captchaType)
+			t.AssertNE(challenge.Answer, "")
+			t.Assert(p.Verify(challenge.Answer, challenge.Answer), true)
+			t.Assert(p.Verify(challenge.Answer, ""), false)
+		}
+
+		_, ok := captcha.Get("unknown")
+		t.Assert(ok, false)
+	})
+}
+
+func TestSlider_Tolerance(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		p, _ := captcha.Get(captcha.TypeSlider)
+		challenge, err := p.Generate()
+		t.AssertNil(err)
+		t.AssertNE(challenge.Slider, nil)
+		t.Assert(strings.HasPrefix(challenge.Slider.Piece, "data:image/png;base64,"), true)
+
+		x, _ := strconv.Atoi(challenge.Answer)
+		t.Assert(p.Verify(challenge.Answer, strconv.Itoa(x+3)), true)
+		t.Assert(p.Verify(challenge.Answer, strconv.Itoa(x-3)), true)
+		t.Assert(p.Verify(challenge.Answer, strconv.Itoa(x+20)), false)
+		t.Assert(p.Verify(challenge.Answer, "abc"), false)
+	})
+}
+
+func TestGenerateAudio(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		wav := captcha.GenerateAudio("1024")
+		t.Assert(string(wav[0:4]), "RIFF")
+		t.Assert(string(wav[8:12]), "WAVE")
+		t.Assert(string(wav[36:40]), "data")
+		t.Assert(len(wav) > 8000, true)
+	})
+}
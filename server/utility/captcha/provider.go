@@ -0,0 +1,137 @@
+package captcha
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 验证码类型
+const (
+	TypeDigit      = "digit"      // 数字验证码
+	TypeArithmetic = "arithmetic" // 算术验证码
+	TypeSlider     = "slider"     // 滑块拼图验证码
+	TypeAudio      = "audio"      // 语音验证码，供视障用户使用
+)
+
+// Challenge 验证码挑战
+// Answer 只保存在服务端，其余字段返回给客户端
+type Challenge struct {
+	Type   string  // 验证码类型
+	Answer string  // 正确答案
+	Image  string  // 图片（data URI）
+	Audio  string  // 音频（data URI）
+	Slider *Slider // 滑块拼图信息
+}
+
+// Slider 滑块拼图，客户端提交拼图块的横向偏移量
+type Slider struct {
+	Piece string // 拼图块图片（data URI）
+	Y     int    // 拼图块纵向位置
+	Size  int    // 拼图块边长
+	Width int    // 背景图宽度
+}
+
+// Provider 验证码提供者
+type Provider interface {
+	// Type 验证码类型
+	Type() string
+	// Generate 生成验证码挑战
+	Generate() (*Challenge, error)
+	// Verify 校验用户输入
+	Verify(answer, input string) bool
+}
+
+var (
+	providers   = map[string]Provider{}
+	providersMu sync.RWMutex
+)
+
+func init() {
+	Register(&digitProvider{length: 4})
+	Register(&arithmeticProvider{})
+	Register(&sliderProvider{tolerance: 5})
+	Register(&audioProvider{length: 4})
+}
+
+// Register 注册验证码提供者，同类型重复注册时覆盖
+func Register(p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[p.Type()] = p
+}
+
+// Get 获取验证码提供者
+func Get(captchaType string) (Provider, bool) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	p, ok := providers[captchaType]
+	return p, ok
+}
+
+// digitProvider 数字验证码
+type digitProvider struct {
+	length int
+}
+
+func (p *digitProvider) Type() string {
+	return TypeDigit
+}
+
+func (p *digitProvider) Generate() (*Challenge, error) {
+	code := GenerateCode(p.length)
+	img, err := GenerateImage(code)
+	if err != nil {
+		return nil, err
+	}
+	return &Challenge{Type: TypeDigit, Answer: code, Image: img}, nil
+}
+
+func (p *digitProvider) Verify(answer, input string) bool {
+	return answer != "" && answer == strings.TrimSpace(input)
+}
+
+// arithmeticProvider 算术验证码，答案为表达式的计算结果
+type arithmeticProvider struct{}
+
+func (p *arithmeticProvider) Type() string {
+	return TypeArithmetic
+}
+
+func (p *arithmeticProvider) Generate() (*Challenge, error) {
+	a, b := randInt(1, 20), randInt(1, 20)
+	var expression string
+	var result int
+	switch randInt(0, 3) {
+	case 0:
+		expression, result = fmt.Sprintf("%d+%d=?", a, b), a+b
+	case 1:
+		// 减法保证结果非负，便于输入
+		if a < b {
+			a, b = b, a
+		}
+		expression, result = fmt.Sprintf("%d-%d=?", a, b), a-b
+	default:
+		a, b = randInt(1, 10), randInt(1, 10)
+		expression, result = fmt.Sprintf("%dx%d=?", a, b), a*b
+	}
+
+	img, err := GenerateImage(expression)
+	if err != nil {
+		return nil, err
+	}
+	return &Challenge{Type: TypeArithmetic, Answer: strconv.Itoa(result), Image: img}, nil
+}
+
+func (p *arithmeticProvider) Verify(answer, input string) bool {
+	return answer != "" && answer == strings.TrimSpace(input)
+}
+
+// randInt 生成 [min, max) 范围内的随机数
+func randInt(min, max int) int {
+	n, _ := rand.Int(rand.Reader, big.NewInt(int64(max-min)))
+	return min + int(n.Int64())
+}
@@ -0,0 +1,127 @@
+package captcha
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+)
+
+const (
+	sliderWidth     = 280
+	sliderHeight    = 160
+	sliderPieceSize = 44
+)
+
+// sliderProvider 滑块拼图验证码
+// 背景图挖去一块，客户端拖动拼图块到缺口处，服务端校验横向偏移量
+type sliderProvider struct {
+	tolerance int // 允许的偏移误差（像素）
+}
+
+func (p *sliderProvider) Type() string {
+	return TypeSlider
+}
+
+func (p *sliderProvider) Generate() (*Challenge, error) {
+	bg := drawSliderBackground()
+
+	x := randInt(sliderPieceSize+10, sliderWidth-sliderPieceSize-10)
+	y := randInt(10, sliderHeight-sliderPieceSize-10)
+
+	// 拼图块取自原图，背景图对应位置压暗作为缺口
+	piece := image.NewRGBA(image.Rect(0, 0, sliderPieceSize, sliderPieceSize))
+	border := color.RGBA{255, 255, 255, 255}
+	for dy := 0; dy < sliderPieceSize; dy++ {
+		for dx := 0; dx < sliderPieceSize; dx++ {
+			c := bg.RGBAAt(x+dx, y+dy)
+			if dx == 0 || dy == 0 || dx == sliderPieceSize-1 || dy == sliderPieceSize-1 {
+				piece.SetRGBA(dx, dy, border)
+			} else {
+				piece.SetRGBA(dx, dy, c)
+			}
+			bg.SetRGBA(x+dx, y+dy, color.RGBA{c.R / 3, c.G / 3, c.B / 3, 255})
+		}
+	}
+
+	bgUri, err := encodePNG(bg)
+	if err != nil {
+		return nil, err
+	}
+	pieceUri, err := encodePNG(piece)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Challenge{
+		Type:   TypeSlider,
+		Answer: strconv.Itoa(x),
+		Image:  bgUri,
+		Slider: &Slider{
+			Piece: pieceUri,
+			Y:     y,
+			Size:  sliderPieceSize,
+			Width: sliderWidth,
+		},
+	}, nil
+}
+
+func (p *sliderProvider) Verify(answer, input string) bool {
+	expected, err := strconv.Atoi(answer)
+	if err != nil {
+		return false
+	}
+	offset, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil {
+		return false
+	}
+	diff := offset - expected
+	return diff >= -p.tolerance && diff <= p.tolerance
+}
+
+// drawSliderBackground 绘制随机渐变背景并叠加色块，避免缺口位置被直接识别
+func drawSliderBackground() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, sliderWidth, sliderHeight))
+
+	from := color.RGBA{uint8(randInt(60, 200)), uint8(randInt(60, 200)), uint8(randInt(60, 200)), 255}
+	to := color.RGBA{uint8(randInt(60, 200)), uint8(randInt(60, 200)), uint8(randInt(60, 200)), 255}
+	for x := 0; x < sliderWidth; x++ {
+		ratio := float64(x) / float64(sliderWidth)
+		c := color.RGBA{
+			uint8(float64(from.R)*(1-ratio) + float64(to.R)*ratio),
+			uint8(float64(from.G)*(1-ratio) + float64(to.G)*ratio),
+			uint8(float64(from.B)*(1-ratio) + float64(to.B)*ratio),
+			255,
+		}
+		for y := 0; y < sliderHeight; y++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	for i := 0; i < 12; i++ {
+		cx, cy, r := randInt(0, sliderWidth), randInt(0, sliderHeight), randInt(8, 30)
+		c := color.RGBA{uint8(randInt(0, 256)), uint8(randInt(0, 256)), uint8(randInt(0, 256)), 255}
+		for y := cy - r; y <= cy+r; y++ {
+			for x := cx - r; x <= cx+r; x++ {
+				if (x-cx)*(x-cx)+(y-cy)*(y-cy) <= r*r && image.Pt(x, y).In(img.Bounds()) {
+					img.SetRGBA(x, y, c)
+				}
+			}
+		}
+	}
+
+	addNoise(img, 400)
+	return img
+}
+
+// encodePNG 将图片编码为PNG data URI
+func encodePNG(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -37,11 +37,6 @@ func GenerateSalt() string {
 	return Md5ToString(fmt.Sprintf("%d_%d", time.Now().UnixNano(), generateRandomNumber()))
 }
 
-// HashPassword 对密码进行哈希加密
-func HashPassword(password, salt string) string {
-	return Md5ToString(password + salt)
-}
-
 // generateRandomNumber 生成随机数
 func generateRandomNumber() int64 {
 	buf := make([]byte, 8)
@@ -0,0 +1,232 @@
+// Package encrypt
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package encrypt
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 密码哈希算法标识
+const (
+	PasswordAlgArgon2id = "argon2id"
+	PasswordAlgBcrypt   = "bcrypt"
+	PasswordAlgMd5      = "md5" // 历史遗留格式，只用于校验，不再用于生成
+)
+
+// PasswordHasher 密码哈希器
+// 生成的哈希值必须自带算法前缀，以便校验时识别所用算法
+type PasswordHasher interface {
+	// Algorithm 算法标识
+	Algorithm() string
+	// Hash 生成密码哈希
+	Hash(password string) (string, error)
+	// Verify 校验密码
+	Verify(password, encoded string) bool
+	// NeedsRehash 哈希参数是否低于当前配置，需要重新生成
+	NeedsRehash(encoded string) bool
+}
+
+var (
+	hashers       = make(map[string]PasswordHasher)
+	defaultHasher PasswordHasher
+	hasherMu      sync.RWMutex
+)
+
+func init() {
+	RegisterPasswordHasher(NewArgon2idHasher())
+	RegisterPasswordHasher(NewBcryptHasher(bcrypt.DefaultCost))
+	defaultHasher = hashers[PasswordAlgArgon2id]
+}
+
+// RegisterPasswordHasher 注册密码哈希器，同名算法会被覆盖
+func RegisterPasswordHasher(h PasswordHasher) {
+	hasherMu.Lock()
+	defer hasherMu.Unlock()
+	hashers[h.Algorithm()] = h
+}
+
+// SetDefaultPasswordHasher 设置生成新密码时使用的算法
+func SetDefaultPasswordHasher(alg string) error {
+	hasherMu.Lock()
+	defer hasherMu.Unlock()
+	h, ok := hashers[alg]
+	if !ok {
+		return gerror.Newf("不支持的密码哈希算法: %s", alg)
+	}
+	defaultHasher = h
+	return nil
+}
+
+// PasswordAlgorithm 识别密码哈希所用的算法
+func PasswordAlgorithm(encoded string) string {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return PasswordAlgArgon2id
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return PasswordAlgBcrypt
+	case len(encoded) == 32 && !strings.HasPrefix(encoded, "$"):
+		return PasswordAlgMd5
+	}
+	return ""
+}
+
+// HashPassword 使用默认算法生成密码哈希
+func HashPassword(password string) (string, error) {
+	hasherMu.RLock()
+	h := defaultHasher
+	hasherMu.RUnlock()
+	return h.Hash(password)
+}
+
+// VerifyPassword 校验密码
+// salt 仅用于历史MD5格式，新格式的盐值已包含在哈希值中。
+// needsRehash 为 true 时表示校验通过但哈希应使用默认算法重新生成。
+func VerifyPassword(password, encoded, salt string) (ok bool, needsRehash bool) {
+	alg := PasswordAlgorithm(encoded)
+	if alg == PasswordAlgMd5 {
+		return verifyLegacyMd5(password, encoded, salt), true
+	}
+
+	hasherMu.RLock()
+	h, exists := hashers[alg]
+	def := defaultHasher
+	hasherMu.RUnlock()
+	if !exists {
+		return false, false
+	}
+
+	if !h.Verify(password, encoded) {
+		return false, false
+	}
+	return true, h.Algorithm() != def.Algorithm() || h.NeedsRehash(encoded)
+}
+
+// verifyLegacyMd5 校验历史MD5密码，兼容 md5(password+salt) 和初始化数据中的 md5(password)
+func verifyLegacyMd5(password, encoded, salt string) bool {
+	encoded = strings.ToLower(encoded)
+	if subtle.ConstantTimeCompare([]byte(Md5ToString(password+salt)), []byte(encoded)) == 1 {
+		return true
+	}
+	return salt != "" && subtle.ConstantTimeCompare([]byte(Md5ToString(password)), []byte(encoded)) == 1
+}
+
+// Argon2idHasher argon2id 哈希器
+// 格式：$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+type Argon2idHasher struct {
+	Memory  uint32 // 内存开销，单位KB
+	Time    uint32 // 迭代次数
+	Threads uint8  // 并行度
+	SaltLen uint32
+	KeyLen  uint32
+}
+
+// NewArgon2idHasher 使用推荐参数创建 argon2id 哈希器
+func NewArgon2idHasher() *Argon2idHasher {
+	return &Argon2idHasher{Memory: 64 * 1024, Time: 3, Threads: 2, SaltLen: 16, KeyLen: 32}
+}
+
+func (h *Argon2idHasher) Algorithm() string {
+	return PasswordAlgArgon2id
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", gerror.Wrap(err, "生成密码盐值失败")
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, h.KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Time, h.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2idHasher) Verify(password, encoded string) bool {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false
+	}
+	other := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	params, _, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return params.Memory < h.Memory || params.Time < h.Time || params.Threads < h.Threads || uint32(len(key)) < h.KeyLen
+}
+
+// decodeArgon2id 解析 argon2id 哈希值
+func decodeArgon2id(encoded string) (params *Argon2idHasher, salt, key []byte, err error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != PasswordAlgArgon2id {
+		return nil, nil, nil, gerror.New("无效的argon2id哈希格式")
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, gerror.New("不支持的argon2版本")
+	}
+
+	params = &Argon2idHasher{}
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return nil, nil, nil, gerror.New("无效的argon2id参数")
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, nil, nil, gerror.New("无效的argon2id盐值")
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return nil, nil, nil, gerror.New("无效的argon2id哈希")
+	}
+	return params, salt, key, nil
+}
+
+// BcryptHasher bcrypt 哈希器
+type BcryptHasher struct {
+	Cost int
+}
+
+// NewBcryptHasher 创建 bcrypt 哈希器
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{Cost: cost}
+}
+
+func (h *BcryptHasher) Algorithm() string {
+	return PasswordAlgBcrypt
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", gerror.Wrap(err, "生成密码哈希失败")
+	}
+	return string(hash), nil
+}
+
+func (h *BcryptHasher) Verify(password, encoded string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)) == nil
+}
+
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost < h.Cost
+}
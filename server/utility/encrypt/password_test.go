@@ -0,0 +1,59 @@
+// Package encrypt_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package encrypt_test
+
+import (
+	"client-app/utility/encrypt"
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+func TestHashPassword_Argon2id(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		hash, err := encrypt.HashPassword("123456")
+		t.AssertNil(err)
+		t.Assert(strings.HasPrefix(hash, "$argon2id$v=19$"), true)
+		t.Assert(encrypt.PasswordAlgorithm(hash), encrypt.PasswordAlgArgon2id)
+
+		ok, needsRehash := encrypt.VerifyPassword("123456", hash, "")
+		t.Assert(ok, true)
+		t.Assert(needsRehash, false)
+
+		ok, _ = encrypt.VerifyPassword("654321", hash, "")
+		t.Assert(ok, false)
+	})
+}
+
+func TestHashPassword_Bcrypt(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		hash, err := encrypt.NewBcryptHasher(4).Hash("123456")
+		t.AssertNil(err)
+		t.Assert(encrypt.PasswordAlgorithm(hash), encrypt.PasswordAlgBcrypt)
+
+		// 默认算法为argon2id，bcrypt哈希校验通过后需要升级
+		ok, needsRehash := encrypt.VerifyPassword("123456", hash, "")
+		t.Assert(ok, true)
+		t.Assert(needsRehash, true)
+	})
+}
+
+func TestVerifyPassword_LegacyMd5(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		// 初始化数据中的 md5("123456")
+		ok, needsRehash := encrypt.VerifyPassword("123456", "e10adc3949ba59abbe56e057f20f883e", "salt123")
+		t.Assert(ok, true)
+		t.Assert(needsRehash, true)
+
+		// md5(password+salt)
+		ok, _ = encrypt.VerifyPassword("123456", encrypt.Md5ToString("123456salt123"), "salt123")
+		t.Assert(ok, true)
+
+		ok, _ = encrypt.VerifyPassword("000000", "e10adc3949ba59abbe56e057f20f883e", "salt123")
+		t.Assert(ok, false)
+	})
+}
@@ -0,0 +1,435 @@
+// Package jwt
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+// 支持的签名算法
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+var (
+	ErrTokenMalformed   = gerror.New("无效的JWT格式")
+	ErrTokenSignature   = gerror.New("JWT签名验证失败")
+	ErrTokenExpired     = gerror.New("JWT token已过期")
+	ErrTokenNotValidYet = gerror.New("JWT token尚未生效")
+	ErrKeyNotFound      = gerror.New("JWT签名密钥不存在")
+	ErrAlgNotAllowed    = gerror.New("不支持的JWT签名算法")
+)
+
+var rawURL = base64.RawURLEncoding
+
+// Header JWT头部
+type Header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// Key 签名密钥
+// HS256 使用 Secret；RS256/EdDSA 使用 PrivateKey 签名，PublicKey 验签。
+// 只配置了 PublicKey 的密钥仅用于验签，不能被设为签发密钥。
+type Key struct {
+	Kid        string
+	Alg        string
+	Secret     []byte
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// CanSign 是否可用于签发
+func (k *Key) CanSign() bool {
+	if k.Alg == AlgHS256 {
+		return len(k.Secret) > 0
+	}
+	return k.PrivateKey != nil
+}
+
+// validate 校验密钥与算法是否匹配
+func (k *Key) validate() error {
+	if k.Kid == "" {
+		return gerror.New("密钥ID(kid)不能为空")
+	}
+	switch k.Alg {
+	case AlgHS256:
+		if len(k.Secret) < 32 {
+			return gerror.Newf("密钥[%s]HS256密钥长度不能小于32字节", k.Kid)
+		}
+	case AlgRS256:
+		if k.PublicKey == nil && k.PrivateKey != nil {
+			k.PublicKey = k.PrivateKey.Public()
+		}
+		pub, ok := k.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return gerror.Newf("密钥[%s]不是有效的RSA密钥", k.Kid)
+		}
+		if pub.N.BitLen() < 2048 {
+			return gerror.Newf("密钥[%s]RSA密钥长度不能小于2048位", k.Kid)
+		}
+	case AlgEdDSA:
+		if k.PublicKey == nil && k.PrivateKey != nil {
+			k.PublicKey = k.PrivateKey.Public()
+		}
+		if _, ok := k.PublicKey.(ed25519.PublicKey); !ok {
+			return gerror.Newf("密钥[%s]不是有效的Ed25519密钥", k.Kid)
+		}
+	default:
+		return gerror.Newf("密钥[%s]使用了不支持的算法: %s", k.Kid, k.Alg)
+	}
+	return nil
+}
+
+// sign 对消息签名
+func (k *Key) sign(message []byte) ([]byte, error) {
+	switch k.Alg {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, k.Secret)
+		mac.Write(message)
+		return mac.Sum(nil), nil
+	case AlgRS256:
+		sum := sha256.Sum256(message)
+		return k.PrivateKey.Sign(rand.Reader, sum[:], crypto.SHA256)
+	case AlgEdDSA:
+		return k.PrivateKey.Sign(rand.Reader, message, crypto.Hash(0))
+	}
+	return nil, ErrAlgNotAllowed
+}
+
+// verify 验证签名
+func (k *Key) verify(message, signature []byte) bool {
+	switch k.Alg {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, k.Secret)
+		mac.Write(message)
+		return hmac.Equal(signature, mac.Sum(nil))
+	case AlgRS256:
+		sum := sha256.Sum256(message)
+		return rsa.VerifyPKCS1v15(k.PublicKey.(*rsa.PublicKey), crypto.SHA256, sum[:], signature) == nil
+	case AlgEdDSA:
+		return ed25519.Verify(k.PublicKey.(ed25519.PublicKey), message, signature)
+	}
+	return false
+}
+
+// Keyring 密钥环，支持多个密钥同时有效以便轮换
+type Keyring struct {
+	mu        sync.RWMutex
+	keys      map[string]*Key
+	order     []string
+	activeKid string
+	leeway    time.Duration
+}
+
+// NewKeyring 创建密钥环
+func NewKeyring() *Keyring {
+	return &Keyring{keys: make(map[string]*Key)}
+}
+
+// SetLeeway 设置校验exp/nbf时允许的时钟偏差
+func (r *Keyring) SetLeeway(leeway time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.leeway = leeway
+}
+
+// Add 添加密钥，第一个可签发的密钥默认作为签发密钥
+func (r *Keyring) Add(key *Key) error {
+	if key == nil {
+		return gerror.New("密钥不能为空")
+	}
+	if err := key.validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.keys[key.Kid]; ok {
+		return gerror.Newf("密钥[%s]已存在", key.Kid)
+	}
+	r.keys[key.Kid] = key
+	r.order = append(r.order, key.Kid)
+	if r.activeKid == "" && key.CanSign() {
+		r.activeKid = key.Kid
+	}
+	return nil
+}
+
+// Remove 移除密钥，移除后由该密钥签发的令牌将无法通过验证
+func (r *Keyring) Remove(kid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.keys, kid)
+	for i, v := range r.order {
+		if v == kid {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	if r.activeKid == kid {
+		r.activeKid = ""
+	}
+}
+
+// SetActive 设置签发密钥
+func (r *Keyring) SetActive(kid string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.keys[kid]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	if !key.CanSign() {
+		return gerror.Newf("密钥[%s]缺少私钥，不能用于签发", kid)
+	}
+	r.activeKid = kid
+	return nil
+}
+
+// ActiveKid 当前签发密钥ID
+func (r *Keyring) ActiveKid() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.activeKid
+}
+
+// Sign 使用当前签发密钥对claims签名
+func (r *Keyring) Sign(claims interface{}) (string, error) {
+	r.mu.RLock()
+	key, ok := r.keys[r.activeKid]
+	r.mu.RUnlock()
+	if !ok {
+		return "", ErrKeyNotFound
+	}
+
+	headerJson, err := json.Marshal(Header{Alg: key.Alg, Typ: "JWT", Kid: key.Kid})
+	if err != nil {
+		return "", err
+	}
+	claimsJson, err := json.Marshal(claims)
+	if err != nil {
+		return "", gerror.Wrap(err, "序列化JWT claims失败")
+	}
+
+	message := rawURL.EncodeToString(headerJson) + "." + rawURL.EncodeToString(claimsJson)
+	signature, err := key.sign([]byte(message))
+	if err != nil {
+		return "", gerror.Wrap(err, "JWT签名失败")
+	}
+	return message + "." + rawURL.EncodeToString(signature), nil
+}
+
+// Parse 验证签名和有效期，并将claims解析到out
+func (r *Keyring) Parse(token string, out interface{}) (*Header, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+
+	headerJson, err := rawURL.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+	var header Header
+	if err = json.Unmarshal(headerJson, &header); err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	r.mu.RLock()
+	kid := header.Kid
+	if kid == "" {
+		kid = r.activeKid
+	}
+	key, ok := r.keys[kid]
+	leeway := r.leeway
+	r.mu.RUnlock()
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	// 算法必须与密钥绑定的算法一致，防止算法混淆攻击
+	if header.Alg != key.Alg {
+		return nil, ErrAlgNotAllowed
+	}
+
+	signature, err := rawURL.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+	if !key.verify([]byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrTokenSignature
+	}
+
+	claimsJson, err := rawURL.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	var registered struct {
+		Exp int64 `json:"exp"`
+		Nbf int64 `json:"nbf"`
+	}
+	if err = json.Unmarshal(claimsJson, &registered); err != nil {
+		return nil, ErrTokenMalformed
+	}
+	now := time.Now()
+	if registered.Exp > 0 && now.After(time.Unix(registered.Exp, 0).Add(leeway)) {
+		return nil, ErrTokenExpired
+	}
+	if registered.Nbf > 0 && now.Add(leeway).Before(time.Unix(registered.Nbf, 0)) {
+		return nil, ErrTokenNotValidYet
+	}
+
+	if out != nil {
+		if err = json.Unmarshal(claimsJson, out); err != nil {
+			return nil, gerror.Wrap(err, "解析JWT claims失败")
+		}
+	}
+	return &header, nil
+}
+
+// JWK 单个公钥
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet 公钥集合，对应 /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS 导出所有非对称密钥的公钥，对称密钥不会被公开
+func (r *Keyring) JWKS() *JWKSet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	set := &JWKSet{Keys: make([]JWK, 0, len(r.order))}
+	for _, kid := range r.order {
+		key := r.keys[kid]
+		switch pub := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA",
+				Use: "sig",
+				Alg: key.Alg,
+				Kid: key.Kid,
+				N:   rawURL.EncodeToString(pub.N.Bytes()),
+				E:   rawURL.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP",
+				Use: "sig",
+				Alg: key.Alg,
+				Kid: key.Kid,
+				Crv: "Ed25519",
+				X:   rawURL.EncodeToString(pub),
+			})
+		}
+	}
+	return set
+}
+
+// KeyFromJWK 将JWK公钥转换为验签密钥，支持RSA和Ed25519
+func KeyFromJWK(jwk JWK) (*Key, error) {
+	key := &Key{Kid: jwk.Kid, Alg: jwk.Alg}
+	switch jwk.Kty {
+	case "RSA":
+		n, err := rawURL.DecodeString(jwk.N)
+		if err != nil {
+			return nil, gerror.Wrapf(err, "密钥[%s]RSA模数格式错误", jwk.Kid)
+		}
+		e, err := rawURL.DecodeString(jwk.E)
+		if err != nil {
+			return nil, gerror.Wrapf(err, "密钥[%s]RSA指数格式错误", jwk.Kid)
+		}
+		key.PublicKey = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if key.Alg == "" {
+			key.Alg = AlgRS256
+		}
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, gerror.Newf("密钥[%s]不支持的曲线: %s", jwk.Kid, jwk.Crv)
+		}
+		x, err := rawURL.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, gerror.Newf("密钥[%s]Ed25519公钥格式错误", jwk.Kid)
+		}
+		key.PublicKey = ed25519.PublicKey(x)
+		if key.Alg == "" {
+			key.Alg = AlgEdDSA
+		}
+	default:
+		return nil, gerror.Newf("密钥[%s]不支持的密钥类型: %s", jwk.Kid, jwk.Kty)
+	}
+
+	if err := key.validate(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ParsePrivateKeyPEM 解析PEM格式私钥，支持PKCS#1和PKCS#8
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, gerror.New("无效的PEM私钥")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, gerror.Wrap(err, "解析私钥失败")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, gerror.New("不支持的私钥类型")
+	}
+	return signer, nil
+}
+
+// ParsePublicKeyPEM 解析PEM格式公钥，支持PKIX和PKCS#1
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, gerror.New("无效的PEM公钥")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, gerror.Wrap(err, "解析公钥失败")
+	}
+	return key, nil
+}
@@ -0,0 +1,127 @@
+// Package jwt_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package jwt_test
+
+import (
+	"client-app/utility/jwt"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+type claims struct {
+	UserId int64 `json:"userId"`
+	Exp    int64 `json:"exp"`
+}
+
+func TestKeyring_HS256(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		ring := jwt.NewKeyring()
+		t.AssertNil(ring.Add(&jwt.Key{Kid: "hs1", Alg: jwt.AlgHS256, Secret: []byte(strings.Repeat("k", 32))}))
+
+		token, err := ring.Sign(&claims{UserId: 1, Exp: time.Now().Add(time.Hour).Unix()})
+		t.AssertNil(err)
+
+		var out claims
+		header, err := ring.Parse(token, &out)
+		t.AssertNil(err)
+		t.Assert(header.Kid, "hs1")
+		t.Assert(header.Alg, jwt.AlgHS256)
+		t.Assert(out.UserId, 1)
+
+		// 篡改payload
+		parts := strings.Split(token, ".")
+		parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"userId":2}`))
+		_, err = ring.Parse(strings.Join(parts, "."), &out)
+		t.Assert(errors.Is(err, jwt.ErrTokenSignature), true)
+
+		// 对称密钥不公开
+		t.Assert(len(ring.JWKS().Keys), 0)
+	})
+}
+
+func TestKeyring_Expired(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		ring := jwt.NewKeyring()
+		t.AssertNil(ring.Add(&jwt.Key{Kid: "hs1", Alg: jwt.AlgHS256, Secret: []byte(strings.Repeat("k", 32))}))
+
+		token, err := ring.Sign(&claims{UserId: 1, Exp: time.Now().Add(-time.Minute).Unix()})
+		t.AssertNil(err)
+		_, err = ring.Parse(token, nil)
+		t.Assert(errors.Is(err, jwt.ErrTokenExpired), true)
+
+		ring.SetLeeway(2 * time.Minute)
+		_, err = ring.Parse(token, nil)
+		t.AssertNil(err)
+	})
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		t.AssertNil(err)
+		_, edKey, err := ed25519.GenerateKey(rand.Reader)
+		t.AssertNil(err)
+
+		ring := jwt.NewKeyring()
+		t.AssertNil(ring.Add(&jwt.Key{Kid: "rs1", Alg: jwt.AlgRS256, PrivateKey: rsaKey}))
+		t.AssertNil(ring.Add(&jwt.Key{Kid: "ed1", Alg: jwt.AlgEdDSA, PrivateKey: edKey}))
+		t.Assert(ring.ActiveKid(), "rs1")
+
+		oldToken, err := ring.Sign(&claims{UserId: 1})
+		t.AssertNil(err)
+
+		// 切换签发密钥后旧令牌仍然有效
+		t.AssertNil(ring.SetActive("ed1"))
+		newToken, err := ring.Sign(&claims{UserId: 2})
+		t.AssertNil(err)
+
+		header, err := ring.Parse(oldToken, nil)
+		t.AssertNil(err)
+		t.Assert(header.Kid, "rs1")
+		header, err = ring.Parse(newToken, nil)
+		t.AssertNil(err)
+		t.Assert(header.Kid, "ed1")
+		t.Assert(header.Alg, jwt.AlgEdDSA)
+
+		jwks := ring.JWKS()
+		t.Assert(len(jwks.Keys), 2)
+		t.Assert(jwks.Keys[0].Kty, "RSA")
+		t.Assert(jwks.Keys[0].E, "AQAB")
+		t.Assert(jwks.Keys[1].Kty, "OKP")
+		t.Assert(jwks.Keys[1].Crv, "Ed25519")
+
+		// 移除旧密钥后由其签发的令牌失效
+		ring.Remove("rs1")
+		_, err = ring.Parse(oldToken, nil)
+		t.Assert(errors.Is(err, jwt.ErrKeyNotFound), true)
+	})
+}
+
+func TestKeyring_AlgConfusion(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		t.AssertNil(err)
+
+		ring := jwt.NewKeyring()
+		t.AssertNil(ring.Add(&jwt.Key{Kid: "rs1", Alg: jwt.AlgRS256, PrivateKey: rsaKey}))
+		token, err := ring.Sign(&claims{UserId: 1})
+		t.AssertNil(err)
+
+		// 将头部算法改为none
+		parts := strings.Split(token, ".")
+		parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"rs1"}`))
+		_, err = ring.Parse(strings.Join(parts, "."), nil)
+		t.Assert(errors.Is(err, jwt.ErrAlgNotAllowed), true)
+	})
+}
@@ -0,0 +1,362 @@
+// Package oidc
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package oidc
+
+import (
+	"client-app/utility/jwt"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+var (
+	ErrIssuerMismatch   = gerror.New("ID Token签发者不匹配")
+	ErrAudienceMismatch = gerror.New("ID Token受众不匹配")
+	ErrNonceMismatch    = gerror.New("ID Token nonce不匹配")
+	ErrMissingIdToken   = gerror.New("令牌响应中缺少ID Token")
+)
+
+// Metadata 身份提供方元数据，对应 /.well-known/openid-configuration
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// Config 依赖方配置
+type Config struct {
+	Issuer       string   // 身份提供方地址，用于发现元数据
+	ClientId     string   // 客户端ID
+	ClientSecret string   // 客户端密钥，公共客户端可为空
+	RedirectURL  string   // 回调地址
+	Scopes       []string // 申请的权限范围，openid 会自动加入
+}
+
+// Token 令牌端点响应
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	IdToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// Claims ID Token声明
+type Claims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Audience          audience `json:"aud"`
+	AuthorizedParty   string   `json:"azp"`
+	Expiry            int64    `json:"exp"`
+	IssuedAt          int64    `json:"iat"`
+	Nonce             string   `json:"nonce"`
+	Email             string   `json:"email"`
+	EmailVerified     bool     `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+	Picture           string   `json:"picture"`
+
+	// Raw 全部声明，用于读取自定义字段
+	Raw map[string]interface{} `json:"-"`
+}
+
+// audience aud 声明可以是字符串或字符串数组
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return err
+	}
+	*a = multi
+	return nil
+}
+
+func (a audience) contains(clientId string) bool {
+	for _, aud := range a {
+		if aud == clientId {
+			return true
+		}
+	}
+	return false
+}
+
+// Provider 身份提供方，缓存元数据和签名公钥
+type Provider struct {
+	config   Config
+	metadata *Metadata
+	client   *http.Client
+
+	mu      sync.Mutex
+	keyring *jwt.Keyring
+	keysAt  time.Time
+	keysTTL time.Duration
+	leeway  time.Duration
+	nowFunc func() time.Time
+}
+
+// NewProvider 通过发现端点创建身份提供方，httpClient 为空时使用默认客户端
+func NewProvider(ctx context.Context, config Config, httpClient *http.Client) (*Provider, error) {
+	if config.Issuer == "" || config.ClientId == "" {
+		return nil, gerror.New("OIDC配置缺少issuer或clientId")
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	p := &Provider{
+		config:  config,
+		client:  httpClient,
+		keysTTL: time.Hour,
+		leeway:  time.Minute,
+		nowFunc: time.Now,
+	}
+
+	discoveryURL := strings.TrimSuffix(config.Issuer, "/") + "/.well-known/openid-configuration"
+	var metadata Metadata
+	if err := p.getJSON(ctx, discoveryURL, &metadata); err != nil {
+		return nil, gerror.Wrap(err, "获取OIDC元数据失败")
+	}
+
+	// 元数据中的issuer必须与配置一致，防止被引导到其他身份提供方
+	if strings.TrimSuffix(metadata.Issuer, "/") != strings.TrimSuffix(config.Issuer, "/") {
+		return nil, gerror.Newf("OIDC元数据issuer不匹配: %s", metadata.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JwksURI == "" {
+		return nil, gerror.New("OIDC元数据缺少必要的端点")
+	}
+	p.metadata = &metadata
+	return p, nil
+}
+
+// Metadata 获取身份提供方元数据
+func (p *Provider) Metadata() *Metadata {
+	return p.metadata
+}
+
+// AuthCodeURL 生成授权地址
+func (p *Provider) AuthCodeURL(state, nonce, codeChallenge string) string {
+	scopes := []string{"openid"}
+	for _, scope := range p.config.Scopes {
+		if scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientId)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(p.metadata.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return p.metadata.AuthorizationEndpoint + sep + query.Encode()
+}
+
+// Exchange 使用授权码和PKCE校验码换取令牌
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientId)
+	form.Set("code_verifier", codeVerifier)
+	if p.config.ClientSecret != "" {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, gerror.Wrap(err, "请求令牌端点失败")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, gerror.Wrap(err, "读取令牌响应失败")
+	}
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		_ = json.Unmarshal(body, &oauthErr)
+		return nil, gerror.Newf("换取令牌失败: %d %s %s", resp.StatusCode, oauthErr.Error, oauthErr.Description)
+	}
+
+	var token Token
+	if err = json.Unmarshal(body, &token); err != nil {
+		return nil, gerror.Wrap(err, "解析令牌响应失败")
+	}
+	if token.IdToken == "" {
+		return nil, ErrMissingIdToken
+	}
+	return &token, nil
+}
+
+// VerifyIdToken 验证ID Token的签名、签发者、受众、有效期和nonce
+func (p *Provider) VerifyIdToken(ctx context.Context, rawIdToken, nonce string) (*Claims, error) {
+	keyring, err := p.getKeyring(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	_, err = keyring.Parse(rawIdToken, &raw)
+	if errors.Is(err, jwt.ErrKeyNotFound) {
+		// 身份提供方可能已轮换密钥，强制刷新一次公钥
+		if keyring, err = p.getKeyring(ctx, true); err != nil {
+			return nil, err
+		}
+		_, err = keyring.Parse(rawIdToken, &raw)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var claims Claims
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return nil, gerror.Wrap(err, "解析ID Token声明失败")
+	}
+	claims.Raw = raw
+
+	if strings.TrimSuffix(claims.Issuer, "/") != strings.TrimSuffix(p.metadata.Issuer, "/") {
+		return nil, ErrIssuerMismatch
+	}
+	if !claims.Audience.contains(p.config.ClientId) {
+		return nil, ErrAudienceMismatch
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != "" && claims.AuthorizedParty != p.config.ClientId {
+		return nil, ErrAudienceMismatch
+	}
+	if claims.Expiry == 0 {
+		return nil, gerror.New("ID Token缺少过期时间")
+	}
+	if claims.IssuedAt > 0 && p.nowFunc().Add(p.leeway).Before(time.Unix(claims.IssuedAt, 0)) {
+		return nil, gerror.New("ID Token签发时间无效")
+	}
+	if claims.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+	if claims.Subject == "" {
+		return nil, gerror.New("ID Token缺少sub")
+	}
+	return &claims, nil
+}
+
+// getKeyring 获取签名公钥，缓存过期或 refresh 为 true 时重新拉取
+func (p *Provider) getKeyring(ctx context.Context, refresh bool) (*jwt.Keyring, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !refresh && p.keyring != nil && p.nowFunc().Sub(p.keysAt) < p.keysTTL {
+		return p.keyring, nil
+	}
+
+	var set jwt.JWKSet
+	if err := p.getJSON(ctx, p.metadata.JwksURI, &set); err != nil {
+		return nil, gerror.Wrap(err, "获取OIDC签名公钥失败")
+	}
+
+	keyring := jwt.NewKeyring()
+	keyring.SetLeeway(p.leeway)
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// 不支持的密钥类型直接跳过，只要令牌使用的密钥可用即可
+		key, err := jwt.KeyFromJWK(jwk)
+		if err != nil {
+			continue
+		}
+		if err = keyring.Add(key); err != nil {
+			continue
+		}
+	}
+
+	p.keyring = keyring
+	p.keysAt = p.nowFunc()
+	return keyring, nil
+}
+
+// getJSON 请求并解析JSON
+func (p *Provider) getJSON(ctx context.Context, target string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return gerror.Newf("请求 %s 失败: %d", target, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
+
+// NewPKCE 生成PKCE校验码及其S256摘要
+func NewPKCE() (verifier, challenge string, err error) {
+	if verifier, err = RandomString(32); err != nil {
+		return "", "", err
+	}
+	return verifier, S256Challenge(verifier), nil
+}
+
+// S256Challenge 计算PKCE S256摘要
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// RandomString 生成URL安全的随机字符串，用于state、nonce和PKCE校验码
+func RandomString(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", gerror.Wrap(err, "生成随机字符串失败")
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
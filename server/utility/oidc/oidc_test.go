@@ -0,0 +1,178 @@
+// Package oidc_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package oidc_test
+
+import (
+	"client-app/utility/jwt"
+	"client-app/utility/oidc"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+const testClientId = "client-app"
+
+// mockIdP 模拟身份提供方，提供发现、公钥和令牌端点
+type mockIdP struct {
+	server *httptest.Server
+	ring   *jwt.Keyring
+
+	mu    sync.Mutex
+	codes map[string]codeGrant
+}
+
+type codeGrant struct {
+	challenge string
+	nonce     string
+	audience  string
+}
+
+func newMockIdP(t *gtest.T) *mockIdP {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	t.AssertNil(err)
+
+	idp := &mockIdP{ring: jwt.NewKeyring(), codes: map[string]codeGrant{}}
+	t.AssertNil(idp.ring.Add(&jwt.Key{Kid: "idp-1", Alg: jwt.AlgRS256, PrivateKey: privateKey}))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(oidc.Metadata{
+			Issuer:                idp.server.URL,
+			AuthorizationEndpoint: idp.server.URL + "/authorize",
+			TokenEndpoint:         idp.server.URL + "/token",
+			JwksURI:               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(idp.ring.JWKS())
+	})
+	mux.HandleFunc("/token", idp.handleToken)
+	idp.server = httptest.NewServer(mux)
+	return idp
+}
+
+// authorize 模拟用户在身份提供方完成登录，返回授权码
+func (idp *mockIdP) authorize(challenge, nonce, audience string) string {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	code := "code-" + nonce
+	idp.codes[code] = codeGrant{challenge: challenge, nonce: nonce, audience: audience}
+	return code
+}
+
+func (idp *mockIdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	idp.mu.Lock()
+	grant, ok := idp.codes[r.PostForm.Get("code")]
+	delete(idp.codes, r.PostForm.Get("code"))
+	idp.mu.Unlock()
+
+	if !ok || oidc.S256Challenge(r.PostForm.Get("code_verifier")) != grant.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken, _ := idp.ring.Sign(map[string]interface{}{
+		"iss":   idp.server.URL,
+		"sub":   "user-1",
+		"aud":   grant.audience,
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": grant.nonce,
+		"email": "alice@example.com",
+	})
+	_ = json.NewEncoder(w).Encode(oidc.Token{AccessToken: "at", TokenType: "Bearer", IdToken: idToken})
+}
+
+func newProvider(t *gtest.T, idp *mockIdP) *oidc.Provider {
+	provider, err := oidc.NewProvider(context.Background(), oidc.Config{
+		Issuer:      idp.server.URL,
+		ClientId:    testClientId,
+		RedirectURL: "https://app.example.com/callback",
+		Scopes:      []string{"email"},
+	}, nil)
+	t.AssertNil(err)
+	return provider
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		idp := newMockIdP(t)
+		defer idp.server.Close()
+		provider := newProvider(t, idp)
+
+		verifier, challenge, err := oidc.NewPKCE()
+		t.AssertNil(err)
+
+		authURL, err := url.Parse(provider.AuthCodeURL("state-1", "nonce-1", challenge))
+		t.AssertNil(err)
+		t.Assert(authURL.Query().Get("scope"), "openid email")
+		t.Assert(authURL.Query().Get("code_challenge_method"), "S256")
+		t.Assert(authURL.Query().Get("code_challenge"), challenge)
+
+		code := idp.authorize(challenge, "nonce-1", testClientId)
+		token, err := provider.Exchange(context.Background(), code, verifier)
+		t.AssertNil(err)
+
+		claims, err := provider.VerifyIdToken(context.Background(), token.IdToken, "nonce-1")
+		t.AssertNil(err)
+		t.Assert(claims.Subject, "user-1")
+		t.Assert(claims.Email, "alice@example.com")
+	})
+}
+
+func TestProvider_Rejects(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		idp := newMockIdP(t)
+		defer idp.server.Close()
+		provider := newProvider(t, idp)
+		ctx := context.Background()
+
+		// PKCE校验码不匹配
+		verifier, challenge, _ := oidc.NewPKCE()
+		code := idp.authorize(challenge, "nonce-2", testClientId)
+		_, err := provider.Exchange(ctx, code, verifier+"x")
+		t.AssertNE(err, nil)
+
+		// nonce不匹配
+		verifier, challenge, _ = oidc.NewPKCE()
+		token, err := provider.Exchange(ctx, idp.authorize(challenge, "nonce-3", testClientId), verifier)
+		t.AssertNil(err)
+		_, err = provider.VerifyIdToken(ctx, token.IdToken, "other")
+		t.Assert(errors.Is(err, oidc.ErrNonceMismatch), true)
+
+		// 受众不是本客户端
+		verifier, challenge, _ = oidc.NewPKCE()
+		token, err = provider.Exchange(ctx, idp.authorize(challenge, "nonce-4", "other-client"), verifier)
+		t.AssertNil(err)
+		_, err = provider.VerifyIdToken(ctx, token.IdToken, "nonce-4")
+		t.Assert(errors.Is(err, oidc.ErrAudienceMismatch), true)
+	})
+}
+
+func TestNewProvider_IssuerMismatch(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		idp := newMockIdP(t)
+		defer idp.server.Close()
+
+		_, err := oidc.NewProvider(context.Background(), oidc.Config{
+			Issuer:   idp.server.URL + "/other",
+			ClientId: testClientId,
+		}, nil)
+		t.AssertNE(err, nil)
+	})
+}
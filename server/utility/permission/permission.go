@@ -0,0 +1,63 @@
+// Package permission
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package permission
+
+import "strings"
+
+const (
+	// Separator 权限标识分段分隔符，如 role:delete
+	Separator = ":"
+	// Wildcard 通配符，单独使用表示全部权限，作为末段表示该前缀下的全部权限，如 role:*
+	Wildcard = "*"
+)
+
+// Match 判断授予的权限是否覆盖所需权限
+// 支持精确匹配和末段通配：* 匹配全部，role:* 匹配 role:list、role:menus:update 等
+func Match(grant, required string) bool {
+	if grant == "" || required == "" {
+		return false
+	}
+	if grant == required || grant == Wildcard {
+		return true
+	}
+	if !strings.HasSuffix(grant, Separator+Wildcard) {
+		return false
+	}
+	prefix := strings.TrimSuffix(grant, Wildcard)
+	return strings.HasPrefix(required, prefix) && len(required) > len(prefix)
+}
+
+// MatchAny 判断授予的权限列表中是否有任一覆盖所需权限
+func MatchAny(grants []string, required string) bool {
+	for _, grant := range grants {
+		if Match(grant, required) {
+			return true
+		}
+	}
+	return false
+}
+
+// Patterns 返回可以覆盖所需权限的全部授权标识，用于数据库中按 IN 条件匹配通配权限
+// 例如 role:menus:update 返回 [role:menus:update * role:* role:menus:*]
+func Patterns(required string) []string {
+	if required == "" {
+		return nil
+	}
+
+	parts := strings.Split(required, Separator)
+	patterns := make([]string, 0, len(parts)+1)
+	patterns = append(patterns, required, Wildcard)
+	for i := 1; i < len(parts); i++ {
+		patterns = append(patterns, strings.Join(parts[:i], Separator)+Separator+Wildcard)
+	}
+	return patterns
+}
+
+// FromPath 根据路由地址生成权限标识，仅用于未声明权限标识的路由
+// 例如 /role/list 生成 role:list
+func FromPath(path string) string {
+	return strings.ReplaceAll(strings.Trim(path, "/"), "/", Separator)
+}
@@ -0,0 +1,59 @@
+// Package permission_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package permission_test
+
+import (
+	"client-app/utility/permission"
+	"testing"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+func TestMatch(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		t.Assert(permission.Match("role:delete", "role:delete"), true)
+		t.Assert(permission.Match("role:delete", "role:list"), false)
+		t.Assert(permission.Match("*", "role:delete"), true)
+		t.Assert(permission.Match("role:*", "role:delete"), true)
+		t.Assert(permission.Match("role:*", "role:menus:update"), true)
+		t.Assert(permission.Match("role:menus:*", "role:menus:update"), true)
+		t.Assert(permission.Match("role:menus:*", "role:list"), false)
+		t.Assert(permission.Match("role:*", "roles:list"), false)
+		t.Assert(permission.Match("role:*", "role:"), false)
+		t.Assert(permission.Match("role*", "role:list"), false)
+		t.Assert(permission.Match("", "role:list"), false)
+		t.Assert(permission.Match("*", ""), false)
+	})
+}
+
+func TestMatchAny(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		t.Assert(permission.MatchAny([]string{"user:list", "role:*"}, "role:delete"), true)
+		t.Assert(permission.MatchAny([]string{"user:list", "dept:*"}, "role:delete"), false)
+		t.Assert(permission.MatchAny(nil, "role:delete"), false)
+	})
+}
+
+func TestPatterns(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		t.Assert(permission.Patterns("role:menus:update"), []string{"role:menus:update", "*", "role:*", "role:menus:*"})
+		t.Assert(permission.Patterns("role"), []string{"role", "*"})
+		t.Assert(len(permission.Patterns("")), 0)
+
+		// 生成的通配标识均能覆盖原权限
+		for _, pattern := range permission.Patterns("role:menus:update") {
+			t.Assert(permission.Match(pattern, "role:menus:update"), true)
+		}
+	})
+}
+
+func TestFromPath(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		t.Assert(permission.FromPath("/role/list"), "role:list")
+		t.Assert(permission.FromPath("/dept/users/move/"), "dept:users:move")
+		t.Assert(permission.FromPath("/role/{id}"), "role:{id}")
+	})
+}
@@ -0,0 +1,150 @@
+// Package pwdpolicy
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package pwdpolicy
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+// 字符类型
+const (
+	ClassLower  = "lower"  // 小写字母
+	ClassUpper  = "upper"  // 大写字母
+	ClassLetter = "letter" // 字母，不区分大小写
+	ClassDigit  = "digit"  // 数字
+	ClassSymbol = "symbol" // 特殊符号
+)
+
+var classNames = map[string]string{
+	ClassLower:  "小写字母",
+	ClassUpper:  "大写字母",
+	ClassLetter: "字母",
+	ClassDigit:  "数字",
+	ClassSymbol: "特殊符号",
+}
+
+// commonPasswords 内置的常见弱密码，与策略中的禁用列表一起校验
+var commonPasswords = []string{
+	"123456", "1234567", "12345678", "123456789", "1234567890", "111111", "000000",
+	"123123", "abc123", "abc12345", "a123456", "a12345678", "password", "password1",
+	"password123", "passw0rd", "qwerty", "qwerty123", "qwe123", "1q2w3e4r", "1qaz2wsx",
+	"admin", "admin123", "admin888", "root123", "iloveyou", "welcome1", "woaini1314",
+}
+
+// Policy 密码策略
+type Policy struct {
+	MinLength        int      `json:"minLength"`        // 最小长度
+	MaxLength        int      `json:"maxLength"`        // 最大长度，0表示不限制
+	RequireClasses   []string `json:"requireClasses"`   // 必须包含的字符类型
+	MinClasses       int      `json:"minClasses"`       // 至少包含的字符类型数量（小写、大写、数字、符号）
+	Banned           []string `json:"banned"`           // 禁用密码，不区分大小写
+	DisallowUsername bool     `json:"disallowUsername"` // 是否禁止包含用户名
+	HistoryCount     int      `json:"historyCount"`     // 不能与最近几次使用过的密码相同，0表示不限制
+	MaxAgeDays       int      `json:"maxAgeDays"`       // 密码最长使用天数，0表示不过期
+}
+
+// Default 默认密码策略，与历史校验规则保持一致
+func Default() *Policy {
+	return &Policy{
+		MinLength:      6,
+		MaxLength:      32,
+		RequireClasses: []string{ClassLetter, ClassDigit},
+	}
+}
+
+// Validate 校验密码是否符合策略，password 为明文
+func (p *Policy) Validate(password, username string) error {
+	if password == "" {
+		return gerror.New("密码不能为空")
+	}
+
+	length := utf8.RuneCountInString(password)
+	if p.MinLength > 0 && length < p.MinLength {
+		return gerror.Newf("密码长度不能少于%d位", p.MinLength)
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return gerror.Newf("密码长度不能超过%d位", p.MaxLength)
+	}
+
+	classes := classify(password)
+	for _, class := range p.RequireClasses {
+		if !classes[class] {
+			name, ok := classNames[class]
+			if !ok {
+				return gerror.Newf("密码策略配置错误，未知的字符类型: %s", class)
+			}
+			return gerror.Newf("密码必须包含%s", name)
+		}
+	}
+	if p.MinClasses > 0 {
+		count := 0
+		for _, class := range []string{ClassLower, ClassUpper, ClassDigit, ClassSymbol} {
+			if classes[class] {
+				count++
+			}
+		}
+		if count < p.MinClasses {
+			return gerror.Newf("密码必须包含小写字母、大写字母、数字、特殊符号中的至少%d种", p.MinClasses)
+		}
+	}
+
+	lower := strings.ToLower(password)
+	if p.DisallowUsername && username != "" && strings.Contains(lower, strings.ToLower(username)) {
+		return gerror.New("密码不能包含用户名")
+	}
+	if p.IsBanned(password) {
+		return gerror.New("密码过于常见，请更换")
+	}
+	return nil
+}
+
+// IsBanned 判断是否为禁用密码
+func (p *Policy) IsBanned(password string) bool {
+	lower := strings.ToLower(password)
+	for _, list := range [][]string{commonPasswords, p.Banned} {
+		for _, banned := range list {
+			if banned != "" && lower == strings.ToLower(banned) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Expired 判断密码是否已超过最长使用天数，changedAt 为零值时视为未过期
+func (p *Policy) Expired(changedAt, now time.Time) bool {
+	if p.MaxAgeDays <= 0 || changedAt.IsZero() {
+		return false
+	}
+	return now.Sub(changedAt) > time.Duration(p.MaxAgeDays)*24*time.Hour
+}
+
+// classify 统计密码包含的字符类型
+func classify(password string) map[string]bool {
+	classes := make(map[string]bool, 5)
+	for _, char := range password {
+		switch {
+		case unicode.IsLower(char):
+			classes[ClassLower] = true
+			classes[ClassLetter] = true
+		case unicode.IsUpper(char):
+			classes[ClassUpper] = true
+			classes[ClassLetter] = true
+		case unicode.IsDigit(char):
+			classes[ClassDigit] = true
+		case unicode.IsLetter(char):
+			classes[ClassLetter] = true
+		default:
+			classes[ClassSymbol] = true
+		}
+	}
+	return classes
+}
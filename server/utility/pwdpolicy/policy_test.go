@@ -0,0 +1,53 @@
+// Package pwdpolicy_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package pwdpolicy_test
+
+import (
+	"client-app/utility/pwdpolicy"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+func TestPolicy_Validate(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		p := pwdpolicy.Default()
+		t.AssertNil(p.Validate("abc12x", "tom"))
+		t.AssertNE(p.Validate("ab1", "tom"), nil)
+		t.AssertNE(p.Validate("abcdefgh", "tom"), nil)
+		t.AssertNE(p.Validate("12345678", "tom"), nil)
+		// 内置弱密码不区分大小写
+		t.AssertNE(p.Validate("Password123", "tom"), nil)
+
+		p = &pwdpolicy.Policy{
+			MinLength:        10,
+			MinClasses:       3,
+			Banned:           []string{"Company2024!"},
+			DisallowUsername: true,
+		}
+		t.AssertNil(p.Validate("Blue-horse-42", "tom"))
+		t.AssertNE(p.Validate("bluehorse42", "tom"), nil)
+		t.AssertNE(p.Validate("company2024!", "tom"), nil)
+		t.AssertNE(p.Validate("Tom-Horse-42", "tom"), nil)
+
+		p = &pwdpolicy.Policy{RequireClasses: []string{"emoji"}}
+		t.AssertNE(p.Validate("anything", ""), nil)
+	})
+}
+
+func TestPolicy_Expired(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		now := time.Now()
+		p := &pwdpolicy.Policy{MaxAgeDays: 90}
+		t.Assert(p.Expired(now.AddDate(0, 0, -91), now), true)
+		t.Assert(p.Expired(now.AddDate(0, 0, -10), now), false)
+		t.Assert(p.Expired(time.Time{}, now), false)
+
+		p.MaxAgeDays = 0
+		t.Assert(p.Expired(now.AddDate(-5, 0, 0), now), false)
+	})
+}
@@ -0,0 +1,84 @@
+package sheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gogf/gf/v2/encoding/gcharset"
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ReadCSV 读取CSV，兼容带BOM的UTF-8以及中文Excel默认导出的GBK编码
+func ReadCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if !utf8.Valid(data) {
+		converted, err := gcharset.ToUTF8("GBK", string(data))
+		if err != nil {
+			return nil, gerror.Wrap(err, "CSV文件编码无法识别")
+		}
+		data = []byte(converted)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var rows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, gerror.Wrap(err, "解析CSV文件失败")
+		}
+		if len(rows) >= MaxRows || len(record) > MaxColumns {
+			return nil, ErrTooLarge
+		}
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+		rows = append(rows, record)
+	}
+	return rows, nil
+}
+
+// WriteCSV 写出带BOM的UTF-8 CSV，便于Excel直接打开
+// 以公式字符开头的单元格会加上单引号前缀，防止CSV公式注入
+func WriteCSV(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(utf8BOM)
+
+	writer := csv.NewWriter(&buf)
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, cell := range row {
+			record[i] = escapeFormula(cell)
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// escapeFormula 转义可能被表格软件当作公式执行的内容
+func escapeFormula(cell string) string {
+	if cell == "" {
+		return cell
+	}
+	switch cell[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + cell
+	}
+	return cell
+}
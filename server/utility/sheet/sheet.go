@@ -0,0 +1,82 @@
+// Package sheet
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package sheet
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+// 表格文件格式
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// 读取限制，防止超大文件或压缩炸弹耗尽内存
+const (
+	MaxRows       = 10000
+	MaxColumns    = 256
+	maxUnzipBytes = 64 << 20
+)
+
+var (
+	ErrFormat   = gerror.New("不支持的表格格式，仅支持csv、xlsx")
+	ErrTooLarge = gerror.Newf("表格内容过大，最多允许%d行、%d列", MaxRows, MaxColumns)
+)
+
+// FormatOf 根据文件名后缀识别表格格式，无法识别时返回空字符串
+func FormatOf(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV
+	case ".xlsx":
+		return FormatXLSX
+	}
+	return ""
+}
+
+// ContentType 获取表格格式对应的MIME类型
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Read 读取表格的第一个工作表，返回去掉首尾空白的单元格文本
+func Read(format string, data []byte) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		return ReadCSV(data)
+	case FormatXLSX:
+		return ReadXLSX(data)
+	}
+	return nil, ErrFormat
+}
+
+// Write 将数据写为指定格式的表格
+func Write(format string, rows [][]string) ([]byte, error) {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(rows)
+	case FormatXLSX:
+		return WriteXLSX(rows)
+	}
+	return nil, ErrFormat
+}
+
+// IsEmptyRow 是否为空行
+func IsEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if cell != "" {
+			return false
+		}
+	}
+	return true
+}
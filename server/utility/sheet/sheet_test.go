@@ -0,0 +1,88 @@
+// Package sheet_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package sheet_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"client-app/utility/sheet"
+	"testing"
+
+	"github.com/gogf/gf/v2/encoding/gcharset"
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+var rows = [][]string{
+	{"username", "email", "phone"},
+	{"zhangsan", "zs@example.com", "13800138000"},
+	{"李四", "a&b<c>@example.com", ""},
+}
+
+func TestRoundTrip(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		for _, format := range []string{sheet.FormatCSV, sheet.FormatXLSX} {
+			data, err := sheet.Write(format, rows)
+			t.AssertNil(err)
+
+			got, err := sheet.Read(format, data)
+			t.AssertNil(err)
+			t.Assert(got, rows)
+		}
+
+		_, err := sheet.Read("xls", nil)
+		t.Assert(err, sheet.ErrFormat)
+		t.Assert(sheet.FormatOf("users.XLSX"), sheet.FormatXLSX)
+		t.Assert(sheet.FormatOf("users.xls"), "")
+	})
+}
+
+func TestCSV_FormulaAndGBK(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		data, err := sheet.WriteCSV([][]string{{"=1+1", "@SUM(A1)", "ok"}})
+		t.AssertNil(err)
+		got, err := sheet.ReadCSV(data)
+		t.AssertNil(err)
+		t.Assert(got, [][]string{{"'=1+1", "'@SUM(A1)", "ok"}})
+
+		gbk, err := gcharset.UTF8To("GBK", "用户名,部门\n张三,研发部\n")
+		t.AssertNil(err)
+		got, err = sheet.ReadCSV([]byte(gbk))
+		t.AssertNil(err)
+		t.Assert(got, [][]string{{"用户名", "部门"}, {"张三", "研发部"}})
+	})
+}
+
+func TestXLSX_SharedStrings(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		// 模拟Excel保存的文件：共享字符串、富文本、跳过的空单元格和空行、科学计数法数字
+		files := map[string]string{
+			"xl/workbook.xml":            `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="用户" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+			"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId3" Target="worksheets/users.xml"/></Relationships>`,
+			"xl/sharedStrings.xml":       `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><si><t>username</t></si><si><r><t>zhang</t></r><r><t>san</t></r></si></sst>`,
+			"xl/worksheets/users.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+				`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="inlineStr"><is><t>phone</t></is></c></row>` +
+				`<row r="3"><c r="A3" t="s"><v>1</v></c><c r="C3"><v>1.3800138E10</v></c></row>` +
+				`</sheetData></worksheet>`,
+		}
+
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, content := range files {
+			w, err := zw.Create(name)
+			t.AssertNil(err)
+			_, err = w.Write([]byte(content))
+			t.AssertNil(err)
+		}
+		t.AssertNil(zw.Close())
+
+		got, err := sheet.ReadXLSX(buf.Bytes())
+		t.AssertNil(err)
+		t.Assert(len(got), 3)
+		t.Assert(got[0], []string{"username", "", "phone"})
+		t.Assert(sheet.IsEmptyRow(got[1]), true)
+		t.Assert(got[2], []string{"zhangsan", "", "13800138000"})
+	})
+}
@@ -0,0 +1,283 @@
+package sheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+// xlsx 只实现导入导出所需的最小子集：读取第一个工作表的单元格文本，写出单个内联字符串工作表
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Id string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.R) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.R {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R  string    `xml:"r,attr"`
+			T  string    `xml:"t,attr"`
+			V  string    `xml:"v"`
+			Is *xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadXLSX 读取XLSX文件第一个工作表
+func ReadXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, gerror.Wrap(err, "解析XLSX文件失败")
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err = decodeZipXML(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[firstSheetPath(files)]
+	if !ok {
+		return nil, gerror.New("XLSX文件中没有工作表")
+	}
+	var ws xlsxWorksheet
+	if err = decodeZipXML(f, &ws); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for i, row := range ws.Rows {
+		index := row.R - 1
+		if row.R == 0 {
+			index = i
+		}
+		if index >= MaxRows {
+			return nil, ErrTooLarge
+		}
+		for len(rows) <= index {
+			rows = append(rows, nil)
+		}
+
+		var cells []string
+		for j, c := range row.Cells {
+			col := j
+			if c.R != "" {
+				if col, err = columnIndex(c.R); err != nil {
+					return nil, err
+				}
+			}
+			if col >= MaxColumns {
+				return nil, ErrTooLarge
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+
+			var value string
+			switch c.T {
+			case "s":
+				idx, err := strconv.Atoi(c.V)
+				if err != nil || idx < 0 || idx >= len(shared.Items) {
+					return nil, gerror.Newf("XLSX单元格%s引用了无效的共享字符串", c.R)
+				}
+				value = shared.Items[idx].String()
+			case "inlineStr":
+				if c.Is != nil {
+					value = c.Is.String()
+				}
+			case "", "n":
+				value = formatNumber(c.V)
+			default:
+				value = c.V
+			}
+			cells[col] = strings.TrimSpace(value)
+		}
+		rows[index] = cells
+	}
+	return rows, nil
+}
+
+// WriteXLSX 写出只包含一个工作表的XLSX文件，单元格均为内联字符串
+func WriteXLSX(rows [][]string) ([]byte, error) {
+	var sheet bytes.Buffer
+	sheet.WriteString(xml.Header)
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		sheet.WriteString(`<row r="` + strconv.Itoa(i+1) + `">`)
+		for j, cell := range row {
+			sheet.WriteString(`<c r="` + columnName(j) + strconv.Itoa(i+1) + `" t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(&sheet, []byte(cell)); err != nil {
+				return nil, err
+			}
+			sheet.WriteString(`</t></is></c>`)
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`},
+		{"xl/worksheets/sheet1.xml", sheet.String()},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, part := range parts {
+		w, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// firstSheetPath 通过工作簿关系找到第一个工作表的路径
+func firstSheetPath(files map[string]*zip.File) string {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	var wb xlsxWorkbook
+	var rels xlsxRelationships
+	wbFile, ok1 := files["xl/workbook.xml"]
+	relsFile, ok2 := files["xl/_rels/workbook.xml.rels"]
+	if !ok1 || !ok2 || decodeZipXML(wbFile, &wb) != nil || decodeZipXML(relsFile, &rels) != nil || len(wb.Sheets) == 0 {
+		return fallback
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.Id != wb.Sheets[0].Id {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/")
+		}
+		return path.Join("xl", rel.Target)
+	}
+	return fallback
+}
+
+// decodeZipXML 解码压缩包内的XML文件，限制解压后的大小
+func decodeZipXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return gerror.Wrapf(err, "读取XLSX文件%s失败", f.Name)
+	}
+	defer rc.Close()
+
+	lr := &io.LimitedReader{R: rc, N: maxUnzipBytes + 1}
+	if err = xml.NewDecoder(lr).Decode(v); err != nil {
+		if lr.N <= 0 {
+			return ErrTooLarge
+		}
+		return gerror.Wrapf(err, "解析XLSX文件%s失败", f.Name)
+	}
+	return nil
+}
+
+// columnIndex 将单元格引用（如 AB12）转换为从0开始的列号
+func columnIndex(ref string) (int, error) {
+	col := 0
+	for i := 0; i < len(ref); i++ {
+		c := ref[i]
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		if c < 'A' || c > 'Z' {
+			if i == 0 {
+				break
+			}
+			return col - 1, nil
+		}
+		col = col*26 + int(c-'A'+1)
+		if col > MaxColumns {
+			return 0, ErrTooLarge
+		}
+	}
+	return 0, gerror.Newf("无效的XLSX单元格引用: %s", ref)
+}
+
+// columnName 将从0开始的列号转换为列名（如 0 => A，27 => AB）
+func columnName(index int) string {
+	var name []byte
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = append([]byte{byte('A' + (index-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+// formatNumber 还原数值单元格，避免手机号等长数字显示为科学计数法
+func formatNumber(v string) string {
+	if !strings.ContainsAny(v, "eE") {
+		return v
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return v
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
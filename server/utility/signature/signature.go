@@ -0,0 +1,127 @@
+// Package signature
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+var (
+	ErrTimestampInvalid = gerror.New("时间戳格式错误")
+	ErrTimestampSkew    = gerror.New("请求时间超出允许的偏差范围")
+	ErrNonceInvalid     = gerror.New("nonce格式错误")
+	ErrSignatureInvalid = gerror.New("签名验证失败")
+)
+
+// Request 参与签名的请求内容
+type Request struct {
+	Method    string     // 请求方法
+	Path      string     // 请求路径，不含域名和查询参数
+	Query     url.Values // 查询参数
+	Body      []byte     // 请求体原文
+	Timestamp string     // Unix时间戳（秒）
+	Nonce     string     // 随机串，同一商户在有效期内不可重复
+}
+
+// Canonical 构造待签名字符串
+// 各部分以换行连接：方法、路径、排序后的查询参数、请求体SHA256、时间戳、nonce
+func (r *Request) Canonical() string {
+	return strings.Join([]string{
+		strings.ToUpper(r.Method),
+		r.Path,
+		canonicalQuery(r.Query),
+		BodyHash(r.Body),
+		r.Timestamp,
+		r.Nonce,
+	}, "\n")
+}
+
+// Sign 使用密钥计算签名，结果为十六进制小写
+func (r *Request) Sign(secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(r.Canonical()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验时间戳、nonce格式和签名，nonce是否重复由调用方检查
+func (r *Request) Verify(secret, signature string, skew time.Duration, now time.Time) error {
+	if err := CheckTimestamp(r.Timestamp, skew, now); err != nil {
+		return err
+	}
+	if !ValidNonce(r.Nonce) {
+		return ErrNonceInvalid
+	}
+
+	expected := r.Sign(secret)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
+
+// CheckTimestamp 检查时间戳是否在允许的偏差范围内
+func CheckTimestamp(timestamp string, skew time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || ts <= 0 {
+		return ErrTimestampInvalid
+	}
+	diff := now.Sub(time.Unix(ts, 0))
+	if diff > skew || diff < -skew {
+		return ErrTimestampSkew
+	}
+	return nil
+}
+
+// ValidNonce 判断nonce格式，长度16-64，只允许字母、数字、下划线和中划线
+func ValidNonce(nonce string) bool {
+	if len(nonce) < 16 || len(nonce) > 64 {
+		return false
+	}
+	for _, c := range nonce {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// BodyHash 请求体SHA256，空请求体同样参与计算
+func BodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalQuery 按参数名和参数值排序后编码查询参数
+func canonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, url.QueryEscape(key)+"="+url.QueryEscape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
@@ -0,0 +1,74 @@
+// Package signature_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package signature_test
+
+import (
+	"client-app/utility/signature"
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+const testSecret = "5787c7a121190011fac8376b1d3e0396"
+
+func newRequest(now time.Time) *signature.Request {
+	return &signature.Request{
+		Method:    "post",
+		Path:      "/api/order/create",
+		Query:     url.Values{"b": {"2", "1"}, "a": {"x y"}},
+		Body:      []byte(`{"amount":100}`),
+		Timestamp: strconv.FormatInt(now.Unix(), 10),
+		Nonce:     "0123456789abcdef",
+	}
+}
+
+func TestRequest_Canonical(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		req := newRequest(time.Unix(1700000000, 0))
+		t.Assert(req.Canonical(), "POST\n/api/order/create\na=x+y&b=1&b=2\n"+
+			signature.BodyHash(req.Body)+"\n1700000000\n0123456789abcdef")
+	})
+}
+
+func TestRequest_Verify(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		now := time.Now()
+		req := newRequest(now)
+		sign := req.Sign(testSecret)
+
+		t.AssertNil(req.Verify(testSecret, sign, 5*time.Minute, now))
+
+		// 查询参数顺序不影响签名
+		req.Query = url.Values{"a": {"x y"}, "b": {"1", "2"}}
+		t.AssertNil(req.Verify(testSecret, sign, 5*time.Minute, now))
+
+		// 密钥或请求体不同
+		t.Assert(errors.Is(req.Verify("other", sign, 5*time.Minute, now), signature.ErrSignatureInvalid), true)
+		req.Body = []byte(`{"amount":1000}`)
+		t.Assert(errors.Is(req.Verify(testSecret, sign, 5*time.Minute, now), signature.ErrSignatureInvalid), true)
+	})
+}
+
+func TestRequest_VerifyTimestampAndNonce(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		now := time.Now()
+
+		req := newRequest(now.Add(-10 * time.Minute))
+		t.Assert(errors.Is(req.Verify(testSecret, req.Sign(testSecret), 5*time.Minute, now), signature.ErrTimestampSkew), true)
+
+		req = newRequest(now)
+		req.Timestamp = "abc"
+		t.Assert(errors.Is(req.Verify(testSecret, req.Sign(testSecret), 5*time.Minute, now), signature.ErrTimestampInvalid), true)
+
+		req = newRequest(now)
+		req.Nonce = "short"
+		t.Assert(errors.Is(req.Verify(testSecret, req.Sign(testSecret), 5*time.Minute, now), signature.ErrNonceInvalid), true)
+	})
+}
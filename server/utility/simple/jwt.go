@@ -0,0 +1,223 @@
+// Package simple
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package simple
+
+import (
+	"client-app/utility/jwt"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/util/guid"
+)
+
+// JWT Token payload 结构
+type JWTPayload struct {
+	UserId      int64     `json:"userId"`                // 用户ID
+	TenantId    int64     `json:"tenantId"`              // 租户ID
+	TenantCode  string    `json:"tenantCode"`            // 租户编码
+	Username    string    `json:"username"`              // 用户名
+	RoleId      int64     `json:"roleId"`                // 主要角色ID
+	RoleKey     string    `json:"roleKey"`               // 角色标识
+	DeptId      int64     `json:"deptId"`                // 部门ID
+	App         string    `json:"app"`                   // 应用标识
+	Iss         string    `json:"iss,omitempty"`         // 签发者
+	Sub         string    `json:"sub,omitempty"`         // 主体（用户ID）
+	Jti         string    `json:"jti"`                   // 令牌唯一标识，用于撤销
+	Sid         string    `json:"sid,omitempty"`         // 登录会话ID
+	Act         *JWTActor `json:"act,omitempty"`         // 模拟登录的实际操作人，为空表示本人登录
+	Mcp         bool      `json:"mcp,omitempty"`         // 签发时密码已过期，修改密码前只能访问有限接口
+	PermVersion int64     `json:"permVersion,omitempty"` // 签发时的用户权限版本，用于发现过期的权限快照
+	Iat         int64     `json:"iat"`                   // 签发时间
+	IatMs       int64     `json:"iatMs,omitempty"`       // 签发时间（毫秒），iat 只精确到秒，用于和撤销时间比较
+	Exp         int64     `json:"exp"`                   // 过期时间
+}
+
+// JWTActor 模拟登录的实际操作人
+type JWTActor struct {
+	UserId   int64  `json:"userId"`   // 操作人用户ID
+	TenantId int64  `json:"tenantId"` // 操作人租户ID
+	Username string `json:"username"` // 操作人用户名
+}
+
+// jwtKeyConfig 单个签名密钥配置
+type jwtKeyConfig struct {
+	Kid            string `json:"kid"`
+	Alg            string `json:"alg"`
+	Secret         string `json:"secret"`
+	PrivateKey     string `json:"privateKey"`
+	PrivateKeyFile string `json:"privateKeyFile"`
+	PublicKey      string `json:"publicKey"`
+	PublicKeyFile  string `json:"publicKeyFile"`
+}
+
+var (
+	jwtKeyring   *jwt.Keyring
+	jwtKeyringMu sync.Mutex
+)
+
+// JWTKeyring 获取JWT密钥环，首次调用时从配置加载
+func JWTKeyring(ctx context.Context) (*jwt.Keyring, error) {
+	jwtKeyringMu.Lock()
+	defer jwtKeyringMu.Unlock()
+
+	if jwtKeyring != nil {
+		return jwtKeyring, nil
+	}
+
+	keyring, err := loadJWTKeyring(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jwtKeyring = keyring
+	return jwtKeyring, nil
+}
+
+// ReloadJWTKeyring 重新加载JWT密钥环，用于密钥轮换后刷新配置
+func ReloadJWTKeyring(ctx context.Context) error {
+	keyring, err := loadJWTKeyring(ctx)
+	if err != nil {
+		return err
+	}
+
+	jwtKeyringMu.Lock()
+	jwtKeyring = keyring
+	jwtKeyringMu.Unlock()
+	return nil
+}
+
+// loadJWTKeyring 从配置加载密钥环
+func loadJWTKeyring(ctx context.Context) (*jwt.Keyring, error) {
+	var configs []*jwtKeyConfig
+	if err := g.Cfg().MustGet(ctx, "jwt.keys").Scan(&configs); err != nil {
+		return nil, gerror.Wrap(err, "读取JWT密钥配置失败")
+	}
+	if len(configs) == 0 {
+		return nil, gerror.New("未配置JWT签名密钥，请在 jwt.keys 中配置")
+	}
+
+	keyring := jwt.NewKeyring()
+	keyring.SetLeeway(g.Cfg().MustGet(ctx, "jwt.leeway", "30s").Duration())
+	for _, conf := range configs {
+		key, err := buildJWTKey(conf)
+		if err != nil {
+			return nil, err
+		}
+		if err = keyring.Add(key); err != nil {
+			return nil, err
+		}
+	}
+
+	if kid := g.Cfg().MustGet(ctx, "jwt.activeKid").String(); kid != "" {
+		if err := keyring.SetActive(kid); err != nil {
+			return nil, gerror.Wrapf(err, "设置JWT签发密钥[%s]失败", kid)
+		}
+	}
+	if keyring.ActiveKid() == "" {
+		return nil, gerror.New("没有可用于签发的JWT密钥")
+	}
+	return keyring, nil
+}
+
+// buildJWTKey 根据配置构建签名密钥
+func buildJWTKey(conf *jwtKeyConfig) (*jwt.Key, error) {
+	key := &jwt.Key{Kid: conf.Kid, Alg: conf.Alg}
+	if key.Alg == "" {
+		key.Alg = jwt.AlgHS256
+	}
+
+	if key.Alg == jwt.AlgHS256 {
+		if conf.Secret == "" {
+			return nil, gerror.Newf("密钥[%s]未配置secret，请生成至少32字节的随机密钥", conf.Kid)
+		}
+		key.Secret = []byte(conf.Secret)
+		return key, nil
+	}
+
+	privatePem := conf.PrivateKey
+	if conf.PrivateKeyFile != "" {
+		privatePem = gfile.GetContents(conf.PrivateKeyFile)
+		if privatePem == "" {
+			return nil, gerror.Newf("密钥[%s]私钥文件不存在或为空: %s", conf.Kid, conf.PrivateKeyFile)
+		}
+	}
+	if privatePem != "" {
+		signer, err := jwt.ParsePrivateKeyPEM([]byte(privatePem))
+		if err != nil {
+			return nil, gerror.Wrapf(err, "密钥[%s]", conf.Kid)
+		}
+		key.PrivateKey = signer
+		return key, nil
+	}
+
+	// 仅配置公钥的密钥只用于验签，通常是已下线但签发的令牌尚未过期的旧密钥
+	publicPem := conf.PublicKey
+	if conf.PublicKeyFile != "" {
+		publicPem = gfile.GetContents(conf.PublicKeyFile)
+	}
+	if publicPem == "" {
+		return nil, gerror.Newf("密钥[%s]未配置私钥或公钥", conf.Kid)
+	}
+	pub, err := jwt.ParsePublicKeyPEM([]byte(publicPem))
+	if err != nil {
+		return nil, gerror.Wrapf(err, "密钥[%s]", conf.Kid)
+	}
+	key.PublicKey = pub
+	return key, nil
+}
+
+// GenerateJWTToken 生成JWT Token
+func GenerateJWTToken(ctx context.Context, payload *JWTPayload) (string, error) {
+	keyring, err := JWTKeyring(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// 设置签发时间和过期时间
+	now := time.Now()
+	payload.Iat = now.Unix()
+	payload.IatMs = now.UnixMilli()
+	if payload.Exp == 0 {
+		payload.Exp = time.Now().Add(g.Cfg().MustGet(ctx, "jwt.expire", "24h").Duration()).Unix() // 默认24小时过期
+	}
+	if payload.Jti == "" {
+		payload.Jti = guid.S()
+	}
+	if payload.Iss == "" {
+		payload.Iss = g.Cfg().MustGet(ctx, "jwt.issuer", AppName(ctx)).String()
+	}
+	if payload.Sub == "" && payload.UserId > 0 {
+		payload.Sub = g.NewVar(payload.UserId).String()
+	}
+
+	return keyring.Sign(payload)
+}
+
+// ParseJWTToken 解析JWT Token
+func ParseJWTToken(ctx context.Context, token string) (*JWTPayload, error) {
+	keyring, err := JWTKeyring(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload JWTPayload
+	if _, err = keyring.Parse(token, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// JWKS 获取用于公开的JWT公钥集合
+func JWKS(ctx context.Context) (*jwt.JWKSet, error) {
+	keyring, err := JWTKeyring(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return keyring.JWKS(), nil
+}
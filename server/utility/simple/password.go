@@ -0,0 +1,54 @@
+// Package simple
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package simple
+
+import (
+	"client-app/utility/encrypt"
+	"context"
+	"sync"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+var passwordHasherOnce sync.Once
+
+// initPasswordHasher 根据配置初始化密码哈希算法
+func initPasswordHasher(ctx context.Context) {
+	passwordHasherOnce.Do(func() {
+		argon := encrypt.NewArgon2idHasher()
+		argon.Memory = g.Cfg().MustGet(ctx, "password.argon2.memory", argon.Memory).Uint32()
+		argon.Time = g.Cfg().MustGet(ctx, "password.argon2.time", argon.Time).Uint32()
+		argon.Threads = g.Cfg().MustGet(ctx, "password.argon2.threads", argon.Threads).Uint8()
+		encrypt.RegisterPasswordHasher(argon)
+
+		cost := g.Cfg().MustGet(ctx, "password.bcrypt.cost", 10).Int()
+		encrypt.RegisterPasswordHasher(encrypt.NewBcryptHasher(cost))
+
+		alg := g.Cfg().MustGet(ctx, "password.hasher", encrypt.PasswordAlgArgon2id).String()
+		if err := encrypt.SetDefaultPasswordHasher(alg); err != nil {
+			g.Log().Warningf(ctx, "%v，使用默认算法: %s", err, encrypt.PasswordAlgArgon2id)
+		}
+	})
+}
+
+// HashPassword 使用配置的算法生成密码哈希，password 为明文
+func HashPassword(ctx context.Context, password string) (string, error) {
+	initPasswordHasher(ctx)
+	return encrypt.HashPassword(password)
+}
+
+// CheckPassword 检查密码
+// password 为客户端传输加密解密后的明文，needsRehash 为 true 时调用方应使用明文密码重新生成哈希
+func CheckPassword(ctx context.Context, password, salt, hash string) (needsRehash bool, err error) {
+	initPasswordHasher(ctx)
+
+	ok, needsRehash := encrypt.VerifyPassword(password, hash, salt)
+	if !ok {
+		return false, gerror.New("用户密码不正确")
+	}
+	return needsRehash, nil
+}
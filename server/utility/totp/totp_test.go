@@ -0,0 +1,68 @@
+// Package totp_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package totp_test
+
+import (
+	"client-app/utility/totp"
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+// RFC 6238 附录B SHA1 测试向量，取后6位
+func TestGenerateCode_RFC6238(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+		cases := map[int64]string{
+			59:          "287082",
+			1111111109:  "081804",
+			1111111111:  "050471",
+			1234567890:  "005924",
+			2000000000:  "279037",
+			20000000000: "353130",
+		}
+		for ts, want := range cases {
+			code, err := totp.GenerateCode(secret, time.Unix(ts, 0))
+			t.AssertNil(err)
+			t.Assert(code, want)
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		secret, err := totp.GenerateSecret()
+		t.AssertNil(err)
+
+		now := time.Now()
+		code, err := totp.GenerateCode(secret, now.Add(-totp.Period*time.Second))
+		t.AssertNil(err)
+
+		_, ok := totp.Validate(secret, code, now, 0)
+		t.Assert(ok, false)
+		step, ok := totp.Validate(secret, code, now, 1)
+		t.Assert(ok, true)
+		t.Assert(step, now.Unix()/totp.Period-1)
+
+		_, ok = totp.Validate(secret, "12345", now, 1)
+		t.Assert(ok, false)
+	})
+}
+
+func TestURI(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		uri := totp.URI("hotgo", "admin", "JBSWY3DPEHPK3PXP")
+		t.Assert(strings.HasPrefix(uri, "otpauth://totp/hotgo:admin?"), true)
+		t.Assert(strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP"), true)
+
+		img, err := totp.QRCodeImage(uri, 200)
+		t.AssertNil(err)
+		t.Assert(strings.HasPrefix(img, "data:image/png;base64,"), true)
+	})
+}
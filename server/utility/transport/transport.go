@@ -0,0 +1,263 @@
+// Package transport
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package transport
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// 加密算法
+const (
+	// AlgRSAOAEP RSA-OAEP，哈希算法SHA-256，浏览器可直接使用 WebCrypto 加密
+	AlgRSAOAEP = "RSA-OAEP-256"
+	// AlgX25519 客户端生成临时X25519密钥与服务端协商，HKDF-SHA256派生AES-256-GCM密钥
+	// 密文格式：临时公钥(32字节) + nonce(12字节) + 密文，附加数据为密钥ID
+	AlgX25519 = "X25519"
+)
+
+const (
+	rsaKeyBits   = 2048
+	x25519Info   = "client-app transport x25519"
+	x25519KeyLen = 32
+)
+
+var (
+	ErrUnsupportedAlg   = errors.New("不支持的加密算法")
+	ErrInvalidKey       = errors.New("密钥格式错误")
+	ErrInvalidEnvelope  = errors.New("加密数据格式错误")
+	ErrDecryptFailed    = errors.New("解密失败")
+	ErrInvalidPublicKey = errors.New("公钥格式错误")
+)
+
+// Key 传输加密密钥，公钥下发给客户端，私钥只保存在服务端
+type Key struct {
+	Kid       string    // 密钥ID
+	Alg       string    // 加密算法
+	CreatedAt time.Time // 创建时间
+	ExpiresAt time.Time // 过期时间，过期后不能再解密
+
+	rsaKey  *rsa.PrivateKey
+	ecdhKey *ecdh.PrivateKey
+}
+
+// Envelope 客户端提交的加密数据
+type Envelope struct {
+	Kid        string `json:"kid"`        // 密钥ID
+	Ciphertext string `json:"ciphertext"` // base64编码的密文
+}
+
+// GenerateKey 生成指定算法的密钥
+func GenerateKey(kid, alg string, createdAt, expiresAt time.Time) (*Key, error) {
+	key := &Key{Kid: kid, Alg: alg, CreatedAt: createdAt, ExpiresAt: expiresAt}
+	var err error
+	switch alg {
+	case AlgRSAOAEP:
+		key.rsaKey, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgX25519:
+		key.ecdhKey, err = ecdh.X25519().GenerateKey(rand.Reader)
+	default:
+		return nil, ErrUnsupportedAlg
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ParseKey 从 MarshalPrivateKey 的结果恢复密钥，用于集群节点间共享密钥
+func ParseKey(kid, alg, privateKey string, createdAt, expiresAt time.Time) (*Key, error) {
+	der, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	key := &Key{Kid: kid, Alg: alg, CreatedAt: createdAt, ExpiresAt: expiresAt}
+	switch alg {
+	case AlgRSAOAEP:
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, ErrInvalidKey
+		}
+		key.rsaKey = rsaKey
+	case AlgX25519:
+		ecdhKey, ok := parsed.(*ecdh.PrivateKey)
+		if !ok || ecdhKey.Curve() != ecdh.X25519() {
+			return nil, ErrInvalidKey
+		}
+		key.ecdhKey = ecdhKey
+	default:
+		return nil, ErrUnsupportedAlg
+	}
+	return key, nil
+}
+
+// MarshalPrivateKey 导出私钥（base64编码的PKCS#8）
+func (k *Key) MarshalPrivateKey() (string, error) {
+	var der []byte
+	var err error
+	if k.rsaKey != nil {
+		der, err = x509.MarshalPKCS8PrivateKey(k.rsaKey)
+	} else {
+		der, err = x509.MarshalPKCS8PrivateKey(k.ecdhKey)
+	}
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(der), nil
+}
+
+// PublicKey 导出公钥（base64编码的SPKI），WebCrypto 可通过 importKey("spki") 导入
+func (k *Key) PublicKey() (string, error) {
+	var der []byte
+	var err error
+	if k.rsaKey != nil {
+		der, err = x509.MarshalPKIXPublicKey(&k.rsaKey.PublicKey)
+	} else {
+		der, err = x509.MarshalPKIXPublicKey(k.ecdhKey.PublicKey())
+	}
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(der), nil
+}
+
+// IsExpired 判断密钥是否已过期
+func (k *Key) IsExpired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && now.After(k.ExpiresAt)
+}
+
+// Decrypt 解密base64编码的密文
+func (k *Key) Decrypt(ciphertext string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, ErrInvalidEnvelope
+	}
+
+	switch k.Alg {
+	case AlgRSAOAEP:
+		plain, err := rsa.DecryptOAEP(sha256.New(), nil, k.rsaKey, data, nil)
+		if err != nil {
+			return nil, ErrDecryptFailed
+		}
+		return plain, nil
+	case AlgX25519:
+		if len(data) < x25519KeyLen+12 {
+			return nil, ErrInvalidEnvelope
+		}
+		peer, err := ecdh.X25519().NewPublicKey(data[:x25519KeyLen])
+		if err != nil {
+			return nil, ErrInvalidEnvelope
+		}
+		gcm, err := x25519Cipher(k.ecdhKey, peer, k.ecdhKey.PublicKey())
+		if err != nil {
+			return nil, ErrDecryptFailed
+		}
+		nonce := data[x25519KeyLen : x25519KeyLen+gcm.NonceSize()]
+		plain, err := gcm.Open(nil, nonce, data[x25519KeyLen+gcm.NonceSize():], []byte(k.Kid))
+		if err != nil {
+			return nil, ErrDecryptFailed
+		}
+		return plain, nil
+	default:
+		return nil, ErrUnsupportedAlg
+	}
+}
+
+// Encrypt 使用公钥加密，供Go客户端和测试使用，浏览器端按相同格式实现
+func Encrypt(kid, alg, publicKey string, plaintext []byte) (string, error) {
+	der, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return "", ErrInvalidPublicKey
+	}
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return "", ErrInvalidPublicKey
+	}
+
+	switch alg {
+	case AlgRSAOAEP:
+		pub, ok := parsed.(*rsa.PublicKey)
+		if !ok {
+			return "", ErrInvalidPublicKey
+		}
+		data, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, plaintext, nil)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	case AlgX25519:
+		pub, ok := parsed.(*ecdh.PublicKey)
+		if !ok || pub.Curve() != ecdh.X25519() {
+			return "", ErrInvalidPublicKey
+		}
+		ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return "", err
+		}
+		gcm, err := x25519Cipher(ephemeral, pub, pub)
+		if err != nil {
+			return "", err
+		}
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err = rand.Read(nonce); err != nil {
+			return "", err
+		}
+		data := append(ephemeral.PublicKey().Bytes(), nonce...)
+		data = gcm.Seal(data, nonce, plaintext, []byte(kid))
+		return base64.StdEncoding.EncodeToString(data), nil
+	default:
+		return "", ErrUnsupportedAlg
+	}
+}
+
+// x25519Cipher 协商共享密钥并派生AES-256-GCM，serverPub 作为HKDF盐值绑定服务端密钥
+func x25519Cipher(private *ecdh.PrivateKey, peer, serverPub *ecdh.PublicKey) (cipher.AEAD, error) {
+	shared, err := private.ECDH(peer)
+	if err != nil {
+		return nil, err
+	}
+	aesKey := make([]byte, 32)
+	if _, err = io.ReadFull(hkdf.New(sha256.New, shared, serverPub.Bytes(), []byte(x25519Info)), aesKey); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ParseEnvelope 解析客户端提交的加密数据
+// 密码字段可以直接提交 {"kid": "...", "ciphertext": "..."} 对象，绑定到字符串字段后为JSON文本
+func ParseEnvelope(text string) (*Envelope, bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "{") {
+		return nil, false
+	}
+	var envelope Envelope
+	if err := json.Unmarshal([]byte(text), &envelope); err != nil || envelope.Kid == "" || envelope.Ciphertext == "" {
+		return nil, false
+	}
+	return &envelope, true
+}
@@ -0,0 +1,76 @@
+// Package transport_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package transport_test
+
+import (
+	"client-app/utility/transport"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		now := time.Now()
+		for _, alg := range []string{transport.AlgRSAOAEP, transport.AlgX25519} {
+			key, err := transport.GenerateKey("k1", alg, now, now.Add(time.Hour))
+			t.AssertNil(err)
+
+			pub, err := key.PublicKey()
+			t.AssertNil(err)
+			ciphertext, err := transport.Encrypt(key.Kid, alg, pub, []byte("Secret123"))
+			t.AssertNil(err)
+
+			plain, err := key.Decrypt(ciphertext)
+			t.AssertNil(err)
+			t.Assert(string(plain), "Secret123")
+
+			// 私钥导出后恢复，模拟集群节点共享密钥
+			private, err := key.MarshalPrivateKey()
+			t.AssertNil(err)
+			restored, err := transport.ParseKey(key.Kid, alg, private, key.CreatedAt, key.ExpiresAt)
+			t.AssertNil(err)
+			plain, err = restored.Decrypt(ciphertext)
+			t.AssertNil(err)
+			t.Assert(string(plain), "Secret123")
+		}
+	})
+}
+
+func TestDecrypt_WrongKey(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		now := time.Now()
+		key1, _ := transport.GenerateKey("k1", transport.AlgX25519, now, now.Add(time.Hour))
+		key2, _ := transport.GenerateKey("k2", transport.AlgX25519, now, now.Add(time.Hour))
+
+		pub, _ := key1.PublicKey()
+		ciphertext, err := transport.Encrypt("k1", transport.AlgX25519, pub, []byte("Secret123"))
+		t.AssertNil(err)
+
+		_, err = key2.Decrypt(ciphertext)
+		t.AssertNE(err, nil)
+
+		// 密钥ID作为附加数据，冒用其他密钥ID加密的数据无法解密
+		ciphertext, _ = transport.Encrypt("k2", transport.AlgX25519, pub, []byte("Secret123"))
+		_, err = key1.Decrypt(ciphertext)
+		t.AssertNE(err, nil)
+	})
+}
+
+func TestParseEnvelope(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		envelope, ok := transport.ParseEnvelope(`{"kid":"k1","ciphertext":"abc"}`)
+		t.Assert(ok, true)
+		t.Assert(envelope.Kid, "k1")
+		t.Assert(envelope.Ciphertext, "abc")
+
+		_, ok = transport.ParseEnvelope("bGVnYWN5")
+		t.Assert(ok, false)
+		_, ok = transport.ParseEnvelope(`{"kid":""}`)
+		t.Assert(ok, false)
+	})
+}
@@ -0,0 +1,83 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// 认证器数据标志位
+const (
+	FlagUserPresent  byte = 0x01 // UP 用户在场
+	FlagUserVerified byte = 0x04 // UV 用户已验证（PIN、生物识别）
+	FlagBackupElig   byte = 0x08 // BE 凭证可同步备份
+	FlagBackupState  byte = 0x10 // BS 凭证已备份
+	FlagAttested     byte = 0x40 // AT 包含凭证数据
+	FlagExtensions   byte = 0x80 // ED 包含扩展数据
+)
+
+var errAuthData = errors.New("认证器数据格式错误")
+
+// AuthenticatorData 认证器数据
+type AuthenticatorData struct {
+	RPIDHash     []byte
+	Flags        byte
+	SignCount    uint32
+	AAGUID       []byte // 认证器型号标识，仅注册时存在
+	CredentialID []byte // 凭证ID，仅注册时存在
+	PublicKey    []byte // COSE格式公钥，仅注册时存在
+}
+
+// Has 是否设置了指定标志位
+func (a *AuthenticatorData) Has(flag byte) bool {
+	return a.Flags&flag == flag
+}
+
+// ParseAuthenticatorData 解析认证器数据
+// 格式：rpIdHash(32) | flags(1) | signCount(4) | [aaguid(16) | credIdLen(2) | credId | COSE公钥] | [扩展]
+func ParseAuthenticatorData(data []byte) (*AuthenticatorData, error) {
+	if len(data) < 37 {
+		return nil, errAuthData
+	}
+
+	ad := &AuthenticatorData{
+		RPIDHash:  data[:32],
+		Flags:     data[32],
+		SignCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rest := data[37:]
+
+	if ad.Has(FlagAttested) {
+		if len(rest) < 18 {
+			return nil, errAuthData
+		}
+		ad.AAGUID = rest[:16]
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLen == 0 || idLen > 1023 || len(rest) < idLen {
+			return nil, errAuthData
+		}
+		ad.CredentialID = rest[:idLen]
+		rest = rest[idLen:]
+
+		// 公钥长度不固定，按CBOR解码后的剩余数据确定边界
+		_, after, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, errAuthData
+		}
+		ad.PublicKey = rest[:len(rest)-len(after)]
+		rest = after
+	}
+
+	if ad.Has(FlagExtensions) {
+		_, after, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, errAuthData
+		}
+		rest = after
+	}
+
+	if len(rest) != 0 {
+		return nil, errAuthData
+	}
+	return ad, nil
+}
@@ -0,0 +1,171 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var errCBOR = errors.New("CBOR数据格式错误")
+
+// maxCBORDepth 限制嵌套深度，避免恶意数据导致栈溢出
+const maxCBORDepth = 16
+
+// decodeCBOR 解码一个CBOR数据项，返回解码结果和剩余数据
+// 只支持WebAuthn用到的类型：整数、字节串、文本、数组、映射和简单值，不支持不定长编码
+func decodeCBOR(data []byte) (any, []byte, error) {
+	return decodeCBORItem(data, 0)
+}
+
+func decodeCBORItem(data []byte, depth int) (any, []byte, error) {
+	if depth > maxCBORDepth || len(data) == 0 {
+		return nil, nil, errCBOR
+	}
+
+	major := data[0] >> 5
+	info := data[0] & 0x1f
+	data = data[1:]
+
+	// 简单值和浮点数
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, data, nil
+		case 21:
+			return true, data, nil
+		case 22, 23:
+			return nil, data, nil
+		case 25:
+			return skipCBOR(data, 2)
+		case 26:
+			return skipCBOR(data, 4)
+		case 27:
+			return skipCBOR(data, 8)
+		default:
+			return nil, nil, errCBOR
+		}
+	}
+
+	n, data, err := readCBORLength(info, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0:
+		return n, data, nil
+	case 1:
+		if n > 1<<62 {
+			return nil, nil, errCBOR
+		}
+		return -1 - int64(n), data, nil
+	case 2, 3:
+		if uint64(len(data)) < n {
+			return nil, nil, errCBOR
+		}
+		value := data[:n]
+		if major == 3 {
+			return string(value), data[n:], nil
+		}
+		return append([]byte(nil), value...), data[n:], nil
+	case 4:
+		if n > uint64(len(data)) {
+			return nil, nil, errCBOR
+		}
+		list := make([]any, 0, n)
+		for i := uint64(0); i < n; i++ {
+			var item any
+			if item, data, err = decodeCBORItem(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+			list = append(list, item)
+		}
+		return list, data, nil
+	case 5:
+		if n > uint64(len(data)) {
+			return nil, nil, errCBOR
+		}
+		m := make(map[any]any, n)
+		for i := uint64(0); i < n; i++ {
+			var key, value any
+			if key, data, err = decodeCBORItem(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+			if value, data, err = decodeCBORItem(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case uint64, int64, string:
+				m[key] = value
+			default:
+				return nil, nil, errCBOR
+			}
+		}
+		return m, data, nil
+	case 6:
+		// 标签只保留被标记的数据
+		return decodeCBORItem(data, depth+1)
+	default:
+		return nil, nil, errCBOR
+	}
+}
+
+// readCBORLength 读取附加信息表示的长度或整数值
+func readCBORLength(info byte, data []byte) (uint64, []byte, error) {
+	switch {
+	case info < 24:
+		return uint64(info), data, nil
+	case info == 24 && len(data) >= 1:
+		return uint64(data[0]), data[1:], nil
+	case info == 25 && len(data) >= 2:
+		return uint64(binary.BigEndian.Uint16(data)), data[2:], nil
+	case info == 26 && len(data) >= 4:
+		return uint64(binary.BigEndian.Uint32(data)), data[4:], nil
+	case info == 27 && len(data) >= 8:
+		return binary.BigEndian.Uint64(data), data[8:], nil
+	default:
+		return 0, nil, errCBOR
+	}
+}
+
+func skipCBOR(data []byte, n int) (any, []byte, error) {
+	if len(data) < n {
+		return nil, nil, errCBOR
+	}
+	return nil, data[n:], nil
+}
+
+// cborInt 读取映射中的整数键值
+func cborInt(m map[any]any, key int64) (int64, bool) {
+	var value any
+	var ok bool
+	if key >= 0 {
+		value, ok = m[uint64(key)]
+	} else {
+		value, ok = m[key]
+	}
+	if !ok {
+		return 0, false
+	}
+	switch v := value.(type) {
+	case uint64:
+		if v > 1<<62 {
+			return 0, false
+		}
+		return int64(v), true
+	case int64:
+		return v, true
+	}
+	return 0, false
+}
+
+// cborBytes 读取映射中的字节串键值
+func cborBytes(m map[any]any, key int64) ([]byte, bool) {
+	var value any
+	if key >= 0 {
+		value = m[uint64(key)]
+	} else {
+		value = m[key]
+	}
+	b, ok := value.([]byte)
+	return b, ok
+}
@@ -0,0 +1,117 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// COSE算法标识
+const (
+	AlgES256 int64 = -7   // ECDSA P-256 + SHA-256
+	AlgEdDSA int64 = -8   // Ed25519
+	AlgRS256 int64 = -257 // RSASSA-PKCS1-v1_5 + SHA-256
+)
+
+// SupportedAlgorithms 支持的签名算法，按优先级排列
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+// COSE密钥参数
+const (
+	coseKty    = 1
+	coseAlg    = 3
+	coseCrv    = -1
+	coseX      = -2
+	coseY      = -3
+	coseRSAN   = -1
+	coseRSAE   = -2
+	ktyOKP     = 1
+	ktyEC2     = 2
+	ktyRSA     = 3
+	crvP256    = 1
+	crvEd25519 = 6
+)
+
+var (
+	ErrUnsupportedKey = errors.New("不支持的公钥类型")
+	ErrSignature      = errors.New("签名验证失败")
+)
+
+// PublicKey COSE格式的凭证公钥
+type PublicKey struct {
+	Alg int64
+	key crypto.PublicKey
+}
+
+// ParsePublicKey 解析COSE_Key编码的公钥
+func ParsePublicKey(data []byte) (*PublicKey, error) {
+	value, rest, err := decodeCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errCBOR
+	}
+	m, ok := value.(map[any]any)
+	if !ok {
+		return nil, errCBOR
+	}
+
+	kty, _ := cborInt(m, coseKty)
+	alg, _ := cborInt(m, coseAlg)
+	switch {
+	case kty == ktyEC2 && alg == AlgES256:
+		crv, _ := cborInt(m, coseCrv)
+		x, okX := cborBytes(m, coseX)
+		y, okY := cborBytes(m, coseY)
+		if crv != crvP256 || !okX || !okY || len(x) != 32 || len(y) != 32 {
+			return nil, ErrUnsupportedKey
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, ErrUnsupportedKey
+		}
+		return &PublicKey{Alg: alg, key: key}, nil
+	case kty == ktyOKP && alg == AlgEdDSA:
+		crv, _ := cborInt(m, coseCrv)
+		x, okX := cborBytes(m, coseX)
+		if crv != crvEd25519 || !okX || len(x) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedKey
+		}
+		return &PublicKey{Alg: alg, key: ed25519.PublicKey(x)}, nil
+	case kty == ktyRSA && alg == AlgRS256:
+		n, okN := cborBytes(m, coseRSAN)
+		e, okE := cborBytes(m, coseRSAE)
+		if !okN || !okE || len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, ErrUnsupportedKey
+		}
+		return &PublicKey{Alg: alg, key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}}, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// Verify 校验签名
+func (k *PublicKey) Verify(data, sig []byte) error {
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if ecdsa.VerifyASN1(key, digest[:], sig) {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if ed25519.Verify(key, data, sig) {
+			return nil
+		}
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) == nil {
+			return nil
+		}
+	}
+	return ErrSignature
+}
@@ -0,0 +1,225 @@
+// Package webauthn_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package webauthn_test
+
+import (
+	"client-app/utility/webauthn"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://example.com"
+)
+
+// softAuthenticator 软件认证器，模拟浏览器和认证器生成注册、认证响应
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialId []byte
+	signCount    uint32
+	origin       string
+}
+
+func newSoftAuthenticator() *softAuthenticator {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return &softAuthenticator{key: key, credentialId: id, origin: testOrigin}
+}
+
+func (a *softAuthenticator) clientData(typ string, challenge []byte) []byte {
+	b, _ := json.Marshal(map[string]any{
+		"type":      typ,
+		"challenge": webauthn.EncodeBase64(challenge),
+		"origin":    a.origin,
+	})
+	return b
+}
+
+func (a *softAuthenticator) authData(flags byte, attested bool) []byte {
+	rpIdHash := sha256.Sum256([]byte(testRPID))
+	data := append([]byte{}, rpIdHash[:]...)
+	if attested {
+		flags |= webauthn.FlagAttested
+	}
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if attested {
+		data = append(data, make([]byte, 16)...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialId)))
+		data = append(data, a.credentialId...)
+		data = append(data, a.coseKey()...)
+	}
+	return data
+}
+
+func (a *softAuthenticator) coseKey() []byte {
+	x := a.key.PublicKey.X.FillBytes(make([]byte, 32))
+	y := a.key.PublicKey.Y.FillBytes(make([]byte, 32))
+	return cborMap(
+		cborUint(1), cborUint(2), // kty: EC2
+		cborUint(3), cborNeg(-7), // alg: ES256
+		cborNeg(-1), cborUint(1), // crv: P-256
+		cborNeg(-2), cborBytes(x),
+		cborNeg(-3), cborBytes(y),
+	)
+}
+
+func (a *softAuthenticator) create(challenge []byte) *webauthn.AttestationResponse {
+	attObj := cborMap(
+		cborText("fmt"), cborText("none"),
+		cborText("attStmt"), cborMap(),
+		cborText("authData"), cborBytes(a.authData(webauthn.FlagUserPresent|webauthn.FlagUserVerified, true)),
+	)
+	return &webauthn.AttestationResponse{
+		ClientDataJSON:    a.clientData("webauthn.create", challenge),
+		AttestationObject: attObj,
+	}
+}
+
+func (a *softAuthenticator) get(challenge []byte) *webauthn.AssertionResponse {
+	a.signCount++
+	authData := a.authData(webauthn.FlagUserPresent|webauthn.FlagUserVerified, false)
+	clientData := a.clientData("webauthn.get", challenge)
+	hash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), hash[:]...))
+	sig, _ := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	return &webauthn.AssertionResponse{
+		CredentialId:      a.credentialId,
+		ClientDataJSON:    clientData,
+		AuthenticatorData: authData,
+		Signature:         sig,
+	}
+}
+
+func cborHead(major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return []byte{major<<5 | byte(n)}
+	case n < 256:
+		return []byte{major<<5 | 24, byte(n)}
+	default:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(n))
+	}
+}
+
+func cborUint(n uint64) []byte  { return cborHead(0, n) }
+func cborNeg(n int64) []byte    { return cborHead(1, uint64(-1-n)) }
+func cborBytes(b []byte) []byte { return append(cborHead(2, uint64(len(b))), b...) }
+func cborText(s string) []byte  { return append(cborHead(3, uint64(len(s))), s...) }
+
+func cborMap(kv ...[]byte) []byte {
+	out := cborHead(5, uint64(len(kv)/2))
+	for _, b := range kv {
+		out = append(out, b...)
+	}
+	return out
+}
+
+func newRelyingParty(t *gtest.T) *webauthn.RelyingParty {
+	rp, err := webauthn.New(webauthn.Config{RPID: testRPID, Origins: []string{testOrigin}, UserVerification: webauthn.UVRequired})
+	t.AssertNil(err)
+	return rp
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		rp := newRelyingParty(t)
+		auth := newSoftAuthenticator()
+
+		challenge, _ := webauthn.NewChallenge()
+		cred, err := rp.VerifyRegistration(challenge, auth.create(challenge))
+		t.AssertNil(err)
+		t.Assert(cred.Id, auth.credentialId)
+		t.Assert(cred.Alg, webauthn.AlgES256)
+		t.Assert(cred.UserVerified, true)
+
+		challenge, _ = webauthn.NewChallenge()
+		assertion, err := rp.VerifyAssertion(challenge, auth.get(challenge), cred.Id, cred.PublicKey, cred.SignCount)
+		t.AssertNil(err)
+		t.Assert(assertion.SignCount, 1)
+
+		// 挑战码不匹配
+		other, _ := webauthn.NewChallenge()
+		_, err = rp.VerifyAssertion(other, auth.get(challenge), cred.Id, cred.PublicKey, assertion.SignCount)
+		t.Assert(err, webauthn.ErrChallenge)
+
+		// 篡改签名
+		resp := auth.get(challenge)
+		resp.Signature[len(resp.Signature)-1] ^= 0xff
+		_, err = rp.VerifyAssertion(challenge, resp, cred.Id, cred.PublicKey, assertion.SignCount)
+		t.AssertNE(err, nil)
+	})
+}
+
+func TestSignCountRegression(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		rp := newRelyingParty(t)
+		auth := newSoftAuthenticator()
+
+		challenge, _ := webauthn.NewChallenge()
+		cred, err := rp.VerifyRegistration(challenge, auth.create(challenge))
+		t.AssertNil(err)
+
+		auth.signCount = 10
+		assertion, err := rp.VerifyAssertion(challenge, auth.get(challenge), cred.Id, cred.PublicKey, 0)
+		t.AssertNil(err)
+		t.Assert(assertion.SignCount, 11)
+
+		// 克隆的认证器计数器落后于服务端记录
+		auth.signCount = 5
+		_, err = rp.VerifyAssertion(challenge, auth.get(challenge), cred.Id, cred.PublicKey, assertion.SignCount)
+		t.Assert(err, webauthn.ErrSignCount)
+	})
+}
+
+func TestVerifyRegistration_Invalid(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		rp := newRelyingParty(t)
+		challenge, _ := webauthn.NewChallenge()
+
+		auth := newSoftAuthenticator()
+		auth.origin = "https://evil.example.net"
+		_, err := rp.VerifyRegistration(challenge, auth.create(challenge))
+		t.Assert(err, webauthn.ErrOrigin)
+
+		// 注册响应不能用于认证
+		auth = newSoftAuthenticator()
+		resp := auth.create(challenge)
+		_, err = rp.VerifyAssertion(challenge, &webauthn.AssertionResponse{
+			CredentialId:      auth.credentialId,
+			ClientDataJSON:    resp.ClientDataJSON,
+			AuthenticatorData: auth.authData(webauthn.FlagUserPresent, false),
+		}, auth.credentialId, auth.coseKey(), 0)
+		t.Assert(err, webauthn.ErrClientData)
+
+		// 要求用户验证时缺少UV标志
+		attObj := cborMap(
+			cborText("fmt"), cborText("none"),
+			cborText("attStmt"), cborMap(),
+			cborText("authData"), cborBytes(auth.authData(webauthn.FlagUserPresent, true)),
+		)
+		_, err = rp.VerifyRegistration(challenge, &webauthn.AttestationResponse{
+			ClientDataJSON:    auth.clientData("webauthn.create", challenge),
+			AttestationObject: attObj,
+		})
+		t.Assert(err, webauthn.ErrUserVerify)
+
+		_, err = rp.VerifyRegistration(challenge, &webauthn.AttestationResponse{
+			ClientDataJSON:    auth.clientData("webauthn.create", challenge),
+			AttestationObject: []byte{0xa1, 0x01},
+		})
+		t.Assert(err, webauthn.ErrAttestation)
+	})
+}